@@ -8,6 +8,7 @@ import (
 	"backend/pkg/database"
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -22,6 +23,18 @@ func main() {
 	// Set Gin mode
 	gin.SetMode(os.Getenv("GIN_MODE"))
 
+	// Set the system default core/secondary factor ratio (defaults to 60/40)
+	if core, secondary := os.Getenv("DEFAULT_CORE_FACTOR_PERSEN"), os.Getenv("DEFAULT_SECONDARY_FACTOR_PERSEN"); core != "" || secondary != "" {
+		coreValue, errCore := strconv.ParseFloat(core, 64)
+		secondaryValue, errSecondary := strconv.ParseFloat(secondary, 64)
+		if errCore != nil || errSecondary != nil {
+			log.Fatal("DEFAULT_CORE_FACTOR_PERSEN and DEFAULT_SECONDARY_FACTOR_PERSEN must both be numbers")
+		}
+		if err := services.SetDefaultFactorRatio(coreValue, secondaryValue); err != nil {
+			log.Fatal("Invalid default factor ratio:", err)
+		}
+	}
+
 	// Initialize database connection
 	_, err := database.ConnectDB()
 	if err != nil {
@@ -43,11 +56,19 @@ func main() {
 	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(database.DB)
 	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(database.DB)
 	profileMatchResultRepo := repositories.NewProfileMatchResultRepository(database.DB)
+	gapWeightTableRepo := repositories.NewGapWeightTableRepository(database.DB)
+	calculationRunRepo := repositories.NewCalculationRunRepository(database.DB)
+	calculationJobRepo := repositories.NewCalculationJobRepository(database.DB)
+	resultStatusHistoryRepo := repositories.NewResultStatusHistoryRepository(database.DB)
+	modelConfigRepo := repositories.NewModelConfigRepository(database.DB)
+	auditLogRepo := repositories.NewAuditLogRepository(database.DB)
 
 	// Initialize services
+	auditSvc := services.NewAuditService(auditLogRepo)
 	authSvc := services.NewAuthService(userRepo)
 	userSvc := services.NewUserService(userRepo)
-	jabatanSvc := services.NewJabatanService(jabatanRepo)
+	jabatanSvc := services.NewJabatanService(jabatanRepo, gapWeightTableRepo)
+	gapWeightTableSvc := services.NewGapWeightTableService(gapWeightTableRepo, jabatanRepo)
 	aspekSvc := services.NewAspekService(aspekRepo)
 	kriteriaSvc := services.NewKriteriaService(kriteriaRepo, aspekRepo)
 	targetProfileSvc := services.NewTargetProfileService(targetProfileRepo, jabatanRepo, kriteriaRepo)
@@ -60,18 +81,39 @@ func main() {
 		tenagaKerjaRepo,
 		profileMatchResultRepo,
 		jabatanRepo,
+		gapWeightTableRepo,
+		calculationRunRepo,
+		resultStatusHistoryRepo,
 	)
+	calculationJobSvc := services.NewCalculationJobService(calculationJobRepo, jabatanRepo, profileMatchingSvc)
+	modelConfigSvc := services.NewModelConfigService(gapWeightTableRepo, aspekRepo, kriteriaRepo, jabatanRepo, targetProfileRepo, modelConfigRepo)
+
+	// Start the background calculation workers (defaults to 2)
+	workers := 2
+	if value := os.Getenv("CALCULATION_WORKERS"); value != "" {
+		workers, err = strconv.Atoi(value)
+		if err != nil {
+			log.Fatal("CALCULATION_WORKERS must be a number")
+		}
+	}
+	if err := calculationJobSvc.Start(workers); err != nil {
+		log.Fatal("Could not start calculation workers:", err)
+	}
 
 	// Initialize controllers
 	authCtrl := controllers.NewAuthController(authSvc)
 	userCtrl := controllers.NewUserController(userSvc)
 	jabatanCtrl := controllers.NewJabatanController(jabatanSvc)
+	gapWeightTableCtrl := controllers.NewGapWeightTableController(gapWeightTableSvc)
 	aspekCtrl := controllers.NewAspekController(aspekSvc)
 	kriteriaCtrl := controllers.NewKriteriaController(kriteriaSvc)
 	targetProfileCtrl := controllers.NewTargetProfileController(targetProfileSvc)
 	tenagaKerjaCtrl := controllers.NewTenagaKerjaController(tenagaKerjaSvc)
 	nilaiTenagaKerjaCtrl := controllers.NewNilaiTenagaKerjaController(nilaiTenagaKerjaSvc)
 	profileMatchingCtrl := controllers.NewProfileMatchingController(profileMatchingSvc)
+	calculationJobCtrl := controllers.NewCalculationJobController(calculationJobSvc)
+	modelConfigCtrl := controllers.NewModelConfigController(modelConfigSvc)
+	auditLogCtrl := controllers.NewAuditLogController(auditSvc)
 
 	// Public routes
 	router.POST("/api/auth/login", authCtrl.Login)
@@ -94,6 +136,13 @@ func main() {
 		protected.PUT("/jabatan/:id", jabatanCtrl.Update)
 		protected.DELETE("/jabatan/:id", jabatanCtrl.Delete)
 
+		// GAP Weight Tables
+		protected.GET("/gap-weight-tables", gapWeightTableCtrl.GetAll)
+		protected.POST("/gap-weight-tables", gapWeightTableCtrl.Create)
+		protected.GET("/gap-weight-tables/:id", gapWeightTableCtrl.GetByID)
+		protected.PUT("/gap-weight-tables/:id", gapWeightTableCtrl.Update)
+		protected.DELETE("/gap-weight-tables/:id", gapWeightTableCtrl.Delete)
+
 		// Aspek
 		protected.GET("/aspek", aspekCtrl.GetAll)
 		protected.POST("/aspek", aspekCtrl.Create)
@@ -118,6 +167,7 @@ func main() {
 		// Tenaga Kerja
 		protected.GET("/tenaga-kerja", tenagaKerjaCtrl.GetAll)
 		protected.POST("/tenaga-kerja", tenagaKerjaCtrl.Create)
+		protected.POST("/tenaga-kerja/import", tenagaKerjaCtrl.Import)
 		protected.GET("/tenaga-kerja/:id", tenagaKerjaCtrl.GetByID)
 		protected.PUT("/tenaga-kerja/:id", tenagaKerjaCtrl.Update)
 		protected.DELETE("/tenaga-kerja/:id", tenagaKerjaCtrl.Delete)
@@ -129,10 +179,31 @@ func main() {
 		protected.PUT("/nilai-tenaga-kerja/:id", nilaiTenagaKerjaCtrl.Update)
 		protected.DELETE("/nilai-tenaga-kerja/:id", nilaiTenagaKerjaCtrl.Delete)
 
+		// Model configuration; imported with cmd/import-config
+		protected.GET("/model-config/export", modelConfigCtrl.Export)
+
+		// Audit log of data changes
+		protected.GET("/audit-logs", auditLogCtrl.GetAll)
+
 		// Profile Matching Calculation
 		protected.POST("/profile-matching/calculate", profileMatchingCtrl.Calculate)
+		protected.POST("/profile-matching/sensitivity", profileMatchingCtrl.Sensitivity)
 		protected.GET("/profile-matching/results", profileMatchingCtrl.GetAllResults)
+		protected.GET("/profile-matching/results/export", profileMatchingCtrl.ExportResults)
 		protected.GET("/profile-matching/results/:id", profileMatchingCtrl.GetResultByID)
+		protected.PUT("/profile-matching/results/:id/status", profileMatchingCtrl.UpdateResultStatus)
+		protected.GET("/profile-matching/results/:id/status-history", profileMatchingCtrl.GetStatusHistory)
+		protected.GET("/profile-matching/jabatan/:id/candidates", profileMatchingCtrl.GetCandidates)
+		protected.GET("/profile-matching/jabatan/:id/ineligible", profileMatchingCtrl.GetIneligible)
+		protected.GET("/profile-matching/jabatan/:id/report", profileMatchingCtrl.DecisionReport)
+		protected.GET("/profile-matching/runs", profileMatchingCtrl.GetRuns)
+		protected.GET("/profile-matching/runs/:id", profileMatchingCtrl.GetRunByID)
+		protected.PUT("/profile-matching/runs/:id/official", profileMatchingCtrl.MarkRunOfficial)
+		protected.POST("/profile-matching/compare", profileMatchingCtrl.Compare)
+		protected.POST("/profile-matching/assignments", profileMatchingCtrl.Assign)
+		protected.POST("/profile-matching/jobs", calculationJobCtrl.Submit)
+		protected.GET("/profile-matching/jobs/:id", calculationJobCtrl.GetByID)
+		protected.POST("/profile-matching/jobs/:id/cancel", calculationJobCtrl.Cancel)
 	}
 
 	// Start server
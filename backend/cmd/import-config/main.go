@@ -0,0 +1,85 @@
+// Command import-config applies a competency model configuration exported
+// by GET /api/model-config/export to the database in the environment:
+//
+//	go run ./cmd/import-config -file model-config.json -mode merge -user admin@example.com
+//
+// Records are matched by natural key. The merge mode creates or overwrites
+// the records in the file; replace also deletes those missing from it. The
+// changes are audited as made by the user given by email.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"backend/internal/dto"
+	"backend/internal/repositories"
+	"backend/internal/services"
+	"backend/pkg/database"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	file := flag.String("file", "", "configuration JSON file to import")
+	mode := flag.String("mode", "merge", "merge or replace")
+	dryRun := flag.Bool("dry-run", false, "report the changes without saving them")
+	email := flag.String("user", "", "email of the user running the import, recorded in the audit log")
+	flag.Parse()
+	if *file == "" || *email == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatal("Could not read configuration:", err)
+	}
+	var cfg dto.ModelConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		log.Fatal("Invalid configuration:", err)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file loaded, continuing with environment variables")
+	}
+	db, err := database.ConnectDB()
+	if err != nil {
+		log.Fatal("Could not connect to database:", err)
+	}
+	user, err := repositories.NewUserRepository(db).FindByEmail(*email)
+	if err != nil {
+		log.Fatal("Could not find user ", *email, ": ", err)
+	}
+
+	modelConfigSvc := services.NewModelConfigService(
+		repositories.NewGapWeightTableRepository(db),
+		repositories.NewAspekRepository(db),
+		repositories.NewKriteriaRepository(db),
+		repositories.NewJabatanRepository(db),
+		repositories.NewTargetProfileRepository(db),
+		repositories.NewModelConfigRepository(db),
+	)
+	summary, err := modelConfigSvc.WithActor(&user.ID).Import(&cfg, *mode, *dryRun)
+	if err != nil {
+		log.Fatal("Import failed: ", err)
+	}
+
+	if *dryRun {
+		log.Println("Dry run, nothing was saved")
+	}
+	for _, line := range []struct {
+		kind   string
+		counts dto.ConfigImportCounts
+	}{
+		{"gap weight tables", summary.GapWeightTables},
+		{"aspek", summary.Aspek},
+		{"kriteria", summary.Kriteria},
+		{"jabatan", summary.Jabatan},
+		{"target profiles", summary.TargetProfiles},
+	} {
+		log.Printf("%-18s created %d, updated %d, deleted %d", line.kind, line.counts.Created, line.counts.Updated, line.counts.Deleted)
+	}
+}
@@ -30,10 +30,10 @@ func main() {
 	}
 
 	// Drop tables to ensure fresh seed (be careful in production)
-	db.Migrator().DropTable(&models.ProfileMatchResult{}, &models.NilaiTenagaKerja{}, &models.TenagaKerja{}, &models.TargetProfile{}, &models.Kriteria{}, &models.Aspek{}, &models.Jabatan{}, &models.User{})
+	db.Migrator().DropTable(&models.AuditLog{}, &models.IneligibleResult{}, &models.ResultStatusHistory{}, &models.ProfileMatchResultKriteria{}, &models.ProfileMatchResultAspek{}, &models.ProfileMatchResult{}, &models.CalculationRun{}, &models.CalculationJob{}, &models.NilaiTenagaKerja{}, &models.TenagaKerja{}, &models.TargetProfile{}, &models.Kriteria{}, &models.Aspek{}, &models.Jabatan{}, &models.GapWeightEntry{}, &models.GapWeightTable{}, &models.User{})
 
 	// AutoMigrate again
-	if err := db.AutoMigrate(&models.User{}, &models.Jabatan{}, &models.Aspek{}, &models.Kriteria{}, &models.TargetProfile{}, &models.TenagaKerja{}, &models.NilaiTenagaKerja{}, &models.ProfileMatchResult{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.GapWeightTable{}, &models.GapWeightEntry{}, &models.Jabatan{}, &models.Aspek{}, &models.Kriteria{}, &models.TargetProfile{}, &models.TenagaKerja{}, &models.NilaiTenagaKerja{}, &models.CalculationRun{}, &models.CalculationJob{}, &models.ProfileMatchResult{}, &models.ProfileMatchResultAspek{}, &models.ProfileMatchResultKriteria{}, &models.ResultStatusHistory{}, &models.IneligibleResult{}, &models.AuditLog{}); err != nil {
 		log.Fatal("Could not migrate database:", err)
 	}
 
@@ -63,6 +63,29 @@ func main() {
 	}
 	log.Println("✅ Regular user created: user@kpsggroup.com / user123")
 
+	// Create standard GAP weight table
+	gapWeightTable := models.GapWeightTable{
+		Nama:       "Standar",
+		Deskripsi:  "Tabel bobot nilai GAP standar profile matching",
+		BelowRange: "zero",
+		AboveRange: "zero",
+		Entries: []models.GapWeightEntry{
+			{Gap: 0, Bobot: 5},
+			{Gap: 1, Bobot: 4.5},
+			{Gap: -1, Bobot: 4},
+			{Gap: 2, Bobot: 3.5},
+			{Gap: -2, Bobot: 3},
+			{Gap: 3, Bobot: 2.5},
+			{Gap: -3, Bobot: 2},
+			{Gap: 4, Bobot: 1.5},
+			{Gap: -4, Bobot: 1},
+		},
+	}
+	if err := db.Create(&gapWeightTable).Error; err != nil {
+		log.Fatal("Could not create gap weight table:", err)
+	}
+	log.Println("✅ GAP weight table created")
+
 	// Create Jabatan
 	jabatanData := []models.Jabatan{
 		{Nama: "Operator Produksi", Deskripsi: "Mengoperasikan mesin produksi gula"},
@@ -58,12 +58,14 @@ func (ac *AspekController) Create(c *gin.Context) {
 	}
 
 	aspek := &models.Aspek{
-		Nama:       req.Nama,
-		Deskripsi:  req.Deskripsi,
-		Persentase: req.Persentase,
+		Nama:                  req.Nama,
+		Deskripsi:             req.Deskripsi,
+		Persentase:            req.Persentase,
+		CoreFactorPersen:      req.CoreFactorPersen,
+		SecondaryFactorPersen: req.SecondaryFactorPersen,
 	}
 
-	if err := ac.aspekService.Create(aspek); err != nil {
+	if err := ac.aspekService.WithActor(actorID(c)).Create(aspek); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -86,16 +88,23 @@ func (ac *AspekController) Update(c *gin.Context) {
 	}
 
 	aspek := &models.Aspek{
-		Nama:       req.Nama,
-		Deskripsi:  req.Deskripsi,
-		Persentase: req.Persentase,
+		Nama:                  req.Nama,
+		Deskripsi:             req.Deskripsi,
+		Persentase:            req.Persentase,
+		CoreFactorPersen:      req.CoreFactorPersen,
+		SecondaryFactorPersen: req.SecondaryFactorPersen,
 	}
 
-	if err := ac.aspekService.Update(uint(id64), aspek); err != nil {
+	opts := services.AspekUpdateOptions{ResetFactorRatio: req.ResetFactorRatio}
+	if err := ac.aspekService.WithActor(actorID(c)).Update(uint(id64), aspek, opts); err != nil {
 		if err.Error() == "aspek not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		if err.Error() == "invalid factor ratio: core and secondary must both be set and sum to 100" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update aspek"})
 		return
 	}
@@ -111,7 +120,7 @@ func (ac *AspekController) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := ac.aspekService.Delete(uint(id64)); err != nil {
+	if err := ac.aspekService.WithActor(actorID(c)).Delete(uint(id64)); err != nil {
 		if err.Error() == "aspek not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -140,4 +140,3 @@ func TestAspekController_Delete(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 }
-
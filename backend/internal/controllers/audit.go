@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// actorID returns the authenticated user, recorded on the audit entries of
+// the changes made by the request; nil when no user is authenticated
+func actorID(c *gin.Context) *uint {
+	if id, ok := middleware.UserID(c); ok {
+		return &id
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/repositories"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLogController struct {
+	auditService *services.AuditService
+}
+
+func NewAuditLogController(auditService *services.AuditService) *AuditLogController {
+	return &AuditLogController{auditService: auditService}
+}
+
+// GetAll lists the audit log newest first, filtered by ?entity=, ?entity_id=,
+// ?user_id= and the dates ?from= and ?to=, both inclusive
+func (alc *AuditLogController) GetAll(c *gin.Context) {
+	filter := repositories.AuditLogFilter{Entity: c.Query("entity")}
+	for name, target := range map[string]*uint{"entity_id": &filter.EntityID, "user_id": &filter.UserID} {
+		if value := c.Query(name); value != "" {
+			id64, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+				return
+			}
+			*target = uint(id64)
+		}
+	}
+	if value := c.Query("from"); value != "" {
+		from, err := dto.ParseDateOnly(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use YYYY-MM-DD"})
+			return
+		}
+		t := time.Time(from)
+		filter.From = &t
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := dto.ParseDateOnly(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use YYYY-MM-DD"})
+			return
+		}
+		// The filter's end is exclusive, so the whole day is included
+		t := time.Time(to).AddDate(0, 0, 1)
+		filter.To = &t
+	}
+
+	entries, err := alc.auditService.GetAll(filter)
+	if err != nil {
+		if err.Error() == "invalid date range" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapAuditLogsToResponse(entries))
+}
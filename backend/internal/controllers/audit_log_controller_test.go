@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func newTestAuditService(db *gorm.DB) *services.AuditService {
+	return services.NewAuditService(repositories.NewAuditLogRepository(db))
+}
+
+func TestAuditLogController_RecordsChanges(t *testing.T) {
+	db := setupControllerTestDB(t)
+	user := &models.User{Email: "auditor@example.com", Password: "x", Nama: "Auditor"}
+	assert.NoError(t, repositories.NewUserRepository(db).Create(user))
+	auditService := newTestAuditService(db)
+	aspekService := services.NewAspekService(repositories.NewAspekRepository(db))
+	aspekCtrl := NewAspekController(aspekService)
+	auditLogCtrl := NewAuditLogController(auditService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		// As set by AuthMiddleware from the JWT claims
+		c.Set("userID", float64(user.ID))
+	})
+	router.POST("/api/aspek", aspekCtrl.Create)
+	router.PUT("/api/aspek/:id", aspekCtrl.Update)
+	router.DELETE("/api/aspek/:id", aspekCtrl.Delete)
+	router.GET("/api/audit-logs", auditLogCtrl.GetAll)
+
+	send := func(method, url string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(method, url, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("POST", "/api/aspek", map[string]interface{}{"nama": "Kompetensi", "persentase": 50.0})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	url := fmt.Sprintf("/api/aspek/%v", created["id"])
+	assert.Equal(t, http.StatusOK, send("PUT", url, map[string]interface{}{"nama": "Kompetensi", "persentase": 60.0}).Code)
+	assert.Equal(t, http.StatusOK, send("DELETE", url, nil).Code)
+
+	today := time.Now().Format("2006-01-02")
+	w = send("GET", fmt.Sprintf("/api/audit-logs?entity=aspek&user_id=%d&from=%s&to=%s", user.ID, today, today), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var entries []map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &entries)
+	assert.Len(t, entries, 3)
+	if len(entries) == 3 {
+		assert.Equal(t, "delete", entries[0]["action"])
+		assert.Equal(t, "update", entries[1]["action"])
+		assert.Equal(t, "create", entries[2]["action"])
+		assert.Equal(t, map[string]interface{}{"persentase": map[string]interface{}{"old": 50.0, "new": 60.0}}, entries[1]["changes"])
+		assert.Equal(t, float64(user.ID), entries[1]["user_id"])
+	}
+
+	w = send("GET", "/api/audit-logs?entity=jabatan", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+}
+
+func TestAuditLogController_InvalidFilter(t *testing.T) {
+	db := setupControllerTestDB(t)
+	auditLogCtrl := NewAuditLogController(newTestAuditService(db))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/audit-logs", auditLogCtrl.GetAll)
+
+	for _, query := range []string{"user_id=abc", "entity_id=-1", "from=18-10-2026", "from=2026-10-18&to=2026-10-17"} {
+		req := httptest.NewRequest("GET", "/api/audit-logs?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
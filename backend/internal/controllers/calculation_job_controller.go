@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/dto"
+	"backend/internal/middleware"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalculationJobController struct {
+	calculationJobService *services.CalculationJobService
+}
+
+func NewCalculationJobController(calculationJobService *services.CalculationJobService) *CalculationJobController {
+	return &CalculationJobController{calculationJobService: calculationJobService}
+}
+
+// Submit queues a background calculation and responds with the job to poll
+func (cjc *CalculationJobController) Submit(c *gin.Context) {
+	var req dto.CalculationJobCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	jobReq := services.CalculationJobRequest{
+		JabatanIDs:    req.JabatanIDs,
+		GapMode:       req.GapMode,
+		RoundingRule:  req.RoundingRule,
+		MissingPolicy: req.MissingPolicy,
+		MinimumNilai:  req.MinimumNilai,
+		Method:        req.Method,
+		RankingStyle:  req.RankingStyle,
+		TieBreakers:   req.TieBreakers,
+	}
+	if userID, ok := middleware.UserID(c); ok {
+		jobReq.UserID = &userID
+	}
+
+	job, err := cjc.calculationJobService.Submit(jobReq)
+	if err != nil {
+		switch err.Error() {
+		case "calculation job queue is full":
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		case "jabatan not found", "no jabatan to calculate", "invalid gap mode", "invalid rounding rule", "invalid missing policy", "invalid method",
+			"invalid ranking style", "invalid tie breaker":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.MapCalculationJobToResponse(job))
+}
+
+func (cjc *CalculationJobController) GetByID(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	job, err := cjc.calculationJobService.GetByID(uint(id64))
+	if err != nil {
+		if err.Error() == "calculation job not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch calculation job"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapCalculationJobToResponse(job))
+}
+
+// Cancel cancels a queued job or stops a running one before its next jabatan
+func (cjc *CalculationJobController) Cancel(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	job, err := cjc.calculationJobService.Cancel(uint(id64), actorID(c))
+	if err != nil {
+		switch err.Error() {
+		case "calculation job not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "calculation job already finished":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not cancel calculation job"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapCalculationJobToResponse(job))
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"backend/internal/dto"
+	"backend/internal/services"
+	"backend/pkg/pdf"
+)
+
+const (
+	reportMargin    = 40.0
+	reportFooter    = 30.0
+	reportFontSize  = 9.0
+	reportRowHeight = 14.0
+)
+
+// reportColumn is a table column; numbers are right-aligned
+type reportColumn struct {
+	title string
+	width float64
+	right bool
+}
+
+// reportLayout draws the report top to bottom, starting a new page when the
+// next block does not fit
+type reportLayout struct {
+	doc *pdf.Document
+	y   float64
+}
+
+func (l *reportLayout) newPage() {
+	l.doc.AddPage()
+	l.y = reportMargin
+}
+
+func (l *reportLayout) ensure(height float64) {
+	if l.y+height > pdf.PageHeight-reportMargin-reportFooter {
+		l.newPage()
+	}
+}
+
+func (l *reportLayout) heading(size float64, s string) {
+	l.ensure(size * 2)
+	l.y += size
+	l.doc.Text(reportMargin, l.y, pdf.Bold, size, s)
+	l.y += size * 0.8
+}
+
+func (l *reportLayout) line(s string) {
+	l.ensure(reportRowHeight)
+	l.y += reportRowHeight
+	l.doc.Text(reportMargin, l.y, pdf.Regular, reportFontSize, s)
+}
+
+// table draws the rows under a bold header, repeating the header on each
+// page the table runs onto
+func (l *reportLayout) table(columns []reportColumn, rows [][]string) {
+	header := func() {
+		l.y += reportRowHeight
+		l.cells(columns, nil, pdf.Bold)
+		l.doc.Line(reportMargin, l.y+4, pdf.PageWidth-reportMargin, l.y+4)
+	}
+	l.ensure(reportRowHeight * 3)
+	header()
+	for _, row := range rows {
+		if l.y+reportRowHeight > pdf.PageHeight-reportMargin-reportFooter {
+			l.newPage()
+			header()
+		}
+		l.y += reportRowHeight
+		l.cells(columns, row, pdf.Regular)
+	}
+	l.y += reportRowHeight / 2
+}
+
+// cells draws one table row; a nil row draws the column titles
+func (l *reportLayout) cells(columns []reportColumn, row []string, font pdf.Font) {
+	x := reportMargin
+	for i, column := range columns {
+		s := column.title
+		if row != nil {
+			s = row[i]
+		}
+		s = pdf.Truncate(s, font, reportFontSize, column.width-4)
+		if column.right {
+			l.doc.Text(x+column.width-4-pdf.TextWidth(s, font, reportFontSize), l.y, font, reportFontSize, s)
+		} else {
+			l.doc.Text(x, l.y, font, reportFontSize, s)
+		}
+		x += column.width
+	}
+}
+
+// signatures draws the blocks the decision is signed in
+func (l *reportLayout) signatures() {
+	l.ensure(110)
+	titles := []string{"Disusun oleh,", "Diperiksa oleh,", "Disetujui oleh,"}
+	width := (pdf.PageWidth - 2*reportMargin) / float64(len(titles))
+	top := l.y + 30
+	for i, title := range titles {
+		x := reportMargin + float64(i)*width
+		l.doc.Text(x, top, pdf.Regular, reportFontSize, title)
+		l.doc.Line(x, top+55, x+width-20, top+55)
+		l.doc.Text(x, top+68, pdf.Regular, reportFontSize, "Nama:")
+		l.doc.Text(x, top+82, pdf.Regular, reportFontSize, "Tanggal:")
+	}
+	l.y = top + 82
+}
+
+func reportNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func reportFactor(isCore bool) string {
+	if isCore {
+		return "Core"
+	}
+	return "Secondary"
+}
+
+// decisionReportPDF lays out the target profile, the ranking and the
+// signature blocks, then one page per candidate with their breakdown.
+// A positive top limits the candidate pages to the best ranked.
+func decisionReportPDF(report *services.DecisionReport, top int) ([]byte, error) {
+	l := &reportLayout{doc: pdf.New()}
+	l.newPage()
+
+	l.heading(16, "Laporan Keputusan Profile Matching")
+	l.line("Jabatan: " + report.Jabatan.Nama)
+	if run := report.Run; run != nil {
+		official := "belum resmi"
+		if run.IsOfficial {
+			official = "resmi"
+		}
+		l.line(fmt.Sprintf("Perhitungan: #%d, %s (%s)", run.ID, run.CreatedAt.Format("2006-01-02 15:04"), official))
+	}
+	first := report.Results[0]
+	l.line(fmt.Sprintf("Metode: %s, gap mode %s, missing policy %s", first.Method, first.GapMode, first.MissingPolicy))
+	l.y += reportRowHeight / 2
+
+	l.heading(12, "Profil Target")
+	targetRows := make([][]string, 0, len(report.TargetProfiles))
+	for _, tp := range report.TargetProfiles {
+		minimum := "-"
+		if tp.NilaiMinimum != nil {
+			minimum = reportNumber(*tp.NilaiMinimum)
+		}
+		targetRows = append(targetRows, []string{
+			tp.Kriteria.Aspek.Nama,
+			tp.Kriteria.Kode,
+			tp.Kriteria.Nama,
+			reportFactor(tp.Kriteria.IsCore),
+			reportNumber(tp.Kriteria.Bobot),
+			reportNumber(tp.TargetNilai),
+			minimum,
+		})
+	}
+	l.table([]reportColumn{
+		{"Aspek", 90, false}, {"Kode", 45, false}, {"Kriteria", 160, false}, {"Faktor", 60, false},
+		{"Bobot", 50, true}, {"Target", 55, true}, {"Minimum", 55, true},
+	}, targetRows)
+
+	l.heading(12, "Peringkat Kandidat")
+	rankRows := make([][]string, len(report.Results))
+	for i, result := range report.Results {
+		rank := result.Rank
+		if rank == 0 {
+			rank = i + 1
+		}
+		status := result.Status
+		if result.Incomplete {
+			status += " (tidak lengkap)"
+		}
+		rankRows[i] = []string{
+			strconv.Itoa(rank),
+			result.TenagaKerja.NIK,
+			result.TenagaKerja.Nama,
+			reportNumber(result.TotalScore),
+			reportNumber(result.CoreFactor),
+			reportNumber(result.SecondaryFactor),
+			status,
+		}
+	}
+	l.table([]reportColumn{
+		{"Peringkat", 50, true}, {"NIK", 70, false}, {"Nama", 145, false}, {"Total", 50, true},
+		{"CF", 45, true}, {"SF", 45, true}, {"Status", 110, false},
+	}, rankRows)
+	l.signatures()
+
+	for i := range report.Results {
+		if top > 0 && i >= top {
+			break
+		}
+		detail := dto.MapProfileMatchResultToDetailResponse(&report.Results[i], report.Details[i], 0)
+		l.newPage()
+		l.heading(14, fmt.Sprintf("Rincian Perhitungan: %s (%s)", report.Results[i].TenagaKerja.Nama, report.Results[i].TenagaKerja.NIK))
+		l.line(fmt.Sprintf("Peringkat %s | Skor total %s | CF %s | SF %s | Status %s",
+			rankRows[i][0], reportNumber(detail.TotalScore), reportNumber(detail.CoreFactor),
+			reportNumber(detail.SecondaryFactor), detail.Status))
+		l.y += reportRowHeight / 2
+
+		aspekNames := make([]string, 0, len(detail.Details.Aspek))
+		for nama := range detail.Details.Aspek {
+			aspekNames = append(aspekNames, nama)
+		}
+		sort.Strings(aspekNames)
+		imputed := false
+		for _, nama := range aspekNames {
+			aspek := detail.Details.Aspek[nama]
+			l.heading(10, fmt.Sprintf("%s (%s%%)", nama, reportNumber(aspek.Persentase)))
+			l.line(fmt.Sprintf("CF %s | SF %s | Skor %s | Kontribusi %s | Rasio core/secondary %s/%s",
+				reportNumber(aspek.CF), reportNumber(aspek.SF), reportNumber(aspek.Score), reportNumber(aspek.Kontribusi),
+				reportNumber(aspek.CoreFactorPersen), reportNumber(aspek.SecondaryFactorPersen)))
+			rows := make([][]string, 0, len(aspek.Kriteria))
+			for _, k := range aspek.Kriteria {
+				actual := reportNumber(k.Actual)
+				if k.Imputed {
+					actual += "*"
+					imputed = true
+				}
+				rows = append(rows, []string{
+					k.Kode, k.Nama, reportFactor(k.IsCore), reportNumber(k.Target), actual,
+					reportNumber(k.Gap), reportNumber(k.BobotNilai),
+				})
+			}
+			l.table([]reportColumn{
+				{"Kode", 45, false}, {"Kriteria", 170, false}, {"Faktor", 60, false}, {"Target", 55, true},
+				{"Aktual", 55, true}, {"Gap", 55, true}, {"Bobot Nilai", 75, true},
+			}, rows)
+		}
+		if imputed {
+			l.line("* Nilai tidak tersedia; dihitung dengan nilai minimum.")
+		}
+		if len(detail.Details.MissingKriteria) > 0 {
+			l.line("Kriteria tanpa nilai: " + strings.Join(detail.Details.MissingKriteria, ", "))
+		}
+	}
+
+	// Footers go on last, once the page count is known
+	pages := l.doc.PageCount()
+	for i := 0; i < pages; i++ {
+		l.doc.SetPage(i)
+		footer := fmt.Sprintf("Halaman %d dari %d", i+1, pages)
+		y := pdf.PageHeight - reportMargin
+		l.doc.Text(reportMargin, y, pdf.Regular, 8, pdf.Truncate(report.Jabatan.Nama, pdf.Regular, 8, 300))
+		l.doc.Text(pdf.PageWidth-reportMargin-pdf.TextWidth(footer, pdf.Regular, 8), y, pdf.Regular, 8, footer)
+	}
+
+	var buf bytes.Buffer
+	if _, err := l.doc.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"backend/internal/models"
+	"backend/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecisionReportPDF(t *testing.T) {
+	kriteria := models.Kriteria{Kode: "K1", Nama: "Kriteria 1", IsCore: true, Bobot: 1, Aspek: models.Aspek{Nama: "Teknis"}}
+	results := make([]models.ProfileMatchResult, 80)
+	details := make([]map[string]interface{}, len(results))
+	for i := range results {
+		results[i] = models.ProfileMatchResult{
+			TenagaKerja: models.TenagaKerja{NIK: "TK1", Nama: "Ani (Kepala)"},
+			TotalScore:  4.2, Rank: i + 1, Status: "candidate",
+		}
+		details[i] = map[string]interface{}{
+			"aspek": map[string]map[string]interface{}{
+				"Teknis": {
+					"persentase": 100.0,
+					"kriteria":   []map[string]interface{}{{"kode": "K1", "nama": "Kriteria 1", "actual": 2.0, "imputed": true}},
+				},
+			},
+		}
+	}
+	report := &services.DecisionReport{
+		Jabatan:        models.Jabatan{Nama: "Supervisor"},
+		TargetProfiles: []models.TargetProfile{{Kriteria: kriteria, TargetNilai: 3}},
+		Results:        results,
+		Details:        details,
+	}
+
+	data, err := decisionReportPDF(report, 2)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+	out := string(data)
+	assert.Contains(t, out, "(Profil Target) Tj")
+	assert.Contains(t, out, "(Disetujui oleh,) Tj")
+	assert.Contains(t, out, `(Rincian Perhitungan: Ani \(Kepala\) \(TK1\)) Tj`)
+	assert.Contains(t, out, "(2.00*) Tj")
+
+	// The ranking runs onto a second page; two candidate pages follow
+	assert.Contains(t, out, "/Count 4")
+	assert.Equal(t, 2, strings.Count(out, "(Rincian Perhitungan:"))
+	assert.Contains(t, out, "(Halaman 4 dari 4) Tj")
+}
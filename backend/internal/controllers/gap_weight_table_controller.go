@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GapWeightTableController struct {
+	gapWeightTableService *services.GapWeightTableService
+}
+
+func NewGapWeightTableController(gapWeightTableService *services.GapWeightTableService) *GapWeightTableController {
+	return &GapWeightTableController{gapWeightTableService: gapWeightTableService}
+}
+
+func (gc *GapWeightTableController) GetAll(c *gin.Context) {
+	tables, err := gc.gapWeightTableService.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch gap weight tables"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.MapGapWeightTablesToResponse(tables))
+}
+
+func (gc *GapWeightTableController) GetByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	table, err := gc.gapWeightTableService.GetByID(uint(id64))
+	if err != nil {
+		if err.Error() == "gap weight table not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch gap weight table"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapGapWeightTableToResponse(table))
+}
+
+func (gc *GapWeightTableController) Create(c *gin.Context) {
+	var req dto.GapWeightTableCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	table := &models.GapWeightTable{
+		Nama:       req.Nama,
+		Deskripsi:  req.Deskripsi,
+		BelowRange: req.BelowRange,
+		AboveRange: req.AboveRange,
+		Entries:    dto.MapGapWeightEntryRequests(req.Entries),
+	}
+
+	if err := gc.gapWeightTableService.WithActor(actorID(c)).Create(table); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapGapWeightTableToResponse(table))
+}
+
+func (gc *GapWeightTableController) Update(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var req dto.GapWeightTableUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	table := &models.GapWeightTable{
+		Nama:       req.Nama,
+		Deskripsi:  req.Deskripsi,
+		BelowRange: req.BelowRange,
+		AboveRange: req.AboveRange,
+	}
+
+	var entries []models.GapWeightEntry
+	if req.Entries != nil {
+		entries = dto.MapGapWeightEntryRequests(req.Entries)
+	}
+
+	if err := gc.gapWeightTableService.WithActor(actorID(c)).Update(uint(id64), table, entries); err != nil {
+		if err.Error() == "gap weight table not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Gap weight table updated successfully"})
+}
+
+func (gc *GapWeightTableController) Delete(c *gin.Context) {
+	idStr := c.Param("id")
+	id64, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := gc.gapWeightTableService.WithActor(actorID(c)).Delete(uint(id64)); err != nil {
+		if err.Error() == "gap weight table not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "gap weight table is in use by a jabatan" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete gap weight table"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Gap weight table deleted successfully"})
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGapWeightTableController_Create(t *testing.T) {
+	db := setupControllerTestDB(t)
+	gapWeightTableService := services.NewGapWeightTableService(repositories.NewGapWeightTableRepository(db), repositories.NewJabatanRepository(db))
+	gapWeightTableCtrl := NewGapWeightTableController(gapWeightTableService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/gap-weight-tables", gapWeightTableCtrl.Create)
+
+	payload := map[string]interface{}{
+		"nama":        "Asimetris",
+		"above_range": "clamp",
+		"entries": []map[string]interface{}{
+			{"gap": 0, "bobot": 5},
+			{"gap": 1, "bobot": 4.5},
+			{"gap": -1, "bobot": 3},
+		},
+	}
+
+	payloadBytes, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", "/api/gap-weight-tables", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Asimetris", response["nama"])
+	assert.Equal(t, "clamp", response["above_range"])
+}
+
+func TestGapWeightTableController_GetByID(t *testing.T) {
+	db := setupControllerTestDB(t)
+	gapWeightTableService := services.NewGapWeightTableService(repositories.NewGapWeightTableRepository(db), repositories.NewJabatanRepository(db))
+	gapWeightTableCtrl := NewGapWeightTableController(gapWeightTableService)
+
+	table := &models.GapWeightTable{Nama: "Standar", Entries: []models.GapWeightEntry{{Gap: 0, Bobot: 5}}}
+	gapWeightTableService.Create(table)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/gap-weight-tables/:id", gapWeightTableCtrl.GetByID)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/gap-weight-tables/%d", table.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response["entries"], 1)
+}
@@ -57,11 +57,16 @@ func (jc *JabatanController) Create(c *gin.Context) {
 	}
 
 	jabatan := &models.Jabatan{
-		Nama:      req.Nama,
-		Deskripsi: req.Deskripsi,
+		Nama:                  req.Nama,
+		Deskripsi:             req.Deskripsi,
+		GapWeightTableID:      req.GapWeightTableID,
+		CoreFactorPersen:      req.CoreFactorPersen,
+		SecondaryFactorPersen: req.SecondaryFactorPersen,
+		JumlahLowongan:        req.JumlahLowongan,
+		SkorMinimum:           req.SkorMinimum,
 	}
 
-	if err := jc.jabatanService.Create(jabatan); err != nil {
+	if err := jc.jabatanService.WithActor(actorID(c)).Create(jabatan); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -84,15 +89,29 @@ func (jc *JabatanController) Update(c *gin.Context) {
 	}
 
 	jabatan := &models.Jabatan{
-		Nama:      req.Nama,
-		Deskripsi: req.Deskripsi,
+		Nama:                  req.Nama,
+		Deskripsi:             req.Deskripsi,
+		GapWeightTableID:      req.GapWeightTableID,
+		CoreFactorPersen:      req.CoreFactorPersen,
+		SecondaryFactorPersen: req.SecondaryFactorPersen,
+		SkorMinimum:           req.SkorMinimum,
 	}
 
-	if err := jc.jabatanService.Update(uint(id64), jabatan); err != nil {
+	opts := services.JabatanUpdateOptions{
+		JumlahLowongan:   req.JumlahLowongan,
+		ResetFactorRatio: req.ResetFactorRatio,
+		ResetSkorMinimum: req.ResetSkorMinimum,
+	}
+	if err := jc.jabatanService.WithActor(actorID(c)).Update(uint(id64), jabatan, opts); err != nil {
 		if err.Error() == "jabatan not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		if err.Error() == "gap weight table not found" || err.Error() == "invalid factor ratio: core and secondary must both be set and sum to 100" ||
+			err.Error() == "jumlah lowongan tidak boleh negatif" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update jabatan"})
 		return
 	}
@@ -108,7 +127,7 @@ func (jc *JabatanController) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := jc.jabatanService.Delete(uint(id64)); err != nil {
+	if err := jc.jabatanService.WithActor(actorID(c)).Delete(uint(id64)); err != nil {
 		if err.Error() == "jabatan not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -19,7 +19,7 @@ import (
 func TestJabatanController_GetAll(t *testing.T) {
 	db := setupControllerTestDB(t)
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	jabatanCtrl := NewJabatanController(jabatanService)
 
 	// Create test jabatan
@@ -46,7 +46,7 @@ func TestJabatanController_GetAll(t *testing.T) {
 func TestJabatanController_Create(t *testing.T) {
 	db := setupControllerTestDB(t)
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	jabatanCtrl := NewJabatanController(jabatanService)
 
 	gin.SetMode(gin.TestMode)
@@ -74,7 +74,7 @@ func TestJabatanController_Create(t *testing.T) {
 func TestJabatanController_GetByID(t *testing.T) {
 	db := setupControllerTestDB(t)
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	jabatanCtrl := NewJabatanController(jabatanService)
 
 	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
@@ -98,7 +98,7 @@ func TestJabatanController_GetByID(t *testing.T) {
 func TestJabatanController_Update(t *testing.T) {
 	db := setupControllerTestDB(t)
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	jabatanCtrl := NewJabatanController(jabatanService)
 
 	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
@@ -124,7 +124,7 @@ func TestJabatanController_Update(t *testing.T) {
 func TestJabatanController_Delete(t *testing.T) {
 	db := setupControllerTestDB(t)
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	jabatanCtrl := NewJabatanController(jabatanService)
 
 	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
@@ -144,7 +144,7 @@ func TestJabatanController_Delete(t *testing.T) {
 func TestJabatanController_Create_EmptyName(t *testing.T) {
 	db := setupControllerTestDB(t)
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	jabatanCtrl := NewJabatanController(jabatanService)
 
 	gin.SetMode(gin.TestMode)
@@ -164,4 +164,3 @@ func TestJabatanController_Create_EmptyName(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
-
@@ -64,7 +64,7 @@ func (kc *KriteriaController) Create(c *gin.Context) {
 		Bobot:   req.Bobot,
 	}
 
-	if err := kc.kriteriaService.Create(kriteria); err != nil {
+	if err := kc.kriteriaService.WithActor(actorID(c)).Create(kriteria); err != nil {
 		if err.Error() == "aspek not found" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -98,7 +98,7 @@ func (kc *KriteriaController) Update(c *gin.Context) {
 		Bobot:   req.Bobot,
 	}
 
-	if err := kc.kriteriaService.Update(uint(id64), kriteria); err != nil {
+	if err := kc.kriteriaService.WithActor(actorID(c)).Update(uint(id64), kriteria); err != nil {
 		if err.Error() == "kriteria not found" || err.Error() == "aspek not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -118,7 +118,7 @@ func (kc *KriteriaController) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := kc.kriteriaService.Delete(uint(id64)); err != nil {
+	if err := kc.kriteriaService.WithActor(actorID(c)).Delete(uint(id64)); err != nil {
 		if err.Error() == "kriteria not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -167,4 +167,3 @@ func TestKriteriaController_Delete(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 }
-
@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModelConfigController struct {
+	modelConfigService *services.ModelConfigService
+}
+
+func NewModelConfigController(modelConfigService *services.ModelConfigService) *ModelConfigController {
+	return &ModelConfigController{modelConfigService: modelConfigService}
+}
+
+// Export downloads the competency model configuration as a JSON document
+// that the import-config command applies to another environment
+func (mc *ModelConfigController) Export(c *gin.Context) {
+	cfg, err := mc.modelConfigService.Export()
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "is not unique") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not export model configuration"})
+		return
+	}
+
+	filename := fmt.Sprintf("model-config-%s.json", cfg.ExportedAt.Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.IndentedJSON(http.StatusOK, cfg)
+}
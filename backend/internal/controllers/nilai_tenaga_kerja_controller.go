@@ -81,7 +81,7 @@ func (ntkc *NilaiTenagaKerjaController) Create(c *gin.Context) {
 		Nilai:         req.Nilai,
 	}
 
-	if err := ntkc.nilaiTenagaKerjaService.Create(nilai); err != nil {
+	if err := ntkc.nilaiTenagaKerjaService.WithActor(actorID(c)).Create(nilai); err != nil {
 		if err.Error() == "tenaga kerja not found" || err.Error() == "kriteria not found" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -113,7 +113,7 @@ func (ntkc *NilaiTenagaKerjaController) Update(c *gin.Context) {
 		Nilai:         req.Nilai,
 	}
 
-	if err := ntkc.nilaiTenagaKerjaService.Update(uint(id64), nilai); err != nil {
+	if err := ntkc.nilaiTenagaKerjaService.WithActor(actorID(c)).Update(uint(id64), nilai); err != nil {
 		if err.Error() == "nilai tenaga kerja not found" || err.Error() == "tenaga kerja not found" || err.Error() == "kriteria not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -133,7 +133,7 @@ func (ntkc *NilaiTenagaKerjaController) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := ntkc.nilaiTenagaKerjaService.Delete(uint(id64)); err != nil {
+	if err := ntkc.nilaiTenagaKerjaService.WithActor(actorID(c)).Delete(uint(id64)); err != nil {
 		if err.Error() == "nilai tenaga kerja not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -222,4 +222,3 @@ func TestNilaiTenagaKerjaController_Delete(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 }
-
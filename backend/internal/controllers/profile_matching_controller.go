@@ -1,11 +1,21 @@
 package controllers
 
 import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
 	"backend/internal/dto"
+	"backend/internal/middleware"
+	"backend/internal/models"
 	"backend/internal/services"
+	"backend/pkg/pdf"
+	"backend/pkg/profilematching"
+	"backend/pkg/xlsx"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,6 +28,29 @@ func NewProfileMatchingController(profileMatchingService *services.ProfileMatchi
 	return &ProfileMatchingController{profileMatchingService: profileMatchingService}
 }
 
+// calculationRequestErrors are the service errors caused by the request
+// rather than by the server
+var calculationRequestErrors = map[string]bool{
+	"jabatan not found":                          true,
+	"no target profiles found for this jabatan":  true,
+	"invalid gap mode":                           true,
+	"invalid rounding rule":                      true,
+	"invalid missing policy":                     true,
+	"invalid method":                             true,
+	"invalid ranking style":                      true,
+	"invalid tie breaker":                        true,
+	"tie breaker kriteria not in target profile": true,
+	"invalid perturbation range":                 true,
+	"too many sensitivity scenarios":             true,
+	"top n must be positive":                     true,
+	"aspek not in target profile":                true,
+	"kriteria not in target profile":             true,
+	"tenaga kerja not in calculation":            true,
+	"compare run or simulation is required":      true,
+	"runs belong to different jabatan":           true,
+	"invalid factor ratio: core and secondary must both be set and sum to 100": true,
+}
+
 func (pmc *ProfileMatchingController) Calculate(c *gin.Context) {
 	var req dto.CalculationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -25,12 +58,37 @@ func (pmc *ProfileMatchingController) Calculate(c *gin.Context) {
 		return
 	}
 
-	results, err := pmc.profileMatchingService.Calculate(services.CalculationRequest{
+	calculationReq := services.CalculationRequest{
 		JabatanID:      req.JabatanID,
 		TenagaKerjaIDs: req.TenagaKerjaIDs,
-	})
+		GapMode:        req.GapMode,
+		RoundingRule:   req.RoundingRule,
+		MissingPolicy:  req.MissingPolicy,
+		MinimumNilai:   req.MinimumNilai,
+		Method:         req.Method,
+		RankingStyle:   req.RankingStyle,
+		TieBreakers:    req.TieBreakers,
+	}
+	if userID, ok := middleware.UserID(c); ok {
+		calculationReq.UserID = &userID
+	}
+
+	if req.DryRun {
+		pmc.simulate(c, req, calculationReq)
+		return
+	}
+	if len(req.NilaiOverrides) > 0 || len(req.TargetOverrides) > 0 || len(req.CoreOverrides) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "overrides require dry_run"})
+		return
+	}
+
+	results, err := pmc.profileMatchingService.Calculate(calculationReq)
 	if err != nil {
-		if err.Error() == "jabatan not found" || err.Error() == "no target profiles found for this jabatan" {
+		if err.Error() == "calculation already running for this jabatan" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if calculationRequestErrors[err.Error()] {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -43,6 +101,50 @@ func (pmc *ProfileMatchingController) Calculate(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// simulate runs a dry-run calculation with the request's overrides and
+// responds with the ranking and breakdown; nothing is stored
+func (pmc *ProfileMatchingController) simulate(c *gin.Context, req dto.CalculationRequest, calculationReq services.CalculationRequest) {
+	simulationReq := simulationRequest(calculationReq, req.NilaiOverrides, req.TargetOverrides, req.CoreOverrides)
+
+	results, details, err := pmc.profileMatchingService.Simulate(simulationReq)
+	if err != nil {
+		if calculationRequestErrors[err.Error()] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]dto.ProfileMatchResultDetailResponse, len(results))
+	for i := range results {
+		response[i] = dto.MapProfileMatchResultToDetailResponse(&results[i], details[i], results[i].Rank)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func simulationRequest(calculationReq services.CalculationRequest, nilaiOverrides []dto.NilaiOverride, targetOverrides []dto.TargetOverride, coreOverrides []dto.CoreOverride) services.SimulationRequest {
+	simulationReq := services.SimulationRequest{
+		CalculationRequest: calculationReq,
+		TargetOverrides:    make(map[uint]float64),
+		CoreOverrides:      make(map[uint]bool),
+	}
+	for _, o := range nilaiOverrides {
+		simulationReq.NilaiOverrides = append(simulationReq.NilaiOverrides, services.NilaiOverride{
+			TenagaKerjaID: o.TenagaKerjaID,
+			KriteriaID:    o.KriteriaID,
+			Nilai:         o.Nilai,
+		})
+	}
+	for _, o := range targetOverrides {
+		simulationReq.TargetOverrides[o.KriteriaID] = o.TargetNilai
+	}
+	for _, o := range coreOverrides {
+		simulationReq.CoreOverrides[o.KriteriaID] = o.IsCore
+	}
+	return simulationReq
+}
+
 func (pmc *ProfileMatchingController) GetAllResults(c *gin.Context) {
 	// Check if jabatan_id query param exists
 	jabatanIDStr := c.Query("jabatan_id")
@@ -95,8 +197,20 @@ func (pmc *ProfileMatchingController) GetResultByID(c *gin.Context) {
 		return
 	}
 
-	// Get rank by getting all results for the same jabatan and finding the position
-	allResults, err := pmc.profileMatchingService.GetResultsByJabatanID(result.JabatanID)
+	if result.Rank > 0 {
+		response := dto.MapProfileMatchResultToDetailResponse(result, details, result.Rank)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	// Results stored before ranks were recorded get their position among
+	// the results of the same run (or the current results of the jabatan)
+	var allResults []models.ProfileMatchResult
+	if result.RunID != nil {
+		_, allResults, err = pmc.profileMatchingService.GetRunByID(*result.RunID)
+	} else {
+		allResults, err = pmc.profileMatchingService.GetResultsByJabatanID(result.JabatanID)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch results for ranking"})
 		return
@@ -115,3 +229,607 @@ func (pmc *ProfileMatchingController) GetResultByID(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// Sensitivity reports how stable the ranking of a jabatan is when aspek
+// persentase, the core/secondary ratio and target nilai vary. Stored results
+// are not changed.
+func (pmc *ProfileMatchingController) Sensitivity(c *gin.Context) {
+	var req dto.SensitivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sensitivityReq := services.SensitivityRequest{
+		CalculationRequest: services.CalculationRequest{
+			JabatanID:      req.JabatanID,
+			TenagaKerjaIDs: req.TenagaKerjaIDs,
+			GapMode:        req.GapMode,
+			RoundingRule:   req.RoundingRule,
+			MissingPolicy:  req.MissingPolicy,
+			MinimumNilai:   req.MinimumNilai,
+			Method:         req.Method,
+			RankingStyle:   req.RankingStyle,
+			TieBreakers:    req.TieBreakers,
+		},
+		TopN:            req.TopN,
+		AspekPersentase: make(map[uint]profilematching.Range),
+		TargetNilai:     make(map[uint]profilematching.Range),
+	}
+	for _, r := range req.AspekPersentase {
+		sensitivityReq.AspekPersentase[r.AspekID] = profilematching.Range{Min: r.Min, Max: r.Max, Step: r.Step}
+	}
+	if req.CoreFactorPersen != nil {
+		sensitivityReq.CoreFactorPersen = &profilematching.Range{Min: req.CoreFactorPersen.Min, Max: req.CoreFactorPersen.Max, Step: req.CoreFactorPersen.Step}
+	}
+	for _, r := range req.TargetNilai {
+		sensitivityReq.TargetNilai[r.KriteriaID] = profilematching.Range{Min: r.Min, Max: r.Max, Step: r.Step}
+	}
+
+	report, tenagaKerja, err := pmc.profileMatchingService.Sensitivity(sensitivityReq)
+	if err != nil {
+		if calculationRequestErrors[err.Error()] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapSensitivityToResponse(req.JabatanID, report, tenagaKerja))
+}
+
+// GetRuns lists calculation runs newest first, optionally for one jabatan
+func (pmc *ProfileMatchingController) GetRuns(c *gin.Context) {
+	var jabatanID uint64
+	if jabatanIDStr := c.Query("jabatan_id"); jabatanIDStr != "" {
+		var err error
+		jabatanID, err = strconv.ParseUint(jabatanIDStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid jabatan_id format"})
+			return
+		}
+	}
+
+	runs, err := pmc.profileMatchingService.GetRuns(uint(jabatanID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch calculation runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapCalculationRunsToResponse(runs))
+}
+
+// GetRunByID returns a calculation run with the ranking it produced
+func (pmc *ProfileMatchingController) GetRunByID(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	run, results, err := pmc.profileMatchingService.GetRunByID(uint(id64))
+	if err != nil {
+		if err.Error() == "calculation run not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch calculation run"})
+		return
+	}
+	ineligible, err := pmc.profileMatchingService.GetIneligibleByRunID(run.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch calculation run"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapCalculationRunToDetailResponse(run, results, ineligible))
+}
+
+// MarkRunOfficial makes a completed run the official result of its jabatan
+func (pmc *ProfileMatchingController) MarkRunOfficial(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := pmc.profileMatchingService.MarkRunOfficial(uint(id64), actorID(c)); err != nil {
+		switch err.Error() {
+		case "calculation run not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "only completed runs can be official":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not mark calculation run official"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calculation run marked as official successfully"})
+}
+
+// Compare reports the rank movements between a stored run and another run of
+// the same jabatan or a fresh simulation. With ?format=csv the movements are
+// returned as a CSV file.
+func (pmc *ProfileMatchingController) Compare(c *gin.Context) {
+	var req dto.CompareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	compareReq := services.CompareRequest{BaseRunID: req.BaseRunID, CompareRunID: req.CompareRunID}
+	if sim := req.Simulation; sim != nil {
+		simulationReq := simulationRequest(services.CalculationRequest{
+			TenagaKerjaIDs: sim.TenagaKerjaIDs,
+			GapMode:        sim.GapMode,
+			RoundingRule:   sim.RoundingRule,
+			MissingPolicy:  sim.MissingPolicy,
+			MinimumNilai:   sim.MinimumNilai,
+			Method:         sim.Method,
+			RankingStyle:   sim.RankingStyle,
+			TieBreakers:    sim.TieBreakers,
+		}, sim.NilaiOverrides, sim.TargetOverrides, sim.CoreOverrides)
+		compareReq.Simulation = &simulationReq
+	}
+
+	comparison, err := pmc.profileMatchingService.Compare(compareReq)
+	if err != nil {
+		if err.Error() == "calculation run not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if calculationRequestErrors[err.Error()] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		data, err := comparisonCSV(comparison)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not write comparison"})
+			return
+		}
+		filename := fmt.Sprintf("comparison-run-%d.csv", comparison.BaseRunID)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Data(http.StatusOK, "text/csv", data)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapComparisonToResponse(comparison))
+}
+
+// comparisonCSV writes one row per tenaga kerja, listing the kriteria whose
+// gap changed as "KODE: old -> new"
+func comparisonCSV(comparison *services.Comparison) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"tenaga_kerja_id", "nik", "nama", "old_rank", "new_rank", "rank_change", "old_score", "new_score", "score_delta", "kriteria_changes"})
+
+	gap := func(g *float64) string {
+		if g == nil {
+			return "-"
+		}
+		return strconv.FormatFloat(*g, 'f', 2, 64)
+	}
+	for _, m := range comparison.Movements {
+		changes := make([]string, len(m.KriteriaChanges))
+		for i, k := range m.KriteriaChanges {
+			changes[i] = fmt.Sprintf("%s: %s -> %s", k.Kode, gap(k.OldGap), gap(k.NewGap))
+		}
+		w.Write([]string{
+			strconv.FormatUint(uint64(m.TenagaKerjaID), 10),
+			m.TenagaKerja.NIK,
+			m.TenagaKerja.Nama,
+			strconv.Itoa(m.OldRank),
+			strconv.Itoa(m.NewRank),
+			strconv.Itoa(m.RankChange),
+			strconv.FormatFloat(m.OldScore, 'f', 2, 64),
+			strconv.FormatFloat(m.NewScore, 'f', 2, 64),
+			strconv.FormatFloat(m.ScoreDelta, 'f', 2, 64),
+			strings.Join(changes, "; "),
+		})
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// assignmentRequestErrors are the assignment errors caused by the request
+var assignmentRequestErrors = map[string]bool{
+	"jabatan has no calculation results":           true,
+	"jabatan results use different methods":        true,
+	"at least one vacancy is required":             true,
+	"openings must be positive":                    true,
+	"duplicate vacancy jabatan":                    true,
+	"too many openings":                            true,
+	"pinned jabatan not in vacancies":              true,
+	"pinned pair is excluded":                      true,
+	"pinned tenaga kerja has no score for jabatan": true,
+	"tenaga kerja pinned more than once":           true,
+	"more pins than openings":                      true,
+}
+
+// Assign fills the openings of several jabatan from their current rankings,
+// maximizing the total score with each tenaga kerja in at most one opening
+func (pmc *ProfileMatchingController) Assign(c *gin.Context) {
+	var req dto.AssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignmentReq := services.AssignmentRequest{}
+	for _, v := range req.Vacancies {
+		assignmentReq.Vacancies = append(assignmentReq.Vacancies, profilematching.Vacancy{JabatanID: v.JabatanID, Openings: v.Openings})
+	}
+	for _, p := range req.Pinned {
+		assignmentReq.Pinned = append(assignmentReq.Pinned, profilematching.Pair{TenagaKerjaID: p.TenagaKerjaID, JabatanID: p.JabatanID})
+	}
+	for _, p := range req.Excluded {
+		assignmentReq.Excluded = append(assignmentReq.Excluded, profilematching.Pair{TenagaKerjaID: p.TenagaKerjaID, JabatanID: p.JabatanID})
+	}
+
+	plan, err := pmc.profileMatchingService.Assign(assignmentReq)
+	if err != nil {
+		if err.Error() == "jabatan not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if assignmentRequestErrors[err.Error()] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, mapAssignmentPlanToResponse(plan))
+}
+
+// GetCandidates lists the current results of a jabatan in rank order,
+// optionally limited to one selection status with ?status=
+func (pmc *ProfileMatchingController) GetCandidates(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	results, err := pmc.profileMatchingService.GetCandidates(uint(id64), c.Query("status"))
+	if err != nil {
+		switch err.Error() {
+		case "jabatan not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "invalid result status":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch candidates"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapProfileMatchResultsToRankingResponse(results))
+}
+
+func (pmc *ProfileMatchingController) UpdateResultStatus(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var req dto.ResultStatusUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := pmc.profileMatchingService.UpdateResultStatus(uint(id64), req.Status, req.Note, actorID(c))
+	if err != nil {
+		switch err.Error() {
+		case "result not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "invalid result status", "only current results can change status":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "jabatan has no remaining vacancies":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update result status"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapProfileMatchResultToResponse(result))
+}
+
+// GetStatusHistory lists the statuses given to the result's tenaga kerja for
+// its jabatan, across recalculations
+func (pmc *ProfileMatchingController) GetStatusHistory(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	history, err := pmc.profileMatchingService.GetStatusHistory(uint(id64))
+	if err != nil {
+		if err.Error() == "result not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch status history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapResultStatusHistoriesToResponse(history))
+}
+
+// GetIneligible lists the tenaga kerja the current run of a jabatan knocked
+// out of its ranking, with the minimums they failed
+func (pmc *ProfileMatchingController) GetIneligible(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	ineligible, err := pmc.profileMatchingService.GetIneligibleByJabatanID(uint(id64))
+	if err != nil {
+		if err.Error() == "jabatan not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch ineligible tenaga kerja"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapIneligibleResultsToResponse(ineligible))
+}
+
+// exportDecimals matches the decimal(5,2) columns scores and gaps are stored in
+const exportDecimals = 2
+
+// ExportResults streams the current ranking of a jabatan as CSV or XLSX:
+// ?jabatan_id= is required, ?format=csv|xlsx defaults to csv and ?gaps=true
+// adds one gap column per kriteria
+func (pmc *ProfileMatchingController) ExportResults(c *gin.Context) {
+	jabatanID, err := strconv.ParseUint(c.Query("jabatan_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid jabatan_id format"})
+		return
+	}
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or xlsx"})
+		return
+	}
+	withGaps := c.Query("gaps") == "true"
+
+	results, err := pmc.profileMatchingService.ExportResults(uint(jabatanID), withGaps)
+	if err != nil {
+		if err.Error() == "jabatan not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch results"})
+		return
+	}
+
+	// Gap columns cover every kriteria in the snapshots, ordered by kriteria
+	var gapKriteria []models.ProfileMatchResultKriteria
+	if withGaps {
+		seen := make(map[uint]bool)
+		for _, result := range results {
+			for _, aspek := range result.Aspek {
+				for _, k := range aspek.Kriteria {
+					if !seen[k.KriteriaID] {
+						seen[k.KriteriaID] = true
+						gapKriteria = append(gapKriteria, k)
+					}
+				}
+			}
+		}
+		sort.Slice(gapKriteria, func(i, j int) bool { return gapKriteria[i].KriteriaID < gapKriteria[j].KriteriaID })
+	}
+
+	header := []xlsx.Cell{xlsx.String("rank"), xlsx.String("nik"), xlsx.String("nama"), xlsx.String("total_score"), xlsx.String("core_factor"), xlsx.String("secondary_factor")}
+	for _, k := range gapKriteria {
+		header = append(header, xlsx.String("gap_"+k.Kode))
+	}
+
+	filename := fmt.Sprintf("ranking-jabatan-%d.%s", jabatanID, format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	var writeRow func([]xlsx.Cell) error
+	var finish func() error
+	if format == "xlsx" {
+		c.Header("Content-Type", xlsx.ContentType)
+		w, err := xlsx.NewWriter(c.Writer, "Ranking")
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		writeRow, finish = w.WriteRow, w.Close
+	} else {
+		c.Header("Content-Type", "text/csv")
+		w := csv.NewWriter(c.Writer)
+		writeRow = func(cells []xlsx.Cell) error {
+			record := make([]string, len(cells))
+			for i, cell := range cells {
+				if cell.IsNumber {
+					record[i] = strconv.FormatFloat(cell.Number, 'f', cell.Decimals, 64)
+				} else {
+					record[i] = cell.String
+				}
+			}
+			return w.Write(record)
+		}
+		finish = func() error {
+			w.Flush()
+			return w.Error()
+		}
+	}
+
+	if err := writeRow(header); err != nil {
+		c.Error(err)
+		return
+	}
+	for i, result := range results {
+		rank := result.Rank
+		if rank == 0 {
+			rank = i + 1
+		}
+		row := []xlsx.Cell{
+			xlsx.Number(float64(rank), 0),
+			xlsx.String(result.TenagaKerja.NIK),
+			xlsx.String(result.TenagaKerja.Nama),
+			xlsx.Number(result.TotalScore, exportDecimals),
+			xlsx.Number(result.CoreFactor, exportDecimals),
+			xlsx.Number(result.SecondaryFactor, exportDecimals),
+		}
+		if len(gapKriteria) > 0 {
+			gaps := make(map[uint]float64)
+			for _, aspek := range result.Aspek {
+				for _, k := range aspek.Kriteria {
+					gaps[k.KriteriaID] = k.Gap
+				}
+			}
+			for _, k := range gapKriteria {
+				if gap, ok := gaps[k.KriteriaID]; ok {
+					row = append(row, xlsx.Number(gap, exportDecimals))
+				} else {
+					row = append(row, xlsx.String(""))
+				}
+			}
+		}
+		if err := writeRow(row); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if err := finish(); err != nil {
+		c.Error(err)
+	}
+}
+
+// DecisionReport renders the printable decision report of a jabatan as a
+// PDF. ?top= limits the per-candidate pages to the best ranked.
+func (pmc *ProfileMatchingController) DecisionReport(c *gin.Context) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	top := 0
+	if value := c.Query("top"); value != "" {
+		if top, err = strconv.Atoi(value); err != nil || top <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "top must be a positive number"})
+			return
+		}
+	}
+
+	report, err := pmc.profileMatchingService.DecisionReport(uint(id64))
+	if err != nil {
+		switch err.Error() {
+		case "jabatan not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "jabatan has no calculation results":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build decision report"})
+		}
+		return
+	}
+
+	data, err := decisionReportPDF(report, top)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not write decision report"})
+		return
+	}
+	filename := fmt.Sprintf("laporan-keputusan-jabatan-%d.pdf", id64)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, pdf.ContentType, data)
+}
+
+// mapComparisonToResponse converts a run comparison to ComparisonResponse DTO
+func mapComparisonToResponse(comparison *services.Comparison) dto.ComparisonResponse {
+	response := dto.ComparisonResponse{
+		JabatanID:    comparison.JabatanID,
+		BaseRunID:    comparison.BaseRunID,
+		CompareRunID: comparison.CompareRunID,
+		Movements:    make([]dto.RankMovementResponse, len(comparison.Movements)),
+	}
+	for i, m := range comparison.Movements {
+		movement := dto.RankMovementResponse{
+			TenagaKerjaID:   m.TenagaKerjaID,
+			OldRank:         m.OldRank,
+			NewRank:         m.NewRank,
+			RankChange:      m.RankChange,
+			OldScore:        m.OldScore,
+			NewScore:        m.NewScore,
+			ScoreDelta:      m.ScoreDelta,
+			KriteriaChanges: make([]dto.KriteriaGapChangeResponse, len(m.KriteriaChanges)),
+		}
+		if m.TenagaKerja.ID != 0 {
+			tk := dto.MapTenagaKerjaToResponse(&m.TenagaKerja)
+			movement.TenagaKerja = &tk
+		}
+		for j, c := range m.KriteriaChanges {
+			movement.KriteriaChanges[j] = dto.KriteriaGapChangeResponse{
+				KriteriaID: c.KriteriaID,
+				Kode:       c.Kode,
+				Nama:       c.Nama,
+				OldGap:     c.OldGap,
+				NewGap:     c.NewGap,
+			}
+		}
+		response.Movements[i] = movement
+	}
+	return response
+}
+
+// mapAssignmentPlanToResponse converts an AssignmentPlan to AssignmentResponse DTO
+func mapAssignmentPlanToResponse(plan *services.AssignmentPlan) dto.AssignmentResponse {
+	jabatanResponse := func(id uint) *dto.JabatanResponse {
+		jabatan, ok := plan.Jabatan[id]
+		if !ok {
+			return nil
+		}
+		response := dto.MapJabatanToResponse(&jabatan)
+		return &response
+	}
+
+	response := dto.AssignmentResponse{
+		Assignments: make([]dto.AssignmentItemResponse, len(plan.Assignments)),
+		Unfilled:    make([]dto.VacancyResponse, len(plan.Unfilled)),
+		TotalScore:  plan.TotalScore,
+	}
+	for i, a := range plan.Assignments {
+		item := dto.AssignmentItemResponse{
+			TenagaKerjaID: a.TenagaKerjaID,
+			JabatanID:     a.JabatanID,
+			Jabatan:       jabatanResponse(a.JabatanID),
+			Score:         a.Score,
+			Pinned:        a.Pinned,
+		}
+		if tk, ok := plan.TenagaKerja[a.TenagaKerjaID]; ok && tk.ID != 0 {
+			tkResponse := dto.MapTenagaKerjaToResponse(&tk)
+			item.TenagaKerja = &tkResponse
+		}
+		if a.Alternative != nil {
+			item.Alternative = &dto.AlternativeResponse{
+				JabatanID: a.Alternative.JabatanID,
+				Jabatan:   jabatanResponse(a.Alternative.JabatanID),
+				Score:     a.Alternative.Score,
+			}
+		}
+		response.Assignments[i] = item
+	}
+	for i, v := range plan.Unfilled {
+		response.Unfilled[i] = dto.VacancyResponse{JabatanID: v.JabatanID, Openings: v.Openings}
+	}
+	return response
+}
@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"backend/internal/models"
 	"backend/internal/repositories"
 	"backend/internal/services"
+	"backend/pkg/xlsx"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestProfileMatchingController_Calculate(t *testing.T) {
@@ -26,17 +29,9 @@ func TestProfileMatchingController_Calculate(t *testing.T) {
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
 	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
-	profileMatchResultRepo := repositories.NewProfileMatchResultRepository(db)
 
 	// Setup services
-	profileMatchingSvc := services.NewProfileMatchingService(
-		targetProfileRepo,
-		kriteriaRepo,
-		nilaiTenagaKerjaRepo,
-		tenagaKerjaRepo,
-		profileMatchResultRepo,
-		jabatanRepo,
-	)
+	profileMatchingSvc := newTestProfileMatchingService(db)
 
 	// Setup controller
 	profileMatchingCtrl := NewProfileMatchingController(profileMatchingSvc)
@@ -94,21 +89,11 @@ func TestProfileMatchingController_GetAllResults(t *testing.T) {
 
 	// Setup repositories
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	kriteriaRepo := repositories.NewKriteriaRepository(db)
-	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
-	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
 	profileMatchResultRepo := repositories.NewProfileMatchResultRepository(db)
 
 	// Setup services
-	profileMatchingSvc := services.NewProfileMatchingService(
-		targetProfileRepo,
-		kriteriaRepo,
-		nilaiTenagaKerjaRepo,
-		tenagaKerjaRepo,
-		profileMatchResultRepo,
-		jabatanRepo,
-	)
+	profileMatchingSvc := newTestProfileMatchingService(db)
 
 	// Setup controller
 	profileMatchingCtrl := NewProfileMatchingController(profileMatchingSvc)
@@ -149,21 +134,11 @@ func TestProfileMatchingController_GetResultByID(t *testing.T) {
 
 	// Setup repositories
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	kriteriaRepo := repositories.NewKriteriaRepository(db)
-	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
-	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
 	profileMatchResultRepo := repositories.NewProfileMatchResultRepository(db)
 
 	// Setup services
-	profileMatchingSvc := services.NewProfileMatchingService(
-		targetProfileRepo,
-		kriteriaRepo,
-		nilaiTenagaKerjaRepo,
-		tenagaKerjaRepo,
-		profileMatchResultRepo,
-		jabatanRepo,
-	)
+	profileMatchingSvc := newTestProfileMatchingService(db)
 
 	// Setup controller
 	profileMatchingCtrl := NewProfileMatchingController(profileMatchingSvc)
@@ -200,3 +175,441 @@ func TestProfileMatchingController_GetResultByID(t *testing.T) {
 	assert.Contains(t, response, "total_score")
 }
 
+func newTestProfileMatchingService(db *gorm.DB) *services.ProfileMatchingService {
+	return services.NewProfileMatchingService(
+		repositories.NewTargetProfileRepository(db),
+		repositories.NewKriteriaRepository(db),
+		repositories.NewNilaiTenagaKerjaRepository(db),
+		repositories.NewTenagaKerjaRepository(db),
+		repositories.NewProfileMatchResultRepository(db),
+		repositories.NewJabatanRepository(db),
+		repositories.NewGapWeightTableRepository(db),
+		repositories.NewCalculationRunRepository(db),
+		repositories.NewResultStatusHistoryRepository(db),
+	)
+}
+
+// seedProfileMatchingJabatan creates a jabatan targeting nilai 3 on a core
+// and a secondary kriteria, and two tenaga kerja assessed on both
+func seedProfileMatchingJabatan(db *gorm.DB) (*models.Jabatan, []models.Kriteria, []models.TenagaKerja) {
+	jabatan := &models.Jabatan{Nama: "Supervisor"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+
+	aspek := &models.Aspek{Nama: "Teknis", Persentase: 100}
+	repositories.NewAspekRepository(db).Create(aspek)
+
+	kriteria := []models.Kriteria{
+		{AspekID: aspek.ID, Kode: "K1", Nama: "Kriteria 1", IsCore: true, Bobot: 1},
+		{AspekID: aspek.ID, Kode: "K2", Nama: "Kriteria 2", IsCore: false, Bobot: 1},
+	}
+	tenagaKerja := []models.TenagaKerja{{NIK: "TK101", Nama: "Ani"}, {NIK: "TK102", Nama: "Budi"}}
+	nilai := [][]float64{{3, 1}, {2, 3}}
+
+	kriteriaRepo := repositories.NewKriteriaRepository(db)
+	targetProfileRepo := repositories.NewTargetProfileRepository(db)
+	for i := range kriteria {
+		kriteriaRepo.Create(&kriteria[i])
+		targetProfileRepo.Create(&models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: kriteria[i].ID, TargetNilai: 3})
+	}
+
+	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
+	nilaiRepo := repositories.NewNilaiTenagaKerjaRepository(db)
+	for i := range tenagaKerja {
+		tenagaKerjaRepo.Create(&tenagaKerja[i])
+		for j := range kriteria {
+			nilaiRepo.Create(&models.NilaiTenagaKerja{TenagaKerjaID: tenagaKerja[i].ID, KriteriaID: kriteria[j].ID, Nilai: nilai[i][j]})
+		}
+	}
+
+	return jabatan, kriteria, tenagaKerja
+}
+
+func TestProfileMatchingController_Sensitivity(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingCtrl := NewProfileMatchingController(newTestProfileMatchingService(db))
+	jabatan, kriteria, _ := seedProfileMatchingJabatan(db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/profile-matching/sensitivity", profileMatchingCtrl.Sensitivity)
+
+	payload := map[string]interface{}{
+		"jabatan_id":         jabatan.ID,
+		"top_n":              1,
+		"core_factor_persen": map[string]interface{}{"min": 40, "max": 80, "step": 20},
+		"target_nilai":       []map[string]interface{}{{"kriteria_id": kriteria[0].ID, "min": 2, "max": 3, "step": 1}},
+	}
+
+	payloadBytes, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", "/api/profile-matching/sensitivity", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, float64(6), response["scenarios"])
+	assert.Len(t, response["tenaga_kerja"], 2)
+
+	results, _ := repositories.NewProfileMatchResultRepository(db).GetByJabatanID(jabatan.ID)
+	assert.Empty(t, results)
+}
+
+func TestProfileMatchingController_Calculate_DryRun(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingCtrl := NewProfileMatchingController(newTestProfileMatchingService(db))
+	jabatan, kriteria, tenagaKerja := seedProfileMatchingJabatan(db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/profile-matching/calculate", profileMatchingCtrl.Calculate)
+
+	// Ani improves K2 from 1 to 3 and now matches the target exactly
+	payload := map[string]interface{}{
+		"jabatan_id": jabatan.ID,
+		"dry_run":    true,
+		"nilai_overrides": []map[string]interface{}{
+			{"tenaga_kerja_id": tenagaKerja[0].ID, "kriteria_id": kriteria[1].ID, "nilai": 3},
+		},
+	}
+
+	payloadBytes, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", "/api/profile-matching/calculate", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response, 2)
+	assert.Equal(t, float64(tenagaKerja[0].ID), response[0]["tenaga_kerja_id"])
+	assert.Equal(t, float64(5), response[0]["total_score"])
+	assert.Contains(t, response[0], "details")
+
+	results, _ := repositories.NewProfileMatchResultRepository(db).GetByJabatanID(jabatan.ID)
+	assert.Empty(t, results)
+}
+
+func TestProfileMatchingController_Calculate_OverridesWithoutDryRun(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingCtrl := NewProfileMatchingController(newTestProfileMatchingService(db))
+	jabatan, kriteria, _ := seedProfileMatchingJabatan(db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/profile-matching/calculate", profileMatchingCtrl.Calculate)
+
+	payload := map[string]interface{}{
+		"jabatan_id":       jabatan.ID,
+		"target_overrides": []map[string]interface{}{{"kriteria_id": kriteria[0].ID, "target_nilai": 4}},
+	}
+
+	payloadBytes, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", "/api/profile-matching/calculate", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProfileMatchingController_Runs(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingSvc := newTestProfileMatchingService(db)
+	profileMatchingCtrl := NewProfileMatchingController(profileMatchingSvc)
+	jabatan, _, _ := seedProfileMatchingJabatan(db)
+
+	results, err := profileMatchingSvc.Calculate(services.CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+	runID := *results[0].RunID
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/profile-matching/runs", profileMatchingCtrl.GetRuns)
+	router.GET("/api/profile-matching/runs/:id", profileMatchingCtrl.GetRunByID)
+	router.PUT("/api/profile-matching/runs/:id/official", profileMatchingCtrl.MarkRunOfficial)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/profile-matching/runs?jabatan_id=%d", jabatan.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var runs []map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &runs)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, "completed", runs[0]["status"])
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/profile-matching/runs/%d", runID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var run map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &run)
+	assert.Len(t, run["ranking"], 2)
+
+	req = httptest.NewRequest("PUT", fmt.Sprintf("/api/profile-matching/runs/%d/official", runID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("PUT", "/api/profile-matching/runs/999999/official", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestProfileMatchingController_Compare_Simulation(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingSvc := newTestProfileMatchingService(db)
+	profileMatchingCtrl := NewProfileMatchingController(profileMatchingSvc)
+	jabatan, kriteria, tenagaKerja := seedProfileMatchingJabatan(db)
+
+	results, err := profileMatchingSvc.Calculate(services.CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/profile-matching/compare", profileMatchingCtrl.Compare)
+
+	// Ani improves K2 from 1 to 3 and overtakes Budi
+	payload := map[string]interface{}{
+		"base_run_id": *results[0].RunID,
+		"simulation": map[string]interface{}{
+			"nilai_overrides": []map[string]interface{}{
+				{"tenaga_kerja_id": tenagaKerja[0].ID, "kriteria_id": kriteria[1].ID, "nilai": 3},
+			},
+		},
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	req := httptest.NewRequest("POST", "/api/profile-matching/compare", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	movements := response["movements"].([]interface{})
+	assert.Len(t, movements, 2)
+	first := movements[0].(map[string]interface{})
+	assert.Equal(t, float64(tenagaKerja[0].ID), first["tenaga_kerja_id"])
+	assert.Equal(t, float64(1), first["new_rank"])
+	assert.Len(t, first["kriteria_changes"], 1)
+
+	req = httptest.NewRequest("POST", "/api/profile-matching/compare?format=csv", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "K2: -2.00 -> 0.00")
+}
+
+func TestProfileMatchingController_Calculate_Conflict(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingCtrl := NewProfileMatchingController(newTestProfileMatchingService(db))
+	jabatan, _, _ := seedProfileMatchingJabatan(db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/profile-matching/calculate", profileMatchingCtrl.Calculate)
+
+	payloadBytes, _ := json.Marshal(map[string]interface{}{"jabatan_id": jabatan.ID})
+
+	// Another instance is calculating the same jabatan
+	repositories.NewCalculationRunRepository(db).WithJabatanLock(jabatan.ID, func() error {
+		req := httptest.NewRequest("POST", "/api/profile-matching/calculate", bytes.NewBuffer(payloadBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		return nil
+	})
+
+	req := httptest.NewRequest("POST", "/api/profile-matching/calculate", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestProfileMatchingController_Assign(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingSvc := newTestProfileMatchingService(db)
+	profileMatchingCtrl := NewProfileMatchingController(profileMatchingSvc)
+	jabatan, kriteria, tenagaKerja := seedProfileMatchingJabatan(db)
+
+	// A second jabatan with the same target profile
+	other := &models.Jabatan{Nama: "Koordinator"}
+	repositories.NewJabatanRepository(db).Create(other)
+	for _, k := range kriteria {
+		repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: other.ID, KriteriaID: k.ID, TargetNilai: 3})
+	}
+	for _, id := range []uint{jabatan.ID, other.ID} {
+		_, err := profileMatchingSvc.Calculate(services.CalculationRequest{JabatanID: id})
+		assert.NoError(t, err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/profile-matching/assignments", profileMatchingCtrl.Assign)
+
+	// Ani tops both rankings; pinning Budi to the first jabatan moves Ani to the second
+	payloadBytes, _ := json.Marshal(map[string]interface{}{
+		"vacancies": []map[string]interface{}{
+			{"jabatan_id": jabatan.ID, "openings": 1},
+			{"jabatan_id": other.ID, "openings": 1},
+		},
+		"pinned": []map[string]interface{}{{"tenaga_kerja_id": tenagaKerja[1].ID, "jabatan_id": jabatan.ID}},
+	})
+	req := httptest.NewRequest("POST", "/api/profile-matching/assignments", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assignments := response["assignments"].([]interface{})
+	assert.Len(t, assignments, 2)
+	first := assignments[0].(map[string]interface{})
+	assert.Equal(t, float64(tenagaKerja[1].ID), first["tenaga_kerja_id"])
+	assert.Equal(t, true, first["pinned"])
+	second := assignments[1].(map[string]interface{})
+	assert.Equal(t, float64(tenagaKerja[0].ID), second["tenaga_kerja_id"])
+	assert.Equal(t, float64(jabatan.ID), second["alternative"].(map[string]interface{})["jabatan_id"])
+
+	// A jabatan without results cannot be assigned
+	empty := &models.Jabatan{Nama: "Staf"}
+	repositories.NewJabatanRepository(db).Create(empty)
+	payloadBytes, _ = json.Marshal(map[string]interface{}{
+		"vacancies": []map[string]interface{}{{"jabatan_id": empty.ID, "openings": 1}},
+	})
+	req = httptest.NewRequest("POST", "/api/profile-matching/assignments", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProfileMatchingController_Assign_MixedMethods(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingSvc := newTestProfileMatchingService(db)
+	profileMatchingCtrl := NewProfileMatchingController(profileMatchingSvc)
+	jabatan, kriteria, _ := seedProfileMatchingJabatan(db)
+
+	other := &models.Jabatan{Nama: "Koordinator"}
+	repositories.NewJabatanRepository(db).Create(other)
+	for _, k := range kriteria {
+		repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: other.ID, KriteriaID: k.ID, TargetNilai: 3})
+	}
+	// Profile matching scores are on a 0-5 scale, SAW scores on 0-100
+	_, err := profileMatchingSvc.Calculate(services.CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+	_, err = profileMatchingSvc.Calculate(services.CalculationRequest{JabatanID: other.ID, Method: "saw"})
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/profile-matching/assignments", profileMatchingCtrl.Assign)
+
+	payloadBytes, _ := json.Marshal(map[string]interface{}{
+		"vacancies": []map[string]interface{}{
+			{"jabatan_id": jabatan.ID, "openings": 1},
+			{"jabatan_id": other.ID, "openings": 1},
+		},
+	})
+	req := httptest.NewRequest("POST", "/api/profile-matching/assignments", bytes.NewBuffer(payloadBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"jabatan results use different methods"}`, w.Body.String())
+}
+
+func TestProfileMatchingController_ExportResults(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingSvc := newTestProfileMatchingService(db)
+	profileMatchingCtrl := NewProfileMatchingController(profileMatchingSvc)
+	jabatan, _, _ := seedProfileMatchingJabatan(db)
+	_, err := profileMatchingSvc.Calculate(services.CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/profile-matching/results/export", profileMatchingCtrl.ExportResults)
+	router.GET("/api/profile-matching/results/:id", profileMatchingCtrl.GetResultByID)
+
+	url := fmt.Sprintf("/api/profile-matching/results/export?jabatan_id=%d&gaps=true", jabatan.ID)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "rank,nik,nama,total_score,core_factor,secondary_factor,gap_K1,gap_K2", lines[0])
+	// Budi misses the core kriteria by one, Ani the secondary one by two
+	assert.True(t, strings.HasPrefix(lines[1], "1,TK102,Budi,"))
+	assert.True(t, strings.HasSuffix(lines[1], ",-1.00,0.00"))
+	assert.True(t, strings.HasSuffix(lines[2], ",0.00,-2.00"))
+
+	// XLSX is a zip archive
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/api/profile-matching/results/export?jabatan_id=%d&format=xlsx", jabatan.ID), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, xlsx.ContentType, w.Header().Get("Content-Type"))
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("PK")))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/api/profile-matching/results/export?jabatan_id=%d&format=pdf", jabatan.ID), nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/profile-matching/results/export?jabatan_id=999", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestProfileMatchingController_DecisionReport(t *testing.T) {
+	db := setupControllerTestDB(t)
+	profileMatchingSvc := newTestProfileMatchingService(db)
+	profileMatchingCtrl := NewProfileMatchingController(profileMatchingSvc)
+	jabatan, _, _ := seedProfileMatchingJabatan(db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/profile-matching/jabatan/:id/report", profileMatchingCtrl.DecisionReport)
+	url := fmt.Sprintf("/api/profile-matching/jabatan/%d/report", jabatan.ID)
+
+	// Nothing to report before a calculation
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	_, err := profileMatchingSvc.Calculate(services.CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "(Rincian Perhitungan: Budi \\(TK102\\)) Tj")
+	assert.Contains(t, w.Body.String(), "(Rincian Perhitungan: Ani \\(TK101\\)) Tj")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/profile-matching/jabatan/999/report", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -76,12 +76,13 @@ func (tpc *TargetProfileController) Create(c *gin.Context) {
 	}
 
 	profile := &models.TargetProfile{
-		JabatanID:   req.JabatanID,
-		KriteriaID:  req.KriteriaID,
-		TargetNilai: req.TargetNilai,
+		JabatanID:    req.JabatanID,
+		KriteriaID:   req.KriteriaID,
+		TargetNilai:  req.TargetNilai,
+		NilaiMinimum: req.NilaiMinimum,
 	}
 
-	if err := tpc.targetProfileService.Create(profile); err != nil {
+	if err := tpc.targetProfileService.WithActor(actorID(c)).Create(profile); err != nil {
 		if err.Error() == "jabatan not found" || err.Error() == "kriteria not found" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -108,12 +109,14 @@ func (tpc *TargetProfileController) Update(c *gin.Context) {
 	}
 
 	profile := &models.TargetProfile{
-		JabatanID:   req.JabatanID,
-		KriteriaID:  req.KriteriaID,
-		TargetNilai: req.TargetNilai,
+		JabatanID:    req.JabatanID,
+		KriteriaID:   req.KriteriaID,
+		TargetNilai:  req.TargetNilai,
+		NilaiMinimum: req.NilaiMinimum,
 	}
 
-	if err := tpc.targetProfileService.Update(uint(id64), profile); err != nil {
+	opts := services.TargetProfileUpdateOptions{ResetNilaiMinimum: req.ResetNilaiMinimum}
+	if err := tpc.targetProfileService.WithActor(actorID(c)).Update(uint(id64), profile, opts); err != nil {
 		if err.Error() == "target profile not found" || err.Error() == "jabatan not found" || err.Error() == "kriteria not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -133,7 +136,7 @@ func (tpc *TargetProfileController) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := tpc.targetProfileService.Delete(uint(id64)); err != nil {
+	if err := tpc.targetProfileService.WithActor(actorID(c)).Delete(uint(id64)); err != nil {
 		if err.Error() == "target profile not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -23,7 +23,7 @@ func TestTargetProfileController_GetAll(t *testing.T) {
 	kriteriaRepo := repositories.NewKriteriaRepository(db)
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	aspekService := services.NewAspekService(aspekRepo)
 	kriteriaService := services.NewKriteriaService(kriteriaRepo, aspekRepo)
 	targetProfileService := services.NewTargetProfileService(targetProfileRepo, jabatanRepo, kriteriaRepo)
@@ -65,7 +65,7 @@ func TestTargetProfileController_Create(t *testing.T) {
 	kriteriaRepo := repositories.NewKriteriaRepository(db)
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	aspekService := services.NewAspekService(aspekRepo)
 	kriteriaService := services.NewKriteriaService(kriteriaRepo, aspekRepo)
 	targetProfileService := services.NewTargetProfileService(targetProfileRepo, jabatanRepo, kriteriaRepo)
@@ -110,7 +110,7 @@ func TestTargetProfileController_GetByID(t *testing.T) {
 	kriteriaRepo := repositories.NewKriteriaRepository(db)
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	aspekService := services.NewAspekService(aspekRepo)
 	kriteriaService := services.NewKriteriaService(kriteriaRepo, aspekRepo)
 	targetProfileService := services.NewTargetProfileService(targetProfileRepo, jabatanRepo, kriteriaRepo)
@@ -150,7 +150,7 @@ func TestTargetProfileController_Update(t *testing.T) {
 	kriteriaRepo := repositories.NewKriteriaRepository(db)
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	aspekService := services.NewAspekService(aspekRepo)
 	kriteriaService := services.NewKriteriaService(kriteriaRepo, aspekRepo)
 	targetProfileService := services.NewTargetProfileService(targetProfileRepo, jabatanRepo, kriteriaRepo)
@@ -192,7 +192,7 @@ func TestTargetProfileController_Delete(t *testing.T) {
 	kriteriaRepo := repositories.NewKriteriaRepository(db)
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 
-	jabatanService := services.NewJabatanService(jabatanRepo)
+	jabatanService := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	aspekService := services.NewAspekService(aspekRepo)
 	kriteriaService := services.NewKriteriaService(kriteriaRepo, aspekRepo)
 	targetProfileService := services.NewTargetProfileService(targetProfileRepo, jabatanRepo, kriteriaRepo)
@@ -220,4 +220,3 @@ func TestTargetProfileController_Delete(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 }
-
@@ -1,8 +1,15 @@
 package controllers
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"backend/internal/dto"
 	"backend/internal/models"
@@ -64,7 +71,7 @@ func (tkc *TenagaKerjaController) Create(c *gin.Context) {
 		Telepon:  req.Telepon,
 	}
 
-	if err := tkc.tenagaKerjaService.Create(tenagaKerja); err != nil {
+	if err := tkc.tenagaKerjaService.WithActor(actorID(c)).Create(tenagaKerja); err != nil {
 		if err.Error() == "NIK sudah terdaftar" {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
@@ -98,7 +105,7 @@ func (tkc *TenagaKerjaController) Update(c *gin.Context) {
 		Telepon:  req.Telepon,
 	}
 
-	if err := tkc.tenagaKerjaService.Update(uint(id64), tenagaKerja); err != nil {
+	if err := tkc.tenagaKerjaService.WithActor(actorID(c)).Update(uint(id64), tenagaKerja); err != nil {
 		if err.Error() == "tenaga kerja not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -122,7 +129,7 @@ func (tkc *TenagaKerjaController) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := tkc.tenagaKerjaService.Delete(uint(id64)); err != nil {
+	if err := tkc.tenagaKerjaService.WithActor(actorID(c)).Delete(uint(id64)); err != nil {
 		if err.Error() == "tenaga kerja not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -133,3 +140,151 @@ func (tkc *TenagaKerjaController) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Tenaga kerja deleted successfully"})
 }
+
+// tenagaKerjaImportColumns are the CSV columns an import must have;
+// telepon is optional
+var tenagaKerjaImportColumns = []string{"nik", "nama", "tgl_lahir", "alamat"}
+
+// Import reads tenaga kerja from the CSV file in the "file" form field and
+// reports on each row. With ?dry_run=true nothing is saved; otherwise the
+// valid rows are inserted together. Columns are matched by header name and
+// may be separated by commas or semicolons.
+func (tkc *TenagaKerjaController) Import(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read CSV file"})
+		return
+	}
+	defer file.Close()
+
+	rows, err := tenagaKerjaImportRows(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	rows, err = tkc.tenagaKerjaService.WithActor(actorID(c)).Import(rows, dryRun)
+	if err != nil {
+		if err.Error() == "import has no rows" || err.Error() == "too many import rows" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not import tenaga kerja"})
+		return
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, mapTenagaKerjaImportToResponse(rows, dryRun))
+}
+
+// tenagaKerjaImportRows parses the CSV into import rows, recording missing
+// or malformed dates and addresses as row errors. It stops reading one row
+// past the import limit.
+func tenagaKerjaImportRows(r io.Reader) ([]services.TenagaKerjaImportRow, error) {
+	br := bufio.NewReader(r)
+	reader := csv.NewReader(br)
+	// Spreadsheets in Indonesian locales export with semicolons
+	if first, _ := br.Peek(4096); bytes.Count(firstLine(first), []byte(";")) > bytes.Count(firstLine(first), []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("CSV file has no header")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, name := range tenagaKerjaImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV file is missing column %s", name)
+		}
+	}
+
+	var rows []services.TenagaKerjaImportRow
+	for len(rows) <= services.MaxTenagaKerjaImportRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := services.TenagaKerjaImportRow{
+			Line: line,
+			TenagaKerja: models.TenagaKerja{
+				NIK:     field("nik"),
+				Nama:    field("nama"),
+				Alamat:  field("alamat"),
+				Telepon: field("telepon"),
+			},
+		}
+		if value := field("tgl_lahir"); value == "" {
+			row.Errors = append(row.Errors, "tanggal lahir tidak boleh kosong")
+		} else if date, err := dto.ParseDateOnly(value); err != nil {
+			row.Errors = append(row.Errors, "tanggal lahir tidak valid, gunakan format YYYY-MM-DD")
+		} else {
+			row.TenagaKerja.TglLahir = date.Time()
+		}
+		if row.TenagaKerja.Alamat == "" {
+			row.Errors = append(row.Errors, "alamat tidak boleh kosong")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func firstLine(b []byte) []byte {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		return b[:i]
+	}
+	return b
+}
+
+// mapTenagaKerjaImportToResponse converts the rows of a tenaga kerja import
+// to the per-row report
+func mapTenagaKerjaImportToResponse(rows []services.TenagaKerjaImportRow, dryRun bool) dto.TenagaKerjaImportResponse {
+	response := dto.TenagaKerjaImportResponse{DryRun: dryRun, Total: len(rows), Rows: make([]dto.TenagaKerjaImportRowResponse, len(rows))}
+	for i, row := range rows {
+		valid := len(row.Errors) == 0
+		response.Rows[i] = dto.TenagaKerjaImportRowResponse{
+			Line:   row.Line,
+			NIK:    row.TenagaKerja.NIK,
+			Nama:   row.TenagaKerja.Nama,
+			Valid:  valid,
+			Errors: append([]string{}, row.Errors...),
+			ID:     row.TenagaKerja.ID,
+		}
+		if valid {
+			response.Valid++
+		} else {
+			response.Invalid++
+		}
+		if row.TenagaKerja.ID != 0 {
+			response.Imported++
+		}
+	}
+	return response
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"backend/internal/models"
@@ -167,3 +168,22 @@ func TestTenagaKerjaController_Create_DuplicateNIK(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, w.Code)
 }
 
+func TestTenagaKerjaImportRows(t *testing.T) {
+	input := "\ufeffNIK;Nama;Tgl_Lahir;Alamat\n" +
+		"TK001;Ani;1990-05-01;Jl. Merdeka\n" +
+		"TK002;Budi;01/05/1990;\n" +
+		"TK003;Citra;;\"Jl. Sudirman;\nBlok A\"\n"
+	rows, err := tenagaKerjaImportRows(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Empty(t, rows[0].Errors)
+	assert.Equal(t, "1990-05-01", rows[0].TenagaKerja.TglLahir.Format("2006-01-02"))
+	assert.Equal(t, []string{"tanggal lahir tidak valid, gunakan format YYYY-MM-DD", "alamat tidak boleh kosong"}, rows[1].Errors)
+	assert.Equal(t, []string{"tanggal lahir tidak boleh kosong"}, rows[2].Errors)
+	assert.Equal(t, "Jl. Sudirman;\nBlok A", rows[2].TenagaKerja.Alamat)
+
+	_, err = tenagaKerjaImportRows(strings.NewReader("nik,nama,alamat\nTK001,Ani,Jl. Merdeka\n"))
+	assert.EqualError(t, err, "CSV file is missing column tgl_lahir")
+}
@@ -66,7 +66,7 @@ func (uc *UserController) Create(c *gin.Context) {
 		user.IsActive = *req.IsActive
 	}
 
-	if err := uc.userService.Create(user); err != nil {
+	if err := uc.userService.WithActor(actorID(c)).Create(user); err != nil {
 		if err.Error() == "email already exists" {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
@@ -102,7 +102,7 @@ func (uc *UserController) Update(c *gin.Context) {
 		user.IsActive = *req.IsActive
 	}
 
-	if err := uc.userService.Update(uint(id64), user); err != nil {
+	if err := uc.userService.WithActor(actorID(c)).Update(uint(id64), user); err != nil {
 		if err.Error() == "user not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
@@ -122,7 +122,7 @@ func (uc *UserController) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := uc.userService.Delete(uint(id64)); err != nil {
+	if err := uc.userService.WithActor(actorID(c)).Delete(uint(id64)); err != nil {
 		if err.Error() == "user not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
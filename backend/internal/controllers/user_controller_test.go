@@ -189,4 +189,3 @@ func TestUserController_Register(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &response)
 	assert.Equal(t, "newuser@example.com", response["email"])
 }
-
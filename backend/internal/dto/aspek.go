@@ -4,24 +4,33 @@ import "time"
 
 // AspekResponse represents aspek data in API response
 type AspekResponse struct {
-	ID         uint      `json:"id"`
-	Nama       string    `json:"nama"`
-	Deskripsi  string    `json:"deskripsi"`
-	Persentase float64   `json:"persentase"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         uint    `json:"id"`
+	Nama       string  `json:"nama"`
+	Deskripsi  string  `json:"deskripsi"`
+	Persentase float64 `json:"persentase"`
+	// Nil factor ratio means the jabatan's ratio is used
+	CoreFactorPersen      *float64  `json:"core_factor_persen"`
+	SecondaryFactorPersen *float64  `json:"secondary_factor_persen"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
 }
 
 // AspekCreateRequest represents aspek creation request
 type AspekCreateRequest struct {
-	Nama       string  `json:"nama" binding:"required"`
-	Deskripsi  string  `json:"deskripsi,omitempty"`
-	Persentase float64 `json:"persentase" binding:"required,min=0,max=100"`
+	Nama                  string   `json:"nama" binding:"required"`
+	Deskripsi             string   `json:"deskripsi,omitempty"`
+	Persentase            float64  `json:"persentase" binding:"required,min=0,max=100"`
+	CoreFactorPersen      *float64 `json:"core_factor_persen,omitempty" binding:"omitempty,min=0,max=100"`
+	SecondaryFactorPersen *float64 `json:"secondary_factor_persen,omitempty" binding:"omitempty,min=0,max=100"`
 }
 
 // AspekUpdateRequest represents aspek update request
 type AspekUpdateRequest struct {
-	Nama       string  `json:"nama,omitempty"`
-	Deskripsi  string  `json:"deskripsi,omitempty"`
-	Persentase float64 `json:"persentase,omitempty" binding:"omitempty,min=0,max=100"`
+	Nama                  string   `json:"nama,omitempty"`
+	Deskripsi             string   `json:"deskripsi,omitempty"`
+	Persentase            float64  `json:"persentase,omitempty" binding:"omitempty,min=0,max=100"`
+	CoreFactorPersen      *float64 `json:"core_factor_persen,omitempty" binding:"omitempty,min=0,max=100"`
+	SecondaryFactorPersen *float64 `json:"secondary_factor_persen,omitempty" binding:"omitempty,min=0,max=100"`
+	// ResetFactorRatio removes the aspek's ratio override
+	ResetFactorRatio bool `json:"reset_factor_ratio,omitempty"`
 }
@@ -0,0 +1,51 @@
+package dto
+
+// VacancyRequest is a jabatan with a number of openings to fill
+type VacancyRequest struct {
+	JabatanID uint `json:"jabatan_id" binding:"required"`
+	Openings  int  `json:"openings" binding:"required,gt=0"`
+}
+
+// AssignmentPair is a tenaga kerja pinned to, or excluded from, a jabatan
+type AssignmentPair struct {
+	TenagaKerjaID uint `json:"tenaga_kerja_id" binding:"required"`
+	JabatanID     uint `json:"jabatan_id" binding:"required"`
+}
+
+// AssignmentRequest represents an assignment of tenaga kerja to the openings of several jabatan
+type AssignmentRequest struct {
+	Vacancies []VacancyRequest `json:"vacancies" binding:"required,min=1,dive"`
+	Pinned    []AssignmentPair `json:"pinned,omitempty" binding:"dive"`
+	Excluded  []AssignmentPair `json:"excluded,omitempty" binding:"dive"`
+}
+
+// AlternativeResponse is the best jabatan a tenaga kerja could fill besides the assigned one
+type AlternativeResponse struct {
+	JabatanID uint             `json:"jabatan_id"`
+	Jabatan   *JabatanResponse `json:"jabatan,omitempty"`
+	Score     float64          `json:"score"`
+}
+
+// AssignmentItemResponse represents a tenaga kerja assigned to a jabatan
+type AssignmentItemResponse struct {
+	TenagaKerjaID uint                 `json:"tenaga_kerja_id"`
+	TenagaKerja   *TenagaKerjaResponse `json:"tenaga_kerja,omitempty"`
+	JabatanID     uint                 `json:"jabatan_id"`
+	Jabatan       *JabatanResponse     `json:"jabatan,omitempty"`
+	Score         float64              `json:"score"`
+	Pinned        bool                 `json:"pinned"`
+	Alternative   *AlternativeResponse `json:"alternative"`
+}
+
+// VacancyResponse represents openings of a jabatan left unfilled
+type VacancyResponse struct {
+	JabatanID uint `json:"jabatan_id"`
+	Openings  int  `json:"openings"`
+}
+
+// AssignmentResponse represents an optimal assignment
+type AssignmentResponse struct {
+	Assignments []AssignmentItemResponse `json:"assignments"`
+	Unfilled    []VacancyResponse        `json:"unfilled"`
+	TotalScore  float64                  `json:"total_score"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// AuditChangeResponse represents the old and new value of a changed field
+type AuditChangeResponse struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditLogResponse represents an audit log entry in API response
+type AuditLogResponse struct {
+	ID        uint                           `json:"id"`
+	UserID    *uint                          `json:"user_id"`
+	User      *UserResponse                  `json:"user,omitempty"`
+	Entity    string                         `json:"entity"`
+	EntityID  uint                           `json:"entity_id"`
+	Action    string                         `json:"action"`
+	Changes   map[string]AuditChangeResponse `json:"changes"`
+	CreatedAt time.Time                      `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// CalculationJobCreateRequest represents a background calculation request
+type CalculationJobCreateRequest struct {
+	// JabatanIDs are calculated in order; empty calculates every jabatan
+	JabatanIDs    []uint   `json:"jabatan_ids,omitempty"`
+	GapMode       string   `json:"gap_mode,omitempty" binding:"omitempty,oneof=exact interpolate round"`
+	RoundingRule  string   `json:"rounding_rule,omitempty" binding:"omitempty,oneof=nearest half_up half_down floor ceil"`
+	MissingPolicy string   `json:"missing_policy,omitempty" binding:"omitempty,oneof=skip minimum exclude incomplete"`
+	MinimumNilai  *float64 `json:"minimum_nilai,omitempty"`
+	Method        string   `json:"method,omitempty" binding:"omitempty,oneof=profile_matching saw wp topsis"`
+	RankingStyle  string   `json:"ranking_style,omitempty" binding:"omitempty,oneof=ordinal competition dense"`
+	TieBreakers   []string `json:"tie_breakers,omitempty"`
+}
+
+// CalculationJobResponse represents calculation job state in API response
+type CalculationJobResponse struct {
+	ID              uint       `json:"id"`
+	UserID          *uint      `json:"user_id"`
+	JabatanIDs      []uint     `json:"jabatan_ids"`
+	Status          string     `json:"status"`
+	CancelRequested bool       `json:"cancel_requested"`
+	Processed       int        `json:"processed"`
+	Total           int        `json:"total"`
+	Errors          []string   `json:"errors"`
+	RunIDs          []uint     `json:"run_ids"`
+	StartedAt       *time.Time `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,117 @@
+package dto
+
+import "time"
+
+// CalculationParametersResponse represents the settings a calculation run used
+type CalculationParametersResponse struct {
+	TenagaKerjaIDs        []uint   `json:"tenaga_kerja_ids,omitempty"`
+	GapWeightTableID      *uint    `json:"gap_weight_table_id,omitempty"`
+	GapMode               string   `json:"gap_mode"`
+	RoundingRule          string   `json:"rounding_rule,omitempty"`
+	MissingPolicy         string   `json:"missing_policy"`
+	MinimumNilai          float64  `json:"minimum_nilai,omitempty"`
+	Method                string   `json:"method"`
+	RankingStyle          string   `json:"ranking_style,omitempty"`
+	TieBreakers           []string `json:"tie_breakers,omitempty"`
+	CoreFactorPersen      float64  `json:"core_factor_persen"`
+	SecondaryFactorPersen float64  `json:"secondary_factor_persen"`
+}
+
+// CalculationRunResponse represents calculation run data in API response
+type CalculationRunResponse struct {
+	ID         uint                          `json:"id"`
+	JabatanID  uint                          `json:"jabatan_id"`
+	Jabatan    *JabatanResponse              `json:"jabatan,omitempty"`
+	UserID     *uint                         `json:"user_id"`
+	User       *UserResponse                 `json:"user,omitempty"`
+	Parameters CalculationParametersResponse `json:"parameters"`
+	Status     string                        `json:"status"`
+	Error      string                        `json:"error,omitempty"`
+	IsOfficial bool                          `json:"is_official"`
+	CreatedAt  time.Time                     `json:"created_at"`
+	UpdatedAt  time.Time                     `json:"updated_at"`
+}
+
+// CalculationRunDetailResponse represents a calculation run with its ranking
+// and the tenaga kerja knocked out of it
+type CalculationRunDetailResponse struct {
+	CalculationRunResponse
+	Ranking    []RankingResponse          `json:"ranking"`
+	Ineligible []IneligibleResultResponse `json:"ineligible"`
+}
+
+// KnockoutFailureResponse represents a nilai minimum a tenaga kerja falls short of
+type KnockoutFailureResponse struct {
+	KriteriaID   uint     `json:"kriteria_id"`
+	Kode         string   `json:"kode"`
+	Nama         string   `json:"nama"`
+	NilaiMinimum float64  `json:"nilai_minimum"`
+	Nilai        *float64 `json:"nilai"` // Nil when the nilai is missing
+}
+
+// IneligibleResultResponse represents a tenaga kerja knocked out of a ranking
+type IneligibleResultResponse struct {
+	ID               uint                      `json:"id"`
+	RunID            uint                      `json:"run_id"`
+	TenagaKerjaID    uint                      `json:"tenaga_kerja_id"`
+	TenagaKerja      *TenagaKerjaResponse      `json:"tenaga_kerja,omitempty"`
+	JabatanID        uint                      `json:"jabatan_id"`
+	Failures         []KnockoutFailureResponse `json:"failures"`
+	BelowSkorMinimum bool                      `json:"below_skor_minimum"`
+	TotalScore       float64                   `json:"total_score,omitempty"` // Set when below the skor minimum
+	CreatedAt        time.Time                 `json:"created_at"`
+}
+
+// CompareSimulation is a dry-run calculation compared against a stored run;
+// settings left empty are taken from the run
+type CompareSimulation struct {
+	TenagaKerjaIDs  []uint           `json:"tenaga_kerja_ids,omitempty"`
+	GapMode         string           `json:"gap_mode,omitempty" binding:"omitempty,oneof=exact interpolate round"`
+	RoundingRule    string           `json:"rounding_rule,omitempty" binding:"omitempty,oneof=nearest half_up half_down floor ceil"`
+	MissingPolicy   string           `json:"missing_policy,omitempty" binding:"omitempty,oneof=skip minimum exclude incomplete"`
+	MinimumNilai    *float64         `json:"minimum_nilai,omitempty"`
+	Method          string           `json:"method,omitempty" binding:"omitempty,oneof=profile_matching saw wp topsis"`
+	RankingStyle    string           `json:"ranking_style,omitempty" binding:"omitempty,oneof=ordinal competition dense"`
+	TieBreakers     []string         `json:"tie_breakers,omitempty"`
+	NilaiOverrides  []NilaiOverride  `json:"nilai_overrides,omitempty" binding:"dive"`
+	TargetOverrides []TargetOverride `json:"target_overrides,omitempty" binding:"dive"`
+	CoreOverrides   []CoreOverride   `json:"core_overrides,omitempty" binding:"dive"`
+}
+
+// CompareRequest compares a stored run with another run of the same jabatan
+// or with a simulation; exactly one of CompareRunID and Simulation is required
+type CompareRequest struct {
+	BaseRunID    uint               `json:"base_run_id" binding:"required"`
+	CompareRunID *uint              `json:"compare_run_id,omitempty"`
+	Simulation   *CompareSimulation `json:"simulation,omitempty"`
+}
+
+// KriteriaGapChangeResponse represents a kriteria whose gap changed; a null gap means it was not evaluated
+type KriteriaGapChangeResponse struct {
+	KriteriaID uint     `json:"kriteria_id"`
+	Kode       string   `json:"kode"`
+	Nama       string   `json:"nama"`
+	OldGap     *float64 `json:"old_gap"`
+	NewGap     *float64 `json:"new_gap"`
+}
+
+// RankMovementResponse represents a tenaga kerja's movement; ranks are 0 when absent from a ranking
+type RankMovementResponse struct {
+	TenagaKerjaID   uint                        `json:"tenaga_kerja_id"`
+	TenagaKerja     *TenagaKerjaResponse        `json:"tenaga_kerja,omitempty"`
+	OldRank         int                         `json:"old_rank"`
+	NewRank         int                         `json:"new_rank"`
+	RankChange      int                         `json:"rank_change"`
+	OldScore        float64                     `json:"old_score"`
+	NewScore        float64                     `json:"new_score"`
+	ScoreDelta      float64                     `json:"score_delta"`
+	KriteriaChanges []KriteriaGapChangeResponse `json:"kriteria_changes"`
+}
+
+// ComparisonResponse represents the rank movements between two rankings of a jabatan
+type ComparisonResponse struct {
+	JabatanID    uint                   `json:"jabatan_id"`
+	BaseRunID    uint                   `json:"base_run_id"`
+	CompareRunID *uint                  `json:"compare_run_id,omitempty"`
+	Movements    []RankMovementResponse `json:"movements"`
+}
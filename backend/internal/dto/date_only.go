@@ -13,21 +13,29 @@ func (d *DateOnly) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
+	t, err := ParseDateOnly(s)
+	if err != nil {
+		return err
+	}
+
+	*d = t
+	return nil
+}
+
+// ParseDateOnly parses a date the way DateOnly accepts it in JSON
+func ParseDateOnly(s string) (DateOnly, error) {
 	// coba format YYYY-MM-DD
 	t, err := time.Parse("2006-01-02", s)
 	if err == nil {
-		*d = DateOnly(t)
-		return nil
+		return DateOnly(t), nil
 	}
 
 	// fallback ke RFC3339 (biar kompatibel juga)
 	t, err = time.Parse(time.RFC3339, s)
 	if err != nil {
-		return err
+		return DateOnly{}, err
 	}
-
-	*d = DateOnly(t)
-	return nil
+	return DateOnly(t), nil
 }
 
 func (d DateOnly) Time() time.Time {
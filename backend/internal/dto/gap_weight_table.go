@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// GapWeightEntryResponse represents a single GAP to bobot nilai conversion
+type GapWeightEntryResponse struct {
+	Gap   float64 `json:"gap"`
+	Bobot float64 `json:"bobot"`
+}
+
+// GapWeightTableResponse represents GAP weight table data in API response
+type GapWeightTableResponse struct {
+	ID         uint                     `json:"id"`
+	Nama       string                   `json:"nama"`
+	Deskripsi  string                   `json:"deskripsi"`
+	BelowRange string                   `json:"below_range"`
+	AboveRange string                   `json:"above_range"`
+	Entries    []GapWeightEntryResponse `json:"entries"`
+	CreatedAt  time.Time                `json:"created_at"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+}
+
+// GapWeightEntryRequest represents a single entry in a GAP weight table request
+type GapWeightEntryRequest struct {
+	Gap   float64 `json:"gap"`
+	Bobot float64 `json:"bobot" binding:"min=0"`
+}
+
+// GapWeightTableCreateRequest represents GAP weight table creation request
+type GapWeightTableCreateRequest struct {
+	Nama       string                  `json:"nama" binding:"required"`
+	Deskripsi  string                  `json:"deskripsi,omitempty"`
+	BelowRange string                  `json:"below_range,omitempty" binding:"omitempty,oneof=zero clamp"`
+	AboveRange string                  `json:"above_range,omitempty" binding:"omitempty,oneof=zero clamp"`
+	Entries    []GapWeightEntryRequest `json:"entries" binding:"required,min=1,dive"`
+}
+
+// GapWeightTableUpdateRequest represents GAP weight table update request.
+// When Entries is present it replaces all existing entries.
+type GapWeightTableUpdateRequest struct {
+	Nama       string                  `json:"nama,omitempty"`
+	Deskripsi  string                  `json:"deskripsi,omitempty"`
+	BelowRange string                  `json:"below_range,omitempty" binding:"omitempty,oneof=zero clamp"`
+	AboveRange string                  `json:"above_range,omitempty" binding:"omitempty,oneof=zero clamp"`
+	Entries    []GapWeightEntryRequest `json:"entries,omitempty" binding:"omitempty,min=1,dive"`
+}
@@ -4,22 +4,43 @@ import "time"
 
 // JabatanResponse represents jabatan data in API response
 type JabatanResponse struct {
-	ID        uint      `json:"id"`
-	Nama      string    `json:"nama"`
-	Deskripsi string    `json:"deskripsi"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID               uint   `json:"id"`
+	Nama             string `json:"nama"`
+	Deskripsi        string `json:"deskripsi"`
+	GapWeightTableID *uint  `json:"gap_weight_table_id"`
+	// Nil factor ratio means the system default is used
+	CoreFactorPersen      *float64  `json:"core_factor_persen"`
+	SecondaryFactorPersen *float64  `json:"secondary_factor_persen"`
+	JumlahLowongan        int       `json:"jumlah_lowongan"`
+	SkorMinimum           *float64  `json:"skor_minimum"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
 }
 
 // JabatanCreateRequest represents jabatan creation request
 type JabatanCreateRequest struct {
-	Nama      string `json:"nama" binding:"required"`
-	Deskripsi string `json:"deskripsi,omitempty"`
+	Nama                  string   `json:"nama" binding:"required"`
+	Deskripsi             string   `json:"deskripsi,omitempty"`
+	GapWeightTableID      *uint    `json:"gap_weight_table_id,omitempty"`
+	CoreFactorPersen      *float64 `json:"core_factor_persen,omitempty" binding:"omitempty,min=0,max=100"`
+	SecondaryFactorPersen *float64 `json:"secondary_factor_persen,omitempty" binding:"omitempty,min=0,max=100"`
+	JumlahLowongan        int      `json:"jumlah_lowongan,omitempty" binding:"omitempty,min=0"`
+	// SkorMinimum knocks tenaga kerja with a lower total score out of the ranking
+	SkorMinimum *float64 `json:"skor_minimum,omitempty" binding:"omitempty,min=0"`
 }
 
 // JabatanUpdateRequest represents jabatan update request
 type JabatanUpdateRequest struct {
 	Nama      string `json:"nama,omitempty"`
 	Deskripsi string `json:"deskripsi,omitempty"`
+	// GapWeightTableID of 0 resets the jabatan to the default GAP weight table
+	GapWeightTableID      *uint    `json:"gap_weight_table_id,omitempty"`
+	CoreFactorPersen      *float64 `json:"core_factor_persen,omitempty" binding:"omitempty,min=0,max=100"`
+	SecondaryFactorPersen *float64 `json:"secondary_factor_persen,omitempty" binding:"omitempty,min=0,max=100"`
+	JumlahLowongan        *int     `json:"jumlah_lowongan,omitempty" binding:"omitempty,min=0"`
+	SkorMinimum           *float64 `json:"skor_minimum,omitempty" binding:"omitempty,min=0"`
+	// ResetSkorMinimum removes the knockout total score
+	ResetSkorMinimum bool `json:"reset_skor_minimum,omitempty"`
+	// ResetFactorRatio switches the jabatan back to the system default ratio
+	ResetFactorRatio bool `json:"reset_factor_ratio,omitempty"`
 }
-
@@ -1,6 +1,9 @@
 package dto
 
-import "backend/internal/models"
+import (
+	"backend/internal/models"
+	"backend/pkg/profilematching"
+)
 
 // MapUserToResponse converts User model to UserResponse DTO
 func MapUserToResponse(user *models.User) UserResponse {
@@ -27,11 +30,16 @@ func MapUsersToResponse(users []models.User) []UserResponse {
 // MapJabatanToResponse converts Jabatan model to JabatanResponse DTO
 func MapJabatanToResponse(jabatan *models.Jabatan) JabatanResponse {
 	return JabatanResponse{
-		ID:        jabatan.ID,
-		Nama:      jabatan.Nama,
-		Deskripsi: jabatan.Deskripsi,
-		CreatedAt: jabatan.CreatedAt,
-		UpdatedAt: jabatan.UpdatedAt,
+		ID:                    jabatan.ID,
+		Nama:                  jabatan.Nama,
+		Deskripsi:             jabatan.Deskripsi,
+		GapWeightTableID:      jabatan.GapWeightTableID,
+		CoreFactorPersen:      jabatan.CoreFactorPersen,
+		SecondaryFactorPersen: jabatan.SecondaryFactorPersen,
+		JumlahLowongan:        jabatan.JumlahLowongan,
+		SkorMinimum:           jabatan.SkorMinimum,
+		CreatedAt:             jabatan.CreatedAt,
+		UpdatedAt:             jabatan.UpdatedAt,
 	}
 }
 
@@ -44,15 +52,53 @@ func MapJabatansToResponse(jabatans []models.Jabatan) []JabatanResponse {
 	return result
 }
 
+// MapGapWeightTableToResponse converts GapWeightTable model to GapWeightTableResponse DTO
+func MapGapWeightTableToResponse(table *models.GapWeightTable) GapWeightTableResponse {
+	entries := make([]GapWeightEntryResponse, len(table.Entries))
+	for i, e := range table.Entries {
+		entries[i] = GapWeightEntryResponse{Gap: e.Gap, Bobot: e.Bobot}
+	}
+	return GapWeightTableResponse{
+		ID:         table.ID,
+		Nama:       table.Nama,
+		Deskripsi:  table.Deskripsi,
+		BelowRange: table.BelowRange,
+		AboveRange: table.AboveRange,
+		Entries:    entries,
+		CreatedAt:  table.CreatedAt,
+		UpdatedAt:  table.UpdatedAt,
+	}
+}
+
+// MapGapWeightTablesToResponse converts GapWeightTable slice to GapWeightTableResponse slice
+func MapGapWeightTablesToResponse(tables []models.GapWeightTable) []GapWeightTableResponse {
+	result := make([]GapWeightTableResponse, len(tables))
+	for i, table := range tables {
+		result[i] = MapGapWeightTableToResponse(&table)
+	}
+	return result
+}
+
+// MapGapWeightEntryRequests converts GapWeightEntryRequest slice to GapWeightEntry models
+func MapGapWeightEntryRequests(reqs []GapWeightEntryRequest) []models.GapWeightEntry {
+	entries := make([]models.GapWeightEntry, len(reqs))
+	for i, r := range reqs {
+		entries[i] = models.GapWeightEntry{Gap: r.Gap, Bobot: r.Bobot}
+	}
+	return entries
+}
+
 // MapAspekToResponse converts Aspek model to AspekResponse DTO
 func MapAspekToResponse(aspek *models.Aspek) AspekResponse {
 	return AspekResponse{
-		ID:         aspek.ID,
-		Nama:       aspek.Nama,
-		Deskripsi:  aspek.Deskripsi,
-		Persentase: aspek.Persentase,
-		CreatedAt:  aspek.CreatedAt,
-		UpdatedAt:  aspek.UpdatedAt,
+		ID:                    aspek.ID,
+		Nama:                  aspek.Nama,
+		Deskripsi:             aspek.Deskripsi,
+		Persentase:            aspek.Persentase,
+		CoreFactorPersen:      aspek.CoreFactorPersen,
+		SecondaryFactorPersen: aspek.SecondaryFactorPersen,
+		CreatedAt:             aspek.CreatedAt,
+		UpdatedAt:             aspek.UpdatedAt,
 	}
 }
 
@@ -77,12 +123,12 @@ func MapKriteriaToResponse(kriteria *models.Kriteria) KriteriaResponse {
 		CreatedAt: kriteria.CreatedAt,
 		UpdatedAt: kriteria.UpdatedAt,
 	}
-	
+
 	if kriteria.Aspek.ID != 0 {
 		aspek := MapAspekToResponse(&kriteria.Aspek)
 		response.Aspek = &aspek
 	}
-	
+
 	return response
 }
 
@@ -98,24 +144,25 @@ func MapKriteriasToResponse(kriterias []models.Kriteria) []KriteriaResponse {
 // MapTargetProfileToResponse converts TargetProfile model to TargetProfileResponse DTO
 func MapTargetProfileToResponse(tp *models.TargetProfile) TargetProfileResponse {
 	response := TargetProfileResponse{
-		ID:          tp.ID,
-		JabatanID:   tp.JabatanID,
-		KriteriaID:  tp.KriteriaID,
-		TargetNilai: tp.TargetNilai,
-		CreatedAt:   tp.CreatedAt,
-		UpdatedAt:   tp.UpdatedAt,
-	}
-	
+		ID:           tp.ID,
+		JabatanID:    tp.JabatanID,
+		KriteriaID:   tp.KriteriaID,
+		TargetNilai:  tp.TargetNilai,
+		NilaiMinimum: tp.NilaiMinimum,
+		CreatedAt:    tp.CreatedAt,
+		UpdatedAt:    tp.UpdatedAt,
+	}
+
 	if tp.Jabatan.ID != 0 {
 		jabatan := MapJabatanToResponse(&tp.Jabatan)
 		response.Jabatan = &jabatan
 	}
-	
+
 	if tp.Kriteria.ID != 0 {
 		kriteria := MapKriteriaToResponse(&tp.Kriteria)
 		response.Kriteria = &kriteria
 	}
-	
+
 	return response
 }
 
@@ -161,17 +208,17 @@ func MapNilaiTenagaKerjaToResponse(ntk *models.NilaiTenagaKerja) NilaiTenagaKerj
 		CreatedAt:     ntk.CreatedAt,
 		UpdatedAt:     ntk.UpdatedAt,
 	}
-	
+
 	if ntk.TenagaKerja.ID != 0 {
 		tk := MapTenagaKerjaToResponse(&ntk.TenagaKerja)
 		response.TenagaKerja = &tk
 	}
-	
+
 	if ntk.Kriteria.ID != 0 {
 		kriteria := MapKriteriaToResponse(&ntk.Kriteria)
 		response.Kriteria = &kriteria
 	}
-	
+
 	return response
 }
 
@@ -187,27 +234,42 @@ func MapNilaiTenagaKerjasToResponse(ntks []models.NilaiTenagaKerja) []NilaiTenag
 // MapProfileMatchResultToResponse converts ProfileMatchResult model to ProfileMatchResultResponse DTO
 func MapProfileMatchResultToResponse(pmr *models.ProfileMatchResult) ProfileMatchResultResponse {
 	response := ProfileMatchResultResponse{
-		ID:              pmr.ID,
-		TenagaKerjaID:   pmr.TenagaKerjaID,
-		JabatanID:       pmr.JabatanID,
-		TotalScore:      pmr.TotalScore,
-		CoreFactor:      pmr.CoreFactor,
-		SecondaryFactor: pmr.SecondaryFactor,
-		CreatedAt:       pmr.CreatedAt,
-		UpdatedAt:       pmr.UpdatedAt,
+		ID:                    pmr.ID,
+		RunID:                 pmr.RunID,
+		TenagaKerjaID:         pmr.TenagaKerjaID,
+		JabatanID:             pmr.JabatanID,
+		TotalScore:            pmr.TotalScore,
+		CoreFactor:            pmr.CoreFactor,
+		SecondaryFactor:       pmr.SecondaryFactor,
+		CoreFactorPersen:      pmr.CoreFactorPersen,
+		SecondaryFactorPersen: pmr.SecondaryFactorPersen,
+		GapMode:               pmr.GapMode,
+		RoundingRule:          pmr.RoundingRule,
+		MissingPolicy:         pmr.MissingPolicy,
+		KriteriaDinilai:       pmr.KriteriaDinilai,
+		KriteriaTotal:         pmr.KriteriaTotal,
+		MissingKriteria:       pmr.MissingKriteria,
+		Incomplete:            pmr.Incomplete,
+		Method:                pmr.Method,
+		RankingStyle:          pmr.RankingStyle,
+		TieBreakers:           pmr.TieBreakers,
+		Status:                pmr.Status,
+		Rank:                  pmr.Rank,
+		CreatedAt:             pmr.CreatedAt,
+		UpdatedAt:             pmr.UpdatedAt,
 	}
 	response.ScoreTotal = pmr.TotalScore // Alias for frontend compatibility
-	
+
 	if pmr.TenagaKerja.ID != 0 {
 		tk := MapTenagaKerjaToResponse(&pmr.TenagaKerja)
 		response.TenagaKerja = &tk
 	}
-	
+
 	if pmr.Jabatan.ID != 0 {
 		jabatan := MapJabatanToResponse(&pmr.Jabatan)
 		response.Jabatan = &jabatan
 	}
-	
+
 	return response
 }
 
@@ -220,6 +282,102 @@ func MapProfileMatchResultsToResponse(pmrs []models.ProfileMatchResult) []Profil
 	return result
 }
 
+// MapCalculationRunToResponse converts CalculationRun model to CalculationRunResponse DTO
+func MapCalculationRunToResponse(run *models.CalculationRun) CalculationRunResponse {
+	response := CalculationRunResponse{
+		ID:        run.ID,
+		JabatanID: run.JabatanID,
+		UserID:    run.UserID,
+		Parameters: CalculationParametersResponse{
+			TenagaKerjaIDs:        run.Parameters.TenagaKerjaIDs,
+			GapWeightTableID:      run.Parameters.GapWeightTableID,
+			GapMode:               run.Parameters.GapMode,
+			RoundingRule:          run.Parameters.RoundingRule,
+			MissingPolicy:         run.Parameters.MissingPolicy,
+			MinimumNilai:          run.Parameters.MinimumNilai,
+			Method:                run.Parameters.Method,
+			RankingStyle:          run.Parameters.RankingStyle,
+			TieBreakers:           run.Parameters.TieBreakers,
+			CoreFactorPersen:      run.Parameters.CoreFactorPersen,
+			SecondaryFactorPersen: run.Parameters.SecondaryFactorPersen,
+		},
+		Status:     run.Status,
+		Error:      run.Error,
+		IsOfficial: run.IsOfficial,
+		CreatedAt:  run.CreatedAt,
+		UpdatedAt:  run.UpdatedAt,
+	}
+
+	if run.Jabatan.ID != 0 {
+		jabatan := MapJabatanToResponse(&run.Jabatan)
+		response.Jabatan = &jabatan
+	}
+
+	if run.User != nil && run.User.ID != 0 {
+		user := MapUserToResponse(run.User)
+		response.User = &user
+	}
+
+	return response
+}
+
+// MapCalculationRunsToResponse converts CalculationRun slice to CalculationRunResponse slice
+func MapCalculationRunsToResponse(runs []models.CalculationRun) []CalculationRunResponse {
+	result := make([]CalculationRunResponse, len(runs))
+	for i, run := range runs {
+		result[i] = MapCalculationRunToResponse(&run)
+	}
+	return result
+}
+
+// MapCalculationRunToDetailResponse converts CalculationRun with its results to CalculationRunDetailResponse
+func MapCalculationRunToDetailResponse(run *models.CalculationRun, results []models.ProfileMatchResult, ineligible []models.IneligibleResult) CalculationRunDetailResponse {
+	return CalculationRunDetailResponse{
+		CalculationRunResponse: MapCalculationRunToResponse(run),
+		Ranking:                MapProfileMatchResultsToRankingResponse(results),
+		Ineligible:             MapIneligibleResultsToResponse(ineligible),
+	}
+}
+
+// MapIneligibleResultToResponse converts IneligibleResult model to IneligibleResultResponse DTO
+func MapIneligibleResultToResponse(ir *models.IneligibleResult) IneligibleResultResponse {
+	response := IneligibleResultResponse{
+		ID:               ir.ID,
+		RunID:            ir.RunID,
+		TenagaKerjaID:    ir.TenagaKerjaID,
+		JabatanID:        ir.JabatanID,
+		Failures:         make([]KnockoutFailureResponse, len(ir.Failures)),
+		BelowSkorMinimum: ir.BelowSkorMinimum,
+		TotalScore:       ir.TotalScore,
+		CreatedAt:        ir.CreatedAt,
+	}
+	for i, f := range ir.Failures {
+		response.Failures[i] = KnockoutFailureResponse{
+			KriteriaID:   f.KriteriaID,
+			Kode:         f.Kode,
+			Nama:         f.Nama,
+			NilaiMinimum: f.NilaiMinimum,
+			Nilai:        f.Nilai,
+		}
+	}
+
+	if ir.TenagaKerja.ID != 0 {
+		tk := MapTenagaKerjaToResponse(&ir.TenagaKerja)
+		response.TenagaKerja = &tk
+	}
+
+	return response
+}
+
+// MapIneligibleResultsToResponse converts IneligibleResult slice to IneligibleResultResponse slice
+func MapIneligibleResultsToResponse(list []models.IneligibleResult) []IneligibleResultResponse {
+	result := make([]IneligibleResultResponse, len(list))
+	for i, ir := range list {
+		result[i] = MapIneligibleResultToResponse(&ir)
+	}
+	return result
+}
+
 // MapProfileMatchResultToRankingResponse converts ProfileMatchResult to RankingResponse with rank
 func MapProfileMatchResultToRankingResponse(pmr *models.ProfileMatchResult, rank int) RankingResponse {
 	response := RankingResponse{
@@ -228,27 +386,36 @@ func MapProfileMatchResultToRankingResponse(pmr *models.ProfileMatchResult, rank
 		ScoreTotal:      pmr.TotalScore,
 		CoreFactor:      pmr.CoreFactor,
 		SecondaryFactor: pmr.SecondaryFactor,
+		KriteriaDinilai: pmr.KriteriaDinilai,
+		KriteriaTotal:   pmr.KriteriaTotal,
+		Status:          pmr.Status,
+		Incomplete:      pmr.Incomplete,
 		CreatedAt:       pmr.CreatedAt,
 	}
-	
+
 	if pmr.TenagaKerja.ID != 0 {
 		tk := MapTenagaKerjaToResponse(&pmr.TenagaKerja)
 		response.TenagaKerja = &tk
 	}
-	
+
 	if pmr.Jabatan.ID != 0 {
 		jabatan := MapJabatanToResponse(&pmr.Jabatan)
 		response.Jabatan = &jabatan
 	}
-	
+
 	return response
 }
 
-// MapProfileMatchResultsToRankingResponse converts ProfileMatchResult slice to RankingResponse slice with ranks
+// MapProfileMatchResultsToRankingResponse converts ProfileMatchResult slice to RankingResponse slice with ranks;
+// results stored before ranks were recorded are numbered by position
 func MapProfileMatchResultsToRankingResponse(pmrs []models.ProfileMatchResult) []RankingResponse {
 	result := make([]RankingResponse, len(pmrs))
 	for i, pmr := range pmrs {
-		result[i] = MapProfileMatchResultToRankingResponse(&pmr, i+1)
+		rank := pmr.Rank
+		if rank == 0 {
+			rank = i + 1
+		}
+		result[i] = MapProfileMatchResultToRankingResponse(&pmr, rank)
 	}
 	return result
 }
@@ -256,16 +423,30 @@ func MapProfileMatchResultsToRankingResponse(pmrs []models.ProfileMatchResult) [
 // MapProfileMatchResultToDetailResponse converts ProfileMatchResult with details to ProfileMatchResultDetailResponse
 func MapProfileMatchResultToDetailResponse(pmr *models.ProfileMatchResult, details map[string]interface{}, rank int) ProfileMatchResultDetailResponse {
 	response := ProfileMatchResultDetailResponse{
-		ID:              pmr.ID,
-		TenagaKerjaID:   pmr.TenagaKerjaID,
-		JabatanID:       pmr.JabatanID,
-		TotalScore:      pmr.TotalScore,
-		CoreFactor:      pmr.CoreFactor,
-		SecondaryFactor: pmr.SecondaryFactor,
-		Rank:            rank,
-		ScoreTotal:      pmr.TotalScore,
-		CreatedAt:       pmr.CreatedAt,
-		UpdatedAt:       pmr.UpdatedAt,
+		ID:                    pmr.ID,
+		RunID:                 pmr.RunID,
+		TenagaKerjaID:         pmr.TenagaKerjaID,
+		JabatanID:             pmr.JabatanID,
+		TotalScore:            pmr.TotalScore,
+		CoreFactor:            pmr.CoreFactor,
+		SecondaryFactor:       pmr.SecondaryFactor,
+		CoreFactorPersen:      pmr.CoreFactorPersen,
+		SecondaryFactorPersen: pmr.SecondaryFactorPersen,
+		GapMode:               pmr.GapMode,
+		RoundingRule:          pmr.RoundingRule,
+		MissingPolicy:         pmr.MissingPolicy,
+		KriteriaDinilai:       pmr.KriteriaDinilai,
+		KriteriaTotal:         pmr.KriteriaTotal,
+		MissingKriteria:       pmr.MissingKriteria,
+		Incomplete:            pmr.Incomplete,
+		Method:                pmr.Method,
+		RankingStyle:          pmr.RankingStyle,
+		TieBreakers:           pmr.TieBreakers,
+		Status:                pmr.Status,
+		Rank:                  rank,
+		ScoreTotal:            pmr.TotalScore,
+		CreatedAt:             pmr.CreatedAt,
+		UpdatedAt:             pmr.UpdatedAt,
 	}
 
 	if pmr.TenagaKerja.ID != 0 {
@@ -303,6 +484,18 @@ func MapProfileMatchResultToDetailResponse(pmr *models.ProfileMatchResult, detai
 				aspekDetail.SF = sf
 			}
 
+			if kontribusi, ok := aspekData["kontribusi"].(float64); ok {
+				aspekDetail.Kontribusi = kontribusi
+			}
+
+			if corePersen, ok := aspekData["core_factor_persen"].(float64); ok {
+				aspekDetail.CoreFactorPersen = corePersen
+			}
+
+			if secondaryPersen, ok := aspekData["secondary_factor_persen"].(float64); ok {
+				aspekDetail.SecondaryFactorPersen = secondaryPersen
+			}
+
 			// Convert kriteria list
 			if kriteriaList, ok := aspekData["kriteria"].([]map[string]interface{}); ok {
 				kriteriaDetails := make([]KriteriaDetail, 0, len(kriteriaList))
@@ -323,12 +516,24 @@ func MapProfileMatchResultToDetailResponse(pmr *models.ProfileMatchResult, detai
 					if gap, ok := k["gap"].(float64); ok {
 						kriteriaDetail.Gap = gap
 					}
+					if effectiveGap, ok := k["effective_gap"].(float64); ok {
+						kriteriaDetail.EffectiveGap = effectiveGap
+					}
 					if bobotNilai, ok := k["bobot_nilai"].(float64); ok {
 						kriteriaDetail.BobotNilai = bobotNilai
 					}
 					if isCore, ok := k["is_core"].(bool); ok {
 						kriteriaDetail.IsCore = isCore
 					}
+					if bobot, ok := k["bobot"].(float64); ok {
+						kriteriaDetail.Bobot = bobot
+					}
+					if kontribusi, ok := k["kontribusi"].(float64); ok {
+						kriteriaDetail.Kontribusi = kontribusi
+					}
+					if imputed, ok := k["imputed"].(bool); ok {
+						kriteriaDetail.Imputed = imputed
+					}
 					kriteriaDetails = append(kriteriaDetails, kriteriaDetail)
 				}
 				aspekDetail.Kriteria = kriteriaDetails
@@ -338,8 +543,131 @@ func MapProfileMatchResultToDetailResponse(pmr *models.ProfileMatchResult, detai
 		}
 	}
 
+	if totalScore, ok := details["total_score"].(float64); ok {
+		detailPerhitungan.TotalScore = totalScore
+	}
+
+	if missing, ok := details["missing_kriteria"].([]string); ok {
+		detailPerhitungan.MissingKriteria = missing
+	}
+
 	response.Details = detailPerhitungan
 
 	return response
 }
 
+// MapSensitivityToResponse converts a sensitivity report to SensitivityResponse
+func MapSensitivityToResponse(jabatanID uint, report *profilematching.SensitivityReport, tenagaKerja map[uint]models.TenagaKerja) SensitivityResponse {
+	response := SensitivityResponse{
+		JabatanID:   jabatanID,
+		Scenarios:   report.Scenarios,
+		TopN:        report.TopN,
+		TenagaKerja: make([]SensitivityTenagaKerjaResponse, len(report.Candidates)),
+	}
+	for i, c := range report.Candidates {
+		item := SensitivityTenagaKerjaResponse{
+			TenagaKerjaID: c.ID,
+			BaseRank:      c.BaseRank,
+			MinRank:       c.MinRank,
+			MaxRank:       c.MaxRank,
+			TopNCount:     c.TopNCount,
+			TopNFrequency: c.TopNFrequency,
+		}
+		if tk, exists := tenagaKerja[c.ID]; exists {
+			tkResponse := MapTenagaKerjaToResponse(&tk)
+			item.TenagaKerja = &tkResponse
+		}
+		response.TenagaKerja[i] = item
+	}
+	return response
+}
+
+// MapCalculationJobToResponse converts CalculationJob model to CalculationJobResponse DTO
+func MapCalculationJobToResponse(job *models.CalculationJob) CalculationJobResponse {
+	response := CalculationJobResponse{
+		ID:              job.ID,
+		UserID:          job.UserID,
+		JabatanIDs:      job.Request.JabatanIDs,
+		Status:          job.Status,
+		CancelRequested: job.CancelRequested,
+		Processed:       job.Processed,
+		Total:           job.Total,
+		Errors:          job.Errors,
+		RunIDs:          job.RunIDs,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+		CreatedAt:       job.CreatedAt,
+		UpdatedAt:       job.UpdatedAt,
+	}
+	if response.Errors == nil {
+		response.Errors = []string{}
+	}
+	if response.RunIDs == nil {
+		response.RunIDs = []uint{}
+	}
+	return response
+}
+
+// MapResultStatusHistoryToResponse converts ResultStatusHistory model to ResultStatusHistoryResponse DTO
+func MapResultStatusHistoryToResponse(h *models.ResultStatusHistory) ResultStatusHistoryResponse {
+	response := ResultStatusHistoryResponse{
+		ID:                   h.ID,
+		JabatanID:            h.JabatanID,
+		TenagaKerjaID:        h.TenagaKerjaID,
+		ProfileMatchResultID: h.ProfileMatchResultID,
+		RunID:                h.RunID,
+		Status:               h.Status,
+		Auto:                 h.Auto,
+		UserID:               h.UserID,
+		Note:                 h.Note,
+		CreatedAt:            h.CreatedAt,
+	}
+
+	if h.User != nil && h.User.ID != 0 {
+		user := MapUserToResponse(h.User)
+		response.User = &user
+	}
+
+	return response
+}
+
+// MapResultStatusHistoriesToResponse converts ResultStatusHistory slice to ResultStatusHistoryResponse slice
+func MapResultStatusHistoriesToResponse(history []models.ResultStatusHistory) []ResultStatusHistoryResponse {
+	result := make([]ResultStatusHistoryResponse, len(history))
+	for i, h := range history {
+		result[i] = MapResultStatusHistoryToResponse(&h)
+	}
+	return result
+}
+
+// MapAuditLogToResponse converts AuditLog model to AuditLogResponse DTO
+func MapAuditLogToResponse(entry *models.AuditLog) AuditLogResponse {
+	response := AuditLogResponse{
+		ID:        entry.ID,
+		UserID:    entry.UserID,
+		Entity:    entry.Entity,
+		EntityID:  entry.EntityID,
+		Action:    entry.Action,
+		Changes:   make(map[string]AuditChangeResponse, len(entry.Changes)),
+		CreatedAt: entry.CreatedAt,
+	}
+	for field, change := range entry.Changes {
+		response.Changes[field] = AuditChangeResponse{Old: change.Old, New: change.New}
+	}
+
+	if entry.User != nil && entry.User.ID != 0 {
+		user := MapUserToResponse(entry.User)
+		response.User = &user
+	}
+
+	return response
+}
+
+// MapAuditLogsToResponse converts AuditLog slice to AuditLogResponse slice
+func MapAuditLogsToResponse(entries []models.AuditLog) []AuditLogResponse {
+	result := make([]AuditLogResponse, len(entries))
+	for i, entry := range entries {
+		result[i] = MapAuditLogToResponse(&entry)
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package dto
+
+import "time"
+
+// ModelConfigVersion is the version of the ModelConfig document format
+const ModelConfigVersion = 1
+
+// ModelConfig is the competency model configuration as one portable
+// document. Records refer to each other by natural key rather than ID:
+// gap weight tables, aspek and jabatan by nama, kriteria by kode.
+type ModelConfig struct {
+	Version         int                    `json:"version"`
+	ExportedAt      time.Time              `json:"exported_at"`
+	GapWeightTables []GapWeightTableConfig `json:"gap_weight_tables"`
+	Aspek           []AspekConfig          `json:"aspek"`
+	Kriteria        []KriteriaConfig       `json:"kriteria"`
+	Jabatan         []JabatanConfig        `json:"jabatan"`
+}
+
+type GapWeightTableConfig struct {
+	Nama       string                 `json:"nama"`
+	Deskripsi  string                 `json:"deskripsi"`
+	BelowRange string                 `json:"below_range"`
+	AboveRange string                 `json:"above_range"`
+	Entries    []GapWeightEntryConfig `json:"entries"`
+}
+
+type GapWeightEntryConfig struct {
+	Gap   float64 `json:"gap"`
+	Bobot float64 `json:"bobot"`
+}
+
+type AspekConfig struct {
+	Nama                  string   `json:"nama"`
+	Deskripsi             string   `json:"deskripsi"`
+	Persentase            float64  `json:"persentase"`
+	CoreFactorPersen      *float64 `json:"core_factor_persen"`
+	SecondaryFactorPersen *float64 `json:"secondary_factor_persen"`
+}
+
+type KriteriaConfig struct {
+	Kode   string  `json:"kode"`
+	Nama   string  `json:"nama"`
+	Aspek  string  `json:"aspek"`
+	IsCore bool    `json:"is_core"`
+	Bobot  float64 `json:"bobot"`
+}
+
+type JabatanConfig struct {
+	Nama      string `json:"nama"`
+	Deskripsi string `json:"deskripsi"`
+	// GapWeightTable is empty for the default GAP weight table
+	GapWeightTable        string   `json:"gap_weight_table,omitempty"`
+	CoreFactorPersen      *float64 `json:"core_factor_persen"`
+	SecondaryFactorPersen *float64 `json:"secondary_factor_persen"`
+	JumlahLowongan        int      `json:"jumlah_lowongan"`
+	SkorMinimum           *float64 `json:"skor_minimum"`
+	// TargetProfiles are keyed by kriteria kode
+	TargetProfiles map[string]TargetProfileConfig `json:"target_profiles"`
+}
+
+type TargetProfileConfig struct {
+	TargetNilai  float64  `json:"target_nilai"`
+	NilaiMinimum *float64 `json:"nilai_minimum"`
+}
+
+// ConfigImportCounts counts the records of one kind a configuration import
+// created, updated and deleted
+type ConfigImportCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+}
+
+// ConfigImportSummary counts the changes of a configuration import per kind
+// of record
+type ConfigImportSummary struct {
+	GapWeightTables ConfigImportCounts `json:"gap_weight_tables"`
+	Aspek           ConfigImportCounts `json:"aspek"`
+	Kriteria        ConfigImportCounts `json:"kriteria"`
+	Jabatan         ConfigImportCounts `json:"jabatan"`
+	TargetProfiles  ConfigImportCounts `json:"target_profiles"`
+}
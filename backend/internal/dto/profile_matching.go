@@ -6,75 +6,212 @@ import "time"
 type CalculationRequest struct {
 	JabatanID      uint   `json:"jabatan_id" binding:"required"`
 	TenagaKerjaIDs []uint `json:"tenaga_kerja_ids,omitempty"` // Optional: if empty, calculate for all
+	// GapMode selects how fractional gaps are weighted: exact (default), interpolate or round
+	GapMode string `json:"gap_mode,omitempty" binding:"omitempty,oneof=exact interpolate round"`
+	// RoundingRule applies to the round mode: nearest (default), half_up, half_down, floor or ceil
+	RoundingRule string `json:"rounding_rule,omitempty" binding:"omitempty,oneof=nearest half_up half_down floor ceil"`
+	// MissingPolicy handles kriteria without nilai: skip (default), minimum, exclude or incomplete
+	MissingPolicy string `json:"missing_policy,omitempty" binding:"omitempty,oneof=skip minimum exclude incomplete"`
+	// MinimumNilai is the nilai assumed for missing kriteria under the minimum policy (default 1)
+	MinimumNilai *float64 `json:"minimum_nilai,omitempty"`
+	// Method ranks with profile_matching (default), saw, wp or topsis on the same data
+	Method string `json:"method,omitempty" binding:"omitempty,oneof=profile_matching saw wp topsis"`
+	// RankingStyle numbers equal scores: ordinal (default, 1 2 3), competition (1 1 3) or dense (1 1 2)
+	RankingStyle string `json:"ranking_style,omitempty" binding:"omitempty,oneof=ordinal competition dense"`
+	// TieBreakers order equal scores in turn: core_factor, secondary_factor, nik or kriteria:<kode>
+	TieBreakers []string `json:"tie_breakers,omitempty"`
+	// DryRun returns the ranking with full breakdown without storing it; the overrides require it
+	DryRun          bool             `json:"dry_run,omitempty"`
+	NilaiOverrides  []NilaiOverride  `json:"nilai_overrides,omitempty" binding:"dive"`
+	TargetOverrides []TargetOverride `json:"target_overrides,omitempty" binding:"dive"`
+	CoreOverrides   []CoreOverride   `json:"core_overrides,omitempty" binding:"dive"`
+}
+
+// NilaiOverride replaces a tenaga kerja's nilai for a kriteria in a dry run
+type NilaiOverride struct {
+	TenagaKerjaID uint    `json:"tenaga_kerja_id" binding:"required"`
+	KriteriaID    uint    `json:"kriteria_id" binding:"required"`
+	Nilai         float64 `json:"nilai"`
+}
+
+// TargetOverride replaces the jabatan's target nilai for a kriteria in a dry run
+type TargetOverride struct {
+	KriteriaID  uint    `json:"kriteria_id" binding:"required"`
+	TargetNilai float64 `json:"target_nilai"`
+}
+
+// CoreOverride replaces the core flag of a kriteria in a dry run
+type CoreOverride struct {
+	KriteriaID uint `json:"kriteria_id" binding:"required"`
+	IsCore     bool `json:"is_core"`
 }
 
 // ProfileMatchResultResponse represents profile matching result in API response
 type ProfileMatchResultResponse struct {
-	ID              uint                  `json:"id"`
-	TenagaKerjaID   uint                  `json:"tenaga_kerja_id"`
-	JabatanID       uint                  `json:"jabatan_id"`
-	TotalScore      float64               `json:"total_score"`
-	CoreFactor      float64               `json:"core_factor"`
-	SecondaryFactor float64               `json:"secondary_factor"`
-	TenagaKerja     *TenagaKerjaResponse  `json:"tenaga_kerja,omitempty"`
-	Jabatan         *JabatanResponse      `json:"jabatan,omitempty"`
-	Rank            int                   `json:"rank,omitempty"`
-	ScoreTotal      float64               `json:"score_total,omitempty"` // Alias for TotalScore for frontend compatibility
-	CreatedAt       time.Time             `json:"created_at"`
-	UpdatedAt       time.Time             `json:"updated_at"`
+	ID                    uint                 `json:"id"`
+	RunID                 *uint                `json:"run_id,omitempty"`
+	TenagaKerjaID         uint                 `json:"tenaga_kerja_id"`
+	JabatanID             uint                 `json:"jabatan_id"`
+	TotalScore            float64              `json:"total_score"`
+	CoreFactor            float64              `json:"core_factor"`
+	SecondaryFactor       float64              `json:"secondary_factor"`
+	CoreFactorPersen      float64              `json:"core_factor_persen"`
+	SecondaryFactorPersen float64              `json:"secondary_factor_persen"`
+	GapMode               string               `json:"gap_mode"`
+	RoundingRule          string               `json:"rounding_rule,omitempty"`
+	MissingPolicy         string               `json:"missing_policy"`
+	KriteriaDinilai       int                  `json:"kriteria_dinilai"`
+	KriteriaTotal         int                  `json:"kriteria_total"`
+	MissingKriteria       []string             `json:"missing_kriteria"`
+	Incomplete            bool                 `json:"incomplete"`
+	Method                string               `json:"method"`
+	RankingStyle          string               `json:"ranking_style"`
+	TieBreakers           []string             `json:"tie_breakers"`
+	Status                string               `json:"status"`
+	TenagaKerja           *TenagaKerjaResponse `json:"tenaga_kerja,omitempty"`
+	Jabatan               *JabatanResponse     `json:"jabatan,omitempty"`
+	Rank                  int                  `json:"rank,omitempty"`
+	ScoreTotal            float64              `json:"score_total,omitempty"` // Alias for TotalScore for frontend compatibility
+	CreatedAt             time.Time            `json:"created_at"`
+	UpdatedAt             time.Time            `json:"updated_at"`
 }
 
 // RankingResponse represents ranking response with additional fields for frontend
 type RankingResponse struct {
-	ID              uint                  `json:"id"`
-	Rank            int                   `json:"rank"`
-	ScoreTotal      float64               `json:"score_total"`
-	CoreFactor      float64               `json:"core_factor"`
-	SecondaryFactor float64               `json:"secondary_factor"`
-	TenagaKerja     *TenagaKerjaResponse  `json:"tenaga_kerja,omitempty"`
-	Jabatan         *JabatanResponse      `json:"jabatan,omitempty"`
-	CreatedAt       time.Time             `json:"created_at"`
+	ID              uint                 `json:"id"`
+	Rank            int                  `json:"rank"`
+	ScoreTotal      float64              `json:"score_total"`
+	CoreFactor      float64              `json:"core_factor"`
+	SecondaryFactor float64              `json:"secondary_factor"`
+	KriteriaDinilai int                  `json:"kriteria_dinilai"`
+	KriteriaTotal   int                  `json:"kriteria_total"`
+	Status          string               `json:"status"`
+	Incomplete      bool                 `json:"incomplete"`
+	TenagaKerja     *TenagaKerjaResponse `json:"tenaga_kerja,omitempty"`
+	Jabatan         *JabatanResponse     `json:"jabatan,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
 }
 
 // KriteriaDetail represents detail kriteria in calculation
 type KriteriaDetail struct {
-	Kode       string  `json:"kode"`
-	Nama       string  `json:"nama"`
-	Target     float64 `json:"target"`
-	Actual     float64 `json:"actual"`
-	Gap        float64 `json:"gap"`
-	BobotNilai float64 `json:"bobot_nilai"`
-	IsCore     bool    `json:"is_core"`
+	Kode         string  `json:"kode"`
+	Nama         string  `json:"nama"`
+	Target       float64 `json:"target"`
+	Actual       float64 `json:"actual"`
+	Gap          float64 `json:"gap"`
+	EffectiveGap float64 `json:"effective_gap"` // Gap used for the weight lookup after rounding
+	BobotNilai   float64 `json:"bobot_nilai"`
+	IsCore       bool    `json:"is_core"`
+	Bobot        float64 `json:"bobot"`
+	Kontribusi   float64 `json:"kontribusi"` // Bobot-weighted share in the aspek CF or SF
+	Imputed      bool    `json:"imputed"`    // Actual is the minimum nilai assumed for a missing kriteria
 }
 
 // AspekDetail represents detail perhitungan per aspek
 type AspekDetail struct {
-	Persentase float64         `json:"persentase"`
-	Score      float64         `json:"score"`
-	CF         float64         `json:"cf"`
-	SF         float64         `json:"sf"`
-	Kriteria   []KriteriaDetail `json:"kriteria"`
+	Persentase            float64          `json:"persentase"`
+	Score                 float64          `json:"score"`
+	CF                    float64          `json:"cf"`
+	SF                    float64          `json:"sf"`
+	Kontribusi            float64          `json:"kontribusi"` // Persentase-weighted share of the total score
+	CoreFactorPersen      float64          `json:"core_factor_persen"`
+	SecondaryFactorPersen float64          `json:"secondary_factor_persen"`
+	Kriteria              []KriteriaDetail `json:"kriteria"`
 }
 
 // DetailPerhitungan represents detail perhitungan structure
 type DetailPerhitungan struct {
-	Aspek map[string]AspekDetail `json:"aspek"`
+	Aspek           map[string]AspekDetail `json:"aspek"`
+	TotalScore      float64                `json:"total_score"` // Profile matching total: sum of aspek kontribusi
+	MissingKriteria []string               `json:"missing_kriteria"`
 }
 
 // ProfileMatchResultDetailResponse represents detailed profile matching result with calculation details
 type ProfileMatchResultDetailResponse struct {
-	ID              uint                  `json:"id"`
-	TenagaKerjaID   uint                  `json:"tenaga_kerja_id"`
-	JabatanID       uint                  `json:"jabatan_id"`
-	TotalScore      float64               `json:"total_score"`
-	CoreFactor      float64               `json:"core_factor"`
-	SecondaryFactor float64               `json:"secondary_factor"`
-	TenagaKerja     *TenagaKerjaResponse  `json:"tenaga_kerja,omitempty"`
-	Jabatan         *JabatanResponse      `json:"jabatan,omitempty"`
-	Rank            int                   `json:"rank,omitempty"`
-	ScoreTotal      float64               `json:"score_total,omitempty"`
-	Details         DetailPerhitungan     `json:"details"`
-	CreatedAt       time.Time             `json:"created_at"`
-	UpdatedAt       time.Time             `json:"updated_at"`
+	ID                    uint                 `json:"id"`
+	RunID                 *uint                `json:"run_id,omitempty"`
+	TenagaKerjaID         uint                 `json:"tenaga_kerja_id"`
+	JabatanID             uint                 `json:"jabatan_id"`
+	TotalScore            float64              `json:"total_score"`
+	CoreFactor            float64              `json:"core_factor"`
+	SecondaryFactor       float64              `json:"secondary_factor"`
+	CoreFactorPersen      float64              `json:"core_factor_persen"`
+	SecondaryFactorPersen float64              `json:"secondary_factor_persen"`
+	GapMode               string               `json:"gap_mode"`
+	RoundingRule          string               `json:"rounding_rule,omitempty"`
+	MissingPolicy         string               `json:"missing_policy"`
+	KriteriaDinilai       int                  `json:"kriteria_dinilai"`
+	KriteriaTotal         int                  `json:"kriteria_total"`
+	MissingKriteria       []string             `json:"missing_kriteria"`
+	Incomplete            bool                 `json:"incomplete"`
+	Method                string               `json:"method"`
+	RankingStyle          string               `json:"ranking_style"`
+	TieBreakers           []string             `json:"tie_breakers"`
+	Status                string               `json:"status"`
+	TenagaKerja           *TenagaKerjaResponse `json:"tenaga_kerja,omitempty"`
+	Jabatan               *JabatanResponse     `json:"jabatan,omitempty"`
+	Rank                  int                  `json:"rank,omitempty"`
+	ScoreTotal            float64              `json:"score_total,omitempty"`
+	Details               DetailPerhitungan    `json:"details"`
+	CreatedAt             time.Time            `json:"created_at"`
+	UpdatedAt             time.Time            `json:"updated_at"`
+}
+
+// PerturbationRange is an inclusive range stepped from Min to Max; a zero Step requires Min == Max
+type PerturbationRange struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Step float64 `json:"step" binding:"gte=0"`
 }
 
+// AspekPersentaseRange perturbs the persentase of an aspek
+type AspekPersentaseRange struct {
+	AspekID uint    `json:"aspek_id" binding:"required"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Step    float64 `json:"step" binding:"gte=0"`
+}
+
+// TargetNilaiRange perturbs the target nilai of a kriteria
+type TargetNilaiRange struct {
+	KriteriaID uint    `json:"kriteria_id" binding:"required"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Step       float64 `json:"step" binding:"gte=0"`
+}
+
+// SensitivityRequest represents a sensitivity analysis request; the calculation settings match CalculationRequest
+type SensitivityRequest struct {
+	JabatanID        uint                   `json:"jabatan_id" binding:"required"`
+	TenagaKerjaIDs   []uint                 `json:"tenaga_kerja_ids,omitempty"`
+	GapMode          string                 `json:"gap_mode,omitempty" binding:"omitempty,oneof=exact interpolate round"`
+	RoundingRule     string                 `json:"rounding_rule,omitempty" binding:"omitempty,oneof=nearest half_up half_down floor ceil"`
+	MissingPolicy    string                 `json:"missing_policy,omitempty" binding:"omitempty,oneof=skip minimum exclude incomplete"`
+	MinimumNilai     *float64               `json:"minimum_nilai,omitempty"`
+	Method           string                 `json:"method,omitempty" binding:"omitempty,oneof=profile_matching saw wp topsis"`
+	RankingStyle     string                 `json:"ranking_style,omitempty" binding:"omitempty,oneof=ordinal competition dense"`
+	TieBreakers      []string               `json:"tie_breakers,omitempty"`
+	TopN             int                    `json:"top_n" binding:"required,gt=0"`
+	AspekPersentase  []AspekPersentaseRange `json:"aspek_persentase,omitempty" binding:"dive"`
+	CoreFactorPersen *PerturbationRange     `json:"core_factor_persen,omitempty"` // Secondary factor takes the remainder
+	TargetNilai      []TargetNilaiRange     `json:"target_nilai,omitempty" binding:"dive"`
+}
+
+// SensitivityTenagaKerjaResponse represents the rank stability of a tenaga kerja
+type SensitivityTenagaKerjaResponse struct {
+	TenagaKerjaID uint                 `json:"tenaga_kerja_id"`
+	TenagaKerja   *TenagaKerjaResponse `json:"tenaga_kerja,omitempty"`
+	BaseRank      int                  `json:"base_rank"` // Rank without perturbation
+	MinRank       int                  `json:"min_rank"`
+	MaxRank       int                  `json:"max_rank"`
+	TopNCount     int                  `json:"top_n_count"`
+	TopNFrequency float64              `json:"top_n_frequency"` // Share of scenarios ranked in the top N
+}
+
+// SensitivityResponse represents sensitivity analysis result
+type SensitivityResponse struct {
+	JabatanID   uint                             `json:"jabatan_id"`
+	Scenarios   int                              `json:"scenarios"`
+	TopN        int                              `json:"top_n"`
+	TenagaKerja []SensitivityTenagaKerjaResponse `json:"tenaga_kerja"`
+}
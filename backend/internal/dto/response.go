@@ -62,4 +62,3 @@ func NewPaginatedResponse(data interface{}, page, perPage int, total int64) Pagi
 		},
 	}
 }
-
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// ResultStatusUpdateRequest represents a selection status given to a result
+type ResultStatusUpdateRequest struct {
+	Status string `json:"status" binding:"required,oneof=candidate shortlisted selected rejected reserve"`
+	Note   string `json:"note,omitempty"`
+}
+
+// ResultStatusHistoryResponse represents a status change in API response
+type ResultStatusHistoryResponse struct {
+	ID                   uint          `json:"id"`
+	JabatanID            uint          `json:"jabatan_id"`
+	TenagaKerjaID        uint          `json:"tenaga_kerja_id"`
+	ProfileMatchResultID uint          `json:"profile_match_result_id"`
+	RunID                *uint         `json:"run_id"`
+	Status               string        `json:"status"`
+	Auto                 bool          `json:"auto"`
+	UserID               *uint         `json:"user_id"`
+	User                 *UserResponse `json:"user,omitempty"`
+	Note                 string        `json:"note"`
+	CreatedAt            time.Time     `json:"created_at"`
+}
@@ -31,3 +31,23 @@ type TenagaKerjaUpdateRequest struct {
 	Alamat   string   `json:"alamat,omitempty"`
 	Telepon  string   `json:"telepon,omitempty"`
 }
+
+// TenagaKerjaImportRowResponse reports one row of a CSV import
+type TenagaKerjaImportRowResponse struct {
+	Line   int      `json:"line"` // Line number in the CSV file, the header being line 1
+	NIK    string   `json:"nik"`
+	Nama   string   `json:"nama"`
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors"`
+	ID     uint     `json:"id,omitempty"` // Set when the row was imported
+}
+
+// TenagaKerjaImportResponse is the per-row report of a CSV import
+type TenagaKerjaImportResponse struct {
+	DryRun   bool                           `json:"dry_run"`
+	Total    int                            `json:"total"`
+	Valid    int                            `json:"valid"`
+	Invalid  int                            `json:"invalid"`
+	Imported int                            `json:"imported"`
+	Rows     []TenagaKerjaImportRowResponse `json:"rows"`
+}
@@ -4,11 +4,11 @@ import "time"
 
 // UserResponse represents user data in API response
 type UserResponse struct {
-	ID       uint      `json:"id"`
-	Email    string    `json:"email"`
-	Nama     string    `json:"nama"`
-	Role     string    `json:"role"`
-	IsActive bool      `json:"is_active"`
+	ID        uint      `json:"id"`
+	Email     string    `json:"email"`
+	Nama      string    `json:"nama"`
+	Role      string    `json:"role"`
+	IsActive  bool      `json:"is_active"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -50,4 +50,3 @@ type UserUpdateRequest struct {
 	Role     string `json:"role,omitempty"`
 	IsActive *bool  `json:"is_active,omitempty"`
 }
-
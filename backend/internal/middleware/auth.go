@@ -47,6 +47,20 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// UserID returns the ID of the authenticated user set by AuthMiddleware
+func UserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("userID")
+	if !exists {
+		return 0, false
+	}
+	// JWT numeric claims are decoded as float64
+	id, ok := value.(float64)
+	if !ok || id <= 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
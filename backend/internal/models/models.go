@@ -17,8 +17,38 @@ type User struct {
 
 type Jabatan struct {
 	gorm.Model
-	Nama      string `gorm:"type:varchar(100);not null" json:"nama"`
-	Deskripsi string `gorm:"type:text" json:"deskripsi"`
+	Nama             string          `gorm:"type:varchar(100);not null" json:"nama"`
+	Deskripsi        string          `gorm:"type:text" json:"deskripsi"`
+	GapWeightTableID *uint           `json:"gap_weight_table_id"`
+	GapWeightTable   *GapWeightTable `gorm:"foreignKey:GapWeightTableID" json:"gap_weight_table,omitempty"`
+	// Core/secondary factor ratio in percent; nil uses the system default
+	CoreFactorPersen      *float64 `gorm:"type:decimal(5,2)" json:"core_factor_persen"`
+	SecondaryFactorPersen *float64 `gorm:"type:decimal(5,2)" json:"secondary_factor_persen"`
+	// JumlahLowongan is the number of vacancies; results ranked within it
+	// are shortlisted after each calculation
+	JumlahLowongan int `gorm:"not null;default:0" json:"jumlah_lowongan"`
+	// SkorMinimum knocks tenaga kerja with a lower total score, on the
+	// ranking method's scale, out of the ranking; nil means no minimum
+	SkorMinimum *float64 `gorm:"type:decimal(5,2)" json:"skor_minimum"`
+}
+
+// GapWeightTable maps a GAP (nilai - target) to a bobot nilai. Gaps outside
+// the range covered by the entries are handled per side by BelowRange and
+// AboveRange: "zero" gives a weight of 0, "clamp" reuses the nearest entry.
+type GapWeightTable struct {
+	gorm.Model
+	Nama       string           `gorm:"type:varchar(100);not null" json:"nama"`
+	Deskripsi  string           `gorm:"type:text" json:"deskripsi"`
+	BelowRange string           `gorm:"type:enum('zero','clamp');default:'zero'" json:"below_range"`
+	AboveRange string           `gorm:"type:enum('zero','clamp');default:'zero'" json:"above_range"`
+	Entries    []GapWeightEntry `gorm:"foreignKey:GapWeightTableID" json:"entries,omitempty"`
+}
+
+type GapWeightEntry struct {
+	gorm.Model
+	GapWeightTableID uint    `gorm:"not null" json:"gap_weight_table_id"`
+	Gap              float64 `gorm:"type:decimal(5,2);not null" json:"gap"`
+	Bobot            float64 `gorm:"type:decimal(5,2);not null" json:"bobot"`
 }
 
 type Aspek struct {
@@ -26,6 +56,9 @@ type Aspek struct {
 	Nama       string  `gorm:"type:varchar(100);not null" json:"nama"`
 	Deskripsi  string  `gorm:"type:text" json:"deskripsi"`
 	Persentase float64 `gorm:"type:decimal(5,2);not null" json:"persentase"`
+	// Optional core/secondary factor ratio overriding the jabatan's ratio
+	CoreFactorPersen      *float64 `gorm:"type:decimal(5,2)" json:"core_factor_persen"`
+	SecondaryFactorPersen *float64 `gorm:"type:decimal(5,2)" json:"secondary_factor_persen"`
 }
 
 type Kriteria struct {
@@ -40,11 +73,14 @@ type Kriteria struct {
 
 type TargetProfile struct {
 	gorm.Model
-	JabatanID   uint     `gorm:"not null" json:"jabatan_id"`
-	KriteriaID  uint     `gorm:"not null" json:"kriteria_id"`
-	TargetNilai float64  `gorm:"type:decimal(5,2);not null" json:"target_nilai"`
-	Jabatan     Jabatan  `gorm:"foreignKey:JabatanID" json:"jabatan,omitempty"`
-	Kriteria    Kriteria `gorm:"foreignKey:KriteriaID" json:"kriteria,omitempty"`
+	JabatanID   uint    `gorm:"not null" json:"jabatan_id"`
+	KriteriaID  uint    `gorm:"not null" json:"kriteria_id"`
+	TargetNilai float64 `gorm:"type:decimal(5,2);not null" json:"target_nilai"`
+	// NilaiMinimum knocks tenaga kerja with a lower or missing nilai out of
+	// the ranking; nil means no minimum
+	NilaiMinimum *float64 `gorm:"type:decimal(5,2)" json:"nilai_minimum"`
+	Jabatan      Jabatan  `gorm:"foreignKey:JabatanID" json:"jabatan,omitempty"`
+	Kriteria     Kriteria `gorm:"foreignKey:KriteriaID" json:"kriteria,omitempty"`
 }
 
 type TenagaKerja struct {
@@ -65,13 +101,207 @@ type NilaiTenagaKerja struct {
 	Kriteria      Kriteria    `gorm:"foreignKey:KriteriaID" json:"kriteria,omitempty"`
 }
 
+// CalculationParameters are the settings a calculation run used
+type CalculationParameters struct {
+	TenagaKerjaIDs        []uint   `json:"tenaga_kerja_ids,omitempty"`
+	GapWeightTableID      *uint    `json:"gap_weight_table_id,omitempty"`
+	GapMode               string   `json:"gap_mode"`
+	RoundingRule          string   `json:"rounding_rule,omitempty"`
+	MissingPolicy         string   `json:"missing_policy"`
+	MinimumNilai          float64  `json:"minimum_nilai,omitempty"`
+	Method                string   `json:"method"`
+	RankingStyle          string   `json:"ranking_style,omitempty"`
+	TieBreakers           []string `json:"tie_breakers,omitempty"`
+	CoreFactorPersen      float64  `json:"core_factor_persen"`
+	SecondaryFactorPersen float64  `json:"secondary_factor_persen"`
+}
+
+// CalculationRun records one calculation of a jabatan's ranking. Its results
+// are kept when the jabatan is recalculated; at most one run per jabatan is
+// the official one.
+type CalculationRun struct {
+	gorm.Model
+	JabatanID  uint                  `gorm:"not null;index" json:"jabatan_id"`
+	UserID     *uint                 `json:"user_id"`
+	Parameters CalculationParameters `gorm:"type:text;serializer:json" json:"parameters"`
+	Status     string                `gorm:"type:enum('running','completed','failed');default:'running'" json:"status"`
+	Error      string                `gorm:"type:text" json:"error,omitempty"`
+	IsOfficial bool                  `gorm:"default:false" json:"is_official"`
+	Jabatan    Jabatan               `gorm:"foreignKey:JabatanID" json:"jabatan,omitempty"`
+	User       *User                 `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Results    []ProfileMatchResult  `gorm:"foreignKey:RunID" json:"results,omitempty"`
+}
+
+// CalculationJobRequest is what a calculation job calculates: the settings
+// applied to each jabatan
+type CalculationJobRequest struct {
+	JabatanIDs    []uint   `json:"jabatan_ids"`
+	GapMode       string   `json:"gap_mode,omitempty"`
+	RoundingRule  string   `json:"rounding_rule,omitempty"`
+	MissingPolicy string   `json:"missing_policy,omitempty"`
+	MinimumNilai  *float64 `json:"minimum_nilai,omitempty"`
+	Method        string   `json:"method,omitempty"`
+	RankingStyle  string   `json:"ranking_style,omitempty"`
+	TieBreakers   []string `json:"tie_breakers,omitempty"`
+}
+
+// CalculationJob is a background calculation of one or more jabatan. Each
+// jabatan calculated records its own CalculationRun.
+type CalculationJob struct {
+	gorm.Model
+	UserID  *uint                 `json:"user_id"`
+	Request CalculationJobRequest `gorm:"type:text;serializer:json" json:"request"`
+	Status  string                `gorm:"type:enum('queued','running','completed','failed','cancelled');default:'queued'" json:"status"`
+	// CancelRequested stops a running job before its next jabatan
+	CancelRequested bool `gorm:"default:false" json:"cancel_requested"`
+	// Processed of Total jabatan are done; the errors of those that failed
+	// are listed per jabatan
+	Processed  int        `gorm:"not null;default:0" json:"processed"`
+	Total      int        `gorm:"not null;default:0" json:"total"`
+	Errors     []string   `gorm:"type:text;serializer:json" json:"errors"`
+	RunIDs     []uint     `gorm:"type:text;serializer:json" json:"run_ids"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	// Owner is the API instance that queued the job and runs it; it keeps
+	// HeartbeatAt recent while the job is unfinished
+	Owner       string     `gorm:"type:varchar(100);index" json:"owner"`
+	HeartbeatAt *time.Time `json:"heartbeat_at"`
+}
+
 type ProfileMatchResult struct {
 	gorm.Model
-	TenagaKerjaID   uint        `gorm:"not null" json:"tenaga_kerja_id"`
-	JabatanID       uint        `gorm:"not null" json:"jabatan_id"`
-	TotalScore      float64     `gorm:"type:decimal(5,2);not null" json:"total_score"`
-	CoreFactor      float64     `gorm:"type:decimal(5,2);not null" json:"core_factor"`
-	SecondaryFactor float64     `gorm:"type:decimal(5,2);not null" json:"secondary_factor"`
-	TenagaKerja     TenagaKerja `gorm:"foreignKey:TenagaKerjaID" json:"tenaga_kerja,omitempty"`
-	Jabatan         Jabatan     `gorm:"foreignKey:JabatanID" json:"jabatan,omitempty"`
+	// RunID is nil for results stored before calculation runs were recorded
+	RunID           *uint   `gorm:"index" json:"run_id"`
+	TenagaKerjaID   uint    `gorm:"not null" json:"tenaga_kerja_id"`
+	JabatanID       uint    `gorm:"not null" json:"jabatan_id"`
+	TotalScore      float64 `gorm:"type:decimal(5,2);not null" json:"total_score"`
+	CoreFactor      float64 `gorm:"type:decimal(5,2);not null" json:"core_factor"`
+	SecondaryFactor float64 `gorm:"type:decimal(5,2);not null" json:"secondary_factor"`
+	// Core/secondary factor ratio in effect for the jabatan at calculation time
+	CoreFactorPersen      float64 `gorm:"type:decimal(5,2);not null;default:60" json:"core_factor_persen"`
+	SecondaryFactorPersen float64 `gorm:"type:decimal(5,2);not null;default:40" json:"secondary_factor_persen"`
+	// GAP to weight conversion mode used at calculation time
+	GapMode      string `gorm:"type:enum('exact','interpolate','round');default:'exact'" json:"gap_mode"`
+	RoundingRule string `gorm:"type:varchar(20)" json:"rounding_rule"`
+	// Handling of target kriteria without nilai and the resulting coverage
+	MissingPolicy   string   `gorm:"type:enum('skip','minimum','exclude','incomplete');default:'skip'" json:"missing_policy"`
+	MinimumNilai    float64  `gorm:"type:decimal(5,2);default:0" json:"minimum_nilai"`
+	KriteriaDinilai int      `gorm:"not null;default:0" json:"kriteria_dinilai"`
+	KriteriaTotal   int      `gorm:"not null;default:0" json:"kriteria_total"`
+	MissingKriteria []string `gorm:"type:text;serializer:json" json:"missing_kriteria"`
+	Incomplete      bool     `gorm:"default:false" json:"incomplete"`
+	// Ranking method; TotalScore is on a 0-100 scale for saw, wp and topsis
+	Method string `gorm:"type:enum('profile_matching','saw','wp','topsis');default:'profile_matching'" json:"method"`
+	// Rank within the run under the ranking style and tie-breakers in effect;
+	// 0 for results stored before ranks were recorded
+	Rank         int      `gorm:"column:ranking;not null;default:0" json:"rank"`
+	RankingStyle string   `gorm:"type:enum('ordinal','competition','dense');default:'ordinal'" json:"ranking_style"`
+	TieBreakers  []string `gorm:"type:text;serializer:json" json:"tie_breakers"`
+	// Status is the selection status of the tenaga kerja for the jabatan
+	Status      string      `gorm:"type:enum('candidate','shortlisted','selected','rejected','reserve');default:'candidate'" json:"status"`
+	TenagaKerja TenagaKerja `gorm:"foreignKey:TenagaKerjaID" json:"tenaga_kerja,omitempty"`
+	Jabatan     Jabatan     `gorm:"foreignKey:JabatanID" json:"jabatan,omitempty"`
+	// Aspek is the breakdown snapshotted at calculation time; empty for
+	// results stored before snapshots were recorded
+	Aspek []ProfileMatchResultAspek `gorm:"foreignKey:ProfileMatchResultID" json:"aspek,omitempty"`
+}
+
+// ProfileMatchResultAspek is an aspek's share of a result as calculated,
+// kept so later edits to aspek, kriteria or nilai do not change it
+type ProfileMatchResultAspek struct {
+	gorm.Model
+	ProfileMatchResultID  uint                         `gorm:"not null;index" json:"profile_match_result_id"`
+	AspekID               uint                         `gorm:"not null" json:"aspek_id"`
+	Nama                  string                       `gorm:"type:varchar(100);not null" json:"nama"`
+	Persentase            float64                      `gorm:"type:decimal(5,2);not null" json:"persentase"`
+	CoreFactorPersen      float64                      `gorm:"type:decimal(5,2);not null" json:"core_factor_persen"`
+	SecondaryFactorPersen float64                      `gorm:"type:decimal(5,2);not null" json:"secondary_factor_persen"`
+	CF                    float64                      `gorm:"column:cf;type:decimal(7,4);not null" json:"cf"`
+	SF                    float64                      `gorm:"column:sf;type:decimal(7,4);not null" json:"sf"`
+	Score                 float64                      `gorm:"type:decimal(7,4);not null" json:"score"`
+	Kontribusi            float64                      `gorm:"type:decimal(7,4);not null" json:"kontribusi"`
+	Kriteria              []ProfileMatchResultKriteria `gorm:"foreignKey:ProfileMatchResultAspekID" json:"kriteria,omitempty"`
+}
+
+// ProfileMatchResultKriteria is a kriteria's GAP and weight as calculated
+type ProfileMatchResultKriteria struct {
+	gorm.Model
+	ProfileMatchResultAspekID uint    `gorm:"not null;index" json:"profile_match_result_aspek_id"`
+	KriteriaID                uint    `gorm:"not null" json:"kriteria_id"`
+	Kode                      string  `gorm:"type:varchar(20);not null" json:"kode"`
+	Nama                      string  `gorm:"type:varchar(100);not null" json:"nama"`
+	IsCore                    bool    `json:"is_core"`
+	Bobot                     float64 `gorm:"type:decimal(5,2);not null" json:"bobot"`
+	Target                    float64 `gorm:"type:decimal(5,2);not null" json:"target"`
+	Actual                    float64 `gorm:"type:decimal(5,2);not null" json:"actual"`
+	Gap                       float64 `gorm:"type:decimal(5,2);not null" json:"gap"`
+	EffectiveGap              float64 `gorm:"type:decimal(5,2);not null" json:"effective_gap"`
+	BobotNilai                float64 `gorm:"type:decimal(7,4);not null" json:"bobot_nilai"`
+	Kontribusi                float64 `gorm:"type:decimal(7,4);not null" json:"kontribusi"`
+	Imputed                   bool    `json:"imputed"`
+}
+
+// ResultStatusHistory records a selection status given to a tenaga kerja for
+// a jabatan. It is kept per jabatan and tenaga kerja rather than per result,
+// so it outlives recalculations; the latest status given by a user carries
+// over to the results of later runs.
+type ResultStatusHistory struct {
+	gorm.Model
+	JabatanID     uint `gorm:"not null;index:idx_result_status_history" json:"jabatan_id"`
+	TenagaKerjaID uint `gorm:"not null;index:idx_result_status_history" json:"tenaga_kerja_id"`
+	// ProfileMatchResultID is the result the status was given to
+	ProfileMatchResultID uint   `gorm:"not null" json:"profile_match_result_id"`
+	RunID                *uint  `json:"run_id"`
+	Status               string `gorm:"type:enum('candidate','shortlisted','selected','rejected','reserve');not null" json:"status"`
+	// Auto is true for shortlists proposed by a calculation
+	Auto   bool   `gorm:"default:false" json:"auto"`
+	UserID *uint  `json:"user_id"`
+	User   *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Note   string `gorm:"type:text" json:"note"`
+}
+
+// KnockoutFailure is a nilai minimum a tenaga kerja falls short of; Nilai is
+// nil when the tenaga kerja has no nilai for the kriteria
+type KnockoutFailure struct {
+	KriteriaID   uint     `json:"kriteria_id"`
+	Kode         string   `json:"kode"`
+	Nama         string   `json:"nama"`
+	NilaiMinimum float64  `json:"nilai_minimum"`
+	Nilai        *float64 `json:"nilai"`
+}
+
+// IneligibleResult is a tenaga kerja a calculation run left out of the
+// ranking for failing a nilai minimum or the jabatan's skor minimum
+type IneligibleResult struct {
+	gorm.Model
+	RunID         uint              `gorm:"not null;index" json:"run_id"`
+	TenagaKerjaID uint              `gorm:"not null" json:"tenaga_kerja_id"`
+	JabatanID     uint              `gorm:"not null" json:"jabatan_id"`
+	Failures      []KnockoutFailure `gorm:"type:text;serializer:json" json:"failures"`
+	// BelowSkorMinimum is set with the TotalScore that fell short
+	BelowSkorMinimum bool        `gorm:"default:false" json:"below_skor_minimum"`
+	TotalScore       float64     `gorm:"type:decimal(5,2);not null;default:0" json:"total_score"`
+	TenagaKerja      TenagaKerja `gorm:"foreignKey:TenagaKerjaID" json:"tenaga_kerja,omitempty"`
+}
+
+// AuditChange is the old and new value of a field; Old is nil when the
+// record was created and New when it was deleted
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditLog records a create, update or delete of a record and the user who
+// made it. Entity is the kind of record, e.g. "aspek", "nilai_tenaga_kerja"
+// or "calculation_run"; Changes holds the fields that changed, keyed by
+// their JSON name. Entries are never changed, so there is no gorm.Model.
+type AuditLog struct {
+	ID        uint                   `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time              `gorm:"index" json:"created_at"`
+	UserID    *uint                  `gorm:"index" json:"user_id"`
+	User      *User                  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Entity    string                 `gorm:"type:varchar(50);not null;index:idx_audit_log_entity" json:"entity"`
+	EntityID  uint                   `gorm:"not null;index:idx_audit_log_entity" json:"entity_id"`
+	Action    string                 `gorm:"type:enum('create','update','delete');not null" json:"action"`
+	Changes   map[string]AuditChange `gorm:"type:text;serializer:json" json:"changes"`
 }
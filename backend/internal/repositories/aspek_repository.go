@@ -14,8 +14,15 @@ func NewAspekRepository(db *gorm.DB) *AspekRepository {
 	return &AspekRepository{db: db}
 }
 
+// WithActor returns the repository recording its changes for the user
+func (r *AspekRepository) WithActor(userID *uint) *AspekRepository {
+	return &AspekRepository{db: withActor(r.db, userID)}
+}
+
 func (r *AspekRepository) Create(a *models.Aspek) error {
-	return r.db.Create(a).Error
+	return auditedCreate(r.db, "aspek", a, func(tx *gorm.DB) error {
+		return tx.Create(a).Error
+	})
 }
 
 func (r *AspekRepository) GetAll() ([]models.Aspek, error) {
@@ -35,10 +42,24 @@ func (r *AspekRepository) GetByID(id uint) (*models.Aspek, error) {
 }
 
 func (r *AspekRepository) Update(id uint, a *models.Aspek) error {
-	return r.db.Model(&models.Aspek{}).Where("id = ?", id).Updates(a).Error
+	return auditedChange(r.db, "aspek", &models.Aspek{}, id, "update", func(tx *gorm.DB) error {
+		return tx.Model(&models.Aspek{}).Where("id = ?", id).Updates(a).Error
+	})
 }
 
 func (r *AspekRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Aspek{}, id).Error
+	return auditedChange(r.db, "aspek", &models.Aspek{}, id, "delete", func(tx *gorm.DB) error {
+		return tx.Delete(&models.Aspek{}, id).Error
+	})
 }
 
+// UpdateWithColumns sets the columns, which may hold the nil and zero values
+// Updates skips, and then updates the aspek, in one transaction.
+func (r *AspekRepository) UpdateWithColumns(id uint, a *models.Aspek, columns map[string]interface{}) error {
+	return auditedChange(r.db, "aspek", &models.Aspek{}, id, "update", func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Aspek{}).Where("id = ?", id).Updates(columns).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Aspek{}).Where("id = ?", id).Updates(a).Error
+	})
+}
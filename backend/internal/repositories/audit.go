@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Changes to audited records write their audit log entry in the transaction
+// making the change, so a change is never saved without its entry. The user
+// making the changes travels in the context of the repository's db, set by
+// the repositories' WithActor.
+
+type auditActorKey struct{}
+
+// withActor returns db recording the changes made through it for the user;
+// nil records them without a user
+func withActor(db *gorm.DB, userID *uint) *gorm.DB {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return db.WithContext(context.WithValue(ctx, auditActorKey{}, userID))
+}
+
+func auditActor(db *gorm.DB) *uint {
+	if db.Statement.Context == nil {
+		return nil
+	}
+	userID, _ := db.Statement.Context.Value(auditActorKey{}).(*uint)
+	return userID
+}
+
+// recordAudit writes the audit entry of a change made in tx. before is nil
+// for a create and after for a delete. An update that changes no field is
+// not recorded.
+func recordAudit(tx *gorm.DB, entity string, entityID uint, action string, before, after interface{}) error {
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		return err
+	}
+	if action == "update" && len(changes) == 0 {
+		return nil
+	}
+	return NewAuditLogRepository(tx).Create(&models.AuditLog{
+		UserID:   auditActor(tx),
+		Entity:   entity,
+		EntityID: entityID,
+		Action:   action,
+		Changes:  changes,
+	})
+}
+
+// auditedCreate creates the record with its audit entry in one transaction
+func auditedCreate(db *gorm.DB, entity string, record interface{}, create func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := create(tx); err != nil {
+			return err
+		}
+		return recordAudit(tx, entity, recordID(record), "create", nil, record)
+	})
+}
+
+// auditedChange updates or deletes the record id of the model's table with
+// its audit entry in one transaction, loading the record before and, unless
+// it is deleted, after the change
+func auditedChange(db *gorm.DB, entity string, model interface{}, id uint, action string, change func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		before := newRecord(model)
+		if err := tx.First(before, id).Error; err != nil {
+			return err
+		}
+		if err := change(tx); err != nil {
+			return err
+		}
+		var after interface{}
+		if action != "delete" {
+			after = newRecord(model)
+			if err := tx.First(after, id).Error; err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, entity, id, action, before, after)
+	})
+}
+
+// newRecord returns a new zero record of the model's type
+func newRecord(model interface{}) interface{} {
+	return reflect.New(reflect.TypeOf(model).Elem()).Interface()
+}
+
+// recordID returns the ID of a record, a pointer to a model
+func recordID(record interface{}) uint {
+	return uint(reflect.Indirect(reflect.ValueOf(record)).FieldByName("ID").Uint())
+}
+
+// gormModelFields are the gorm.Model fields, which have no JSON names and
+// change with every save
+var gormModelFields = []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"}
+
+// auditChanges compares the fields of two versions of a record; either may
+// be nil
+func auditChanges(before, after interface{}) (map[string]models.AuditChange, error) {
+	old, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	current, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]models.AuditChange)
+	for key, value := range old {
+		if !reflect.DeepEqual(value, current[key]) {
+			changes[key] = models.AuditChange{Old: value, New: current[key]}
+		}
+	}
+	for key, value := range current {
+		if _, ok := old[key]; !ok {
+			changes[key] = models.AuditChange{New: value}
+		}
+	}
+	return changes, nil
+}
+
+// auditFields returns the fields of a record by JSON name. Associated
+// records, recognisable by their ID, are left out as they are audited on
+// their own; the gorm.Model fields are left out throughout.
+func auditFields(record interface{}) (map[string]interface{}, error) {
+	var fields map[string]interface{}
+	if record != nil {
+		b, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return nil, err
+		}
+	}
+	if fields == nil {
+		return map[string]interface{}{}, nil // A nil pointer encodes as null
+	}
+
+	for key, value := range fields {
+		if object, ok := value.(map[string]interface{}); ok {
+			if _, ok := object["ID"]; ok {
+				delete(fields, key)
+				continue
+			}
+		}
+		fields[key] = withoutModelFields(value)
+	}
+	for _, key := range gormModelFields {
+		delete(fields, key)
+	}
+	return fields, nil
+}
+
+// withoutModelFields removes the gorm.Model fields from the objects in a
+// decoded JSON value, such as the entries of a gap weight table
+func withoutModelFields(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range gormModelFields {
+			delete(v, key)
+		}
+		for key, item := range v {
+			v[key] = withoutModelFields(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = withoutModelFields(item)
+		}
+	}
+	return value
+}
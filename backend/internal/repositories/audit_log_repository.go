@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"time"
+
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter limits the audit log; zero fields match everything. From is
+// inclusive and To exclusive.
+type AuditLogFilter struct {
+	Entity   string
+	EntityID uint
+	UserID   uint
+	From     *time.Time
+	To       *time.Time
+}
+
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (r *AuditLogRepository) Create(entry *models.AuditLog) error {
+	return r.db.Omit("User").Create(entry).Error
+}
+
+// GetAll returns the entries matching the filter, newest first
+func (r *AuditLogRepository) GetAll(filter AuditLogFilter) ([]models.AuditLog, error) {
+	query := r.db.Preload("User").Order("id DESC")
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var list []models.AuditLog
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAuditChanges_Update(t *testing.T) {
+	before := &models.Kriteria{Model: gorm.Model{ID: 1}, Kode: "K1", Nama: "Komunikasi", AspekID: 1, Bobot: 1}
+	after := &models.Kriteria{Model: gorm.Model{ID: 1, UpdatedAt: time.Now()}, Kode: "K1", Nama: "Komunikasi Lisan", AspekID: 2, Bobot: 1,
+		Aspek: models.Aspek{Model: gorm.Model{ID: 2}, Nama: "Kepribadian"}}
+
+	changes, err := auditChanges(before, after)
+	assert.NoError(t, err)
+	// Only the changed fields; the preloaded aspek and the timestamps are left out
+	assert.Equal(t, map[string]models.AuditChange{
+		"nama":     {Old: "Komunikasi", New: "Komunikasi Lisan"},
+		"aspek_id": {Old: float64(1), New: float64(2)},
+	}, changes)
+}
+
+func TestAuditChanges_CreateAndDelete(t *testing.T) {
+	minimum := 3.0
+	profile := &models.TargetProfile{Model: gorm.Model{ID: 5}, JabatanID: 1, KriteriaID: 2, TargetNilai: 4, NilaiMinimum: &minimum}
+
+	created, err := auditChanges(nil, profile)
+	assert.NoError(t, err)
+	assert.Equal(t, models.AuditChange{New: float64(4)}, created["target_nilai"])
+	assert.Equal(t, models.AuditChange{New: float64(3)}, created["nilai_minimum"])
+	assert.NotContains(t, created, "ID")
+	assert.NotContains(t, created, "jabatan")
+
+	deleted, err := auditChanges(profile, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, models.AuditChange{Old: float64(4)}, deleted["target_nilai"])
+	assert.Len(t, deleted, len(created))
+
+	var nilai *models.NilaiTenagaKerja
+	changes, err := auditChanges(nilai, nilai)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestAuditChanges_NestedEntries(t *testing.T) {
+	before := &models.GapWeightTable{Nama: "Ketat", Entries: []models.GapWeightEntry{
+		{Model: gorm.Model{ID: 1}, GapWeightTableID: 1, Gap: 0, Bobot: 5},
+	}}
+	// Entries are replaced on update, so their IDs change
+	same := &models.GapWeightTable{Nama: "Ketat", Entries: []models.GapWeightEntry{
+		{Model: gorm.Model{ID: 7, CreatedAt: time.Now()}, GapWeightTableID: 1, Gap: 0, Bobot: 5},
+	}}
+	changes, err := auditChanges(before, same)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changed := &models.GapWeightTable{Nama: "Ketat", Entries: []models.GapWeightEntry{
+		{Model: gorm.Model{ID: 8}, GapWeightTableID: 1, Gap: 0, Bobot: 4.5},
+	}}
+	changes, err = auditChanges(before, changed)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"gap_weight_table_id": float64(1), "gap": float64(0), "bobot": float64(4.5)}},
+		changes["entries"].New)
+}
+
+func TestAuditedChanges(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	user := &models.User{Email: "auditor@example.com", Password: "x", Nama: "Auditor"}
+	assert.NoError(t, NewUserRepository(db).Create(user))
+	aspekRepo := NewAspekRepository(db).WithActor(&user.ID)
+	auditLogRepo := NewAuditLogRepository(db)
+
+	aspek := &models.Aspek{Nama: "Kompetensi", Persentase: 50}
+	assert.NoError(t, aspekRepo.Create(aspek))
+	assert.NoError(t, aspekRepo.Update(aspek.ID, &models.Aspek{Persentase: 60}))
+	// An update that changes nothing is not recorded
+	assert.NoError(t, aspekRepo.Update(aspek.ID, &models.Aspek{Persentase: 60}))
+	assert.NoError(t, aspekRepo.Delete(aspek.ID))
+
+	entries, err := auditLogRepo.GetAll(AuditLogFilter{Entity: "aspek", EntityID: aspek.ID})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "delete", entries[0].Action)
+		assert.Equal(t, "update", entries[1].Action)
+		assert.Equal(t, models.AuditChange{Old: float64(50), New: float64(60)}, entries[1].Changes["persentase"])
+		assert.Equal(t, "create", entries[2].Action)
+		assert.Equal(t, &user.ID, entries[2].UserID)
+	}
+
+	// A change that fails is rolled back with its entry
+	assert.Error(t, aspekRepo.Update(aspek.ID, &models.Aspek{Persentase: 70}))
+	entries, _ = auditLogRepo.GetAll(AuditLogFilter{Entity: "aspek"})
+	assert.Len(t, entries, 3)
+
+	// Without an actor the entry has no user
+	entries, _ = auditLogRepo.GetAll(AuditLogFilter{Entity: "user"})
+	if assert.Len(t, entries, 1) {
+		assert.Nil(t, entries[0].UserID)
+	}
+}
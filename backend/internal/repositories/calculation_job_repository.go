@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"time"
+
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CalculationJobRepository struct {
+	db *gorm.DB
+}
+
+func NewCalculationJobRepository(db *gorm.DB) *CalculationJobRepository {
+	return &CalculationJobRepository{db: db}
+}
+
+// WithActor returns the repository recording its changes for the user
+func (r *CalculationJobRepository) WithActor(userID *uint) *CalculationJobRepository {
+	return &CalculationJobRepository{db: withActor(r.db, userID)}
+}
+
+func (r *CalculationJobRepository) Create(job *models.CalculationJob) error {
+	return auditedCreate(r.db, "calculation_job", job, func(tx *gorm.DB) error {
+		return tx.Create(job).Error
+	})
+}
+
+func (r *CalculationJobRepository) GetByID(id uint) (*models.CalculationJob, error) {
+	var job models.CalculationJob
+	if err := r.db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Start moves a queued job to running; it reports false when the job is no
+// longer queued, e.g. because it was cancelled
+func (r *CalculationJobRepository) Start(id uint) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&models.CalculationJob{}).Where("id = ? AND status = ?", id, "queued").
+		Updates(map[string]interface{}{"status": "running", "started_at": &now})
+	return result.RowsAffected == 1, result.Error
+}
+
+// unfinishedJobStatuses are the statuses of jobs an instance still owns
+var unfinishedJobStatuses = []string{"queued", "running"}
+
+// UpdateProgress records the jabatan processed so far; it reports false when
+// the job is no longer running, e.g. because it was failed as stale
+func (r *CalculationJobRepository) UpdateProgress(job *models.CalculationJob) (bool, error) {
+	result := r.db.Model(job).Where("status = ?", "running").Select("processed", "errors", "run_ids").Updates(job)
+	return result.RowsAffected == 1, result.Error
+}
+
+// Finish records the final status of a job that is still unfinished
+func (r *CalculationJobRepository) Finish(job *models.CalculationJob, status string) error {
+	now := time.Now()
+	job.Status = status
+	job.FinishedAt = &now
+	return r.db.Model(job).Where("status IN ?", unfinishedJobStatuses).
+		Select("status", "processed", "errors", "run_ids", "finished_at").Updates(job).Error
+}
+
+// CancelQueued cancels a job that has not started; it reports false when the
+// job is not queued
+func (r *CalculationJobRepository) CancelQueued(id uint) (bool, error) {
+	now := time.Now()
+	var cancelled bool
+	err := auditedChange(r.db, "calculation_job", &models.CalculationJob{}, id, "update", func(tx *gorm.DB) error {
+		result := tx.Model(&models.CalculationJob{}).Where("id = ? AND status = ?", id, "queued").
+			Updates(map[string]interface{}{"status": "cancelled", "finished_at": &now})
+		cancelled = result.RowsAffected == 1
+		return result.Error
+	})
+	return cancelled, err
+}
+
+// RequestCancel asks the worker running the job to stop; it reports false
+// when the job is not running
+func (r *CalculationJobRepository) RequestCancel(id uint) (bool, error) {
+	var requested bool
+	err := auditedChange(r.db, "calculation_job", &models.CalculationJob{}, id, "update", func(tx *gorm.DB) error {
+		result := tx.Model(&models.CalculationJob{}).Where("id = ? AND status = ?", id, "running").Update("cancel_requested", true)
+		requested = result.RowsAffected == 1
+		return result.Error
+	})
+	return requested, err
+}
+
+// CancelRequested reports whether cancelling the job was requested
+func (r *CalculationJobRepository) CancelRequested(id uint) (bool, error) {
+	var job models.CalculationJob
+	if err := r.db.Select("cancel_requested").First(&job, id).Error; err != nil {
+		return false, err
+	}
+	return job.CancelRequested, nil
+}
+
+// Heartbeat marks the unfinished jobs of the owner as still being worked on
+func (r *CalculationJobRepository) Heartbeat(owner string) error {
+	return r.db.Model(&models.CalculationJob{}).Where("owner = ? AND status IN ?", owner, unfinishedJobStatuses).
+		Update("heartbeat_at", time.Now()).Error
+}
+
+// FailStale fails the unfinished jobs whose owner has not marked them since
+// staleBefore, as the process that owned them has stopped
+func (r *CalculationJobRepository) FailStale(staleBefore time.Time, message string) error {
+	now := time.Now()
+	return r.db.Model(&models.CalculationJob{}).
+		Where("status IN ? AND (heartbeat_at IS NULL OR heartbeat_at < ?)", unfinishedJobStatuses, staleBefore).
+		Updates(&models.CalculationJob{Status: "failed", Errors: []string{message}, FinishedAt: &now}).Error
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrLockNotAcquired is returned by WithJabatanLock when another connection
+// holds the jabatan's lock
+var ErrLockNotAcquired = errors.New("lock not acquired")
+
+type CalculationRunRepository struct {
+	db *gorm.DB
+}
+
+func NewCalculationRunRepository(db *gorm.DB) *CalculationRunRepository {
+	return &CalculationRunRepository{db: db}
+}
+
+// WithActor returns the repository recording its changes for the user
+func (r *CalculationRunRepository) WithActor(userID *uint) *CalculationRunRepository {
+	return &CalculationRunRepository{db: withActor(r.db, userID)}
+}
+
+func (r *CalculationRunRepository) Create(run *models.CalculationRun) error {
+	return auditedCreate(r.db, "calculation_run", run, func(tx *gorm.DB) error {
+		return tx.Omit("Results").Create(run).Error
+	})
+}
+
+// GetAll returns the runs newest first; a non-zero jabatanID limits them to
+// that jabatan
+func (r *CalculationRunRepository) GetAll(jabatanID uint) ([]models.CalculationRun, error) {
+	var list []models.CalculationRun
+	query := r.db.Preload("Jabatan").Preload("User").Order("id DESC")
+	if jabatanID != 0 {
+		query = query.Where("jabatan_id = ?", jabatanID)
+	}
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *CalculationRunRepository) GetByID(id uint) (*models.CalculationRun, error) {
+	var run models.CalculationRun
+	if err := r.db.Preload("Jabatan").Preload("User").First(&run, id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *CalculationRunRepository) UpdateStatus(id uint, status, errMsg string) error {
+	return r.db.Model(&models.CalculationRun{}).Where("id = ?", id).Updates(map[string]interface{}{"status": status, "error": errMsg}).Error
+}
+
+// resultBatchSize bounds the results, with their snapshots, inserted per
+// statement to stay below MySQL's placeholder limit
+const resultBatchSize = 100
+
+// Complete stores the run's results, the statuses proposed for them and the
+// tenaga kerja knocked out, and marks the run completed in one transaction,
+// so a failure leaves neither the results nor a completed run. Each history
+// entry is linked to the result of its tenaga kerja.
+func (r *CalculationRunRepository) Complete(id uint, results []models.ProfileMatchResult, history []models.ResultStatusHistory, ineligible []models.IneligibleResult) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(results) > 0 {
+			if err := tx.CreateInBatches(&results, resultBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(history) > 0 {
+			resultIDs := make(map[uint]uint, len(results))
+			for _, result := range results {
+				resultIDs[result.TenagaKerjaID] = result.ID
+			}
+			for i := range history {
+				history[i].ProfileMatchResultID = resultIDs[history[i].TenagaKerjaID]
+			}
+			if err := tx.CreateInBatches(&history, resultBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(ineligible) > 0 {
+			if err := tx.CreateInBatches(&ineligible, resultBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.CalculationRun{}).Where("id = ?", id).Updates(map[string]interface{}{"status": "completed", "error": ""}).Error
+	})
+}
+
+// WithJabatanLock runs fn while holding a MySQL named lock for the jabatan.
+// The lock is held by a dedicated connection, so it excludes calculations
+// from every API instance sharing the database; it is not waited for.
+func (r *CalculationRunRepository) WithJabatanLock(jabatanID uint, fn func() error) error {
+	name := fmt.Sprintf("spk_profile_matching.calculate.%d", jabatanID)
+	return r.db.Connection(func(conn *gorm.DB) error {
+		var acquired sql.NullInt64
+		if err := conn.Raw("SELECT GET_LOCK(?, 0)", name).Row().Scan(&acquired); err != nil {
+			return err
+		}
+		if !acquired.Valid || acquired.Int64 != 1 {
+			return ErrLockNotAcquired
+		}
+		defer conn.Exec("DO RELEASE_LOCK(?)", name)
+
+		return fn()
+	})
+}
+
+// SetOfficial makes the run the only official run of its jabatan, auditing
+// the runs whose official mark changes
+func (r *CalculationRunRepository) SetOfficial(id, jabatanID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var run models.CalculationRun
+		if err := tx.First(&run, id).Error; err != nil {
+			return err
+		}
+		var previous []models.CalculationRun
+		if err := tx.Where("jabatan_id = ? AND id <> ? AND is_official = ?", jabatanID, id, true).Find(&previous).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.CalculationRun{}).Where("jabatan_id = ? AND id <> ?", jabatanID, id).Update("is_official", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.CalculationRun{}).Where("id = ?", id).Update("is_official", true).Error; err != nil {
+			return err
+		}
+
+		for i := range previous {
+			unofficial := previous[i]
+			unofficial.IsOfficial = false
+			if err := recordAudit(tx, "calculation_run", previous[i].ID, "update", &previous[i], &unofficial); err != nil {
+				return err
+			}
+		}
+		official := run
+		official.IsOfficial = true
+		return recordAudit(tx, "calculation_run", id, "update", &run, &official)
+	})
+}
+
+// GetCurrentIDs returns, per jabatan, the official run or, when none is
+// official, the latest completed run
+func (r *CalculationRunRepository) GetCurrentIDs() ([]uint, error) {
+	var runs []models.CalculationRun
+	if err := r.db.Select("id", "jabatan_id", "is_official").Where("status = ?", "completed").Order("id ASC").Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	current := make(map[uint]models.CalculationRun)
+	for _, run := range runs {
+		if existing, ok := current[run.JabatanID]; ok && existing.IsOfficial {
+			continue
+		}
+		current[run.JabatanID] = run
+	}
+
+	ids := make([]uint, 0, len(current))
+	for _, run := range current {
+		ids = append(ids, run.ID)
+	}
+	return ids, nil
+}
+
+// GetCurrent returns the official run of the jabatan or, when none is
+// official, its latest completed run
+func (r *CalculationRunRepository) GetCurrent(jabatanID uint) (*models.CalculationRun, error) {
+	var run models.CalculationRun
+	err := r.db.Where("jabatan_id = ? AND status = ?", jabatanID, "completed").
+		Order("is_official DESC").Order("id DESC").First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"testing"
+
+	"backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculationRunRepository_GetCurrentIDs(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewCalculationRunRepository(db)
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	NewJabatanRepository(db).Create(jabatan)
+
+	first := &models.CalculationRun{JabatanID: jabatan.ID, Status: "completed"}
+	second := &models.CalculationRun{JabatanID: jabatan.ID, Status: "completed"}
+	failed := &models.CalculationRun{JabatanID: jabatan.ID, Status: "failed"}
+	assert.NoError(t, repo.Create(first))
+	assert.NoError(t, repo.Create(second))
+	assert.NoError(t, repo.Create(failed))
+
+	// The latest completed run is current while none is official
+	ids, err := repo.GetCurrentIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{second.ID}, ids)
+
+	assert.NoError(t, repo.SetOfficial(first.ID, jabatan.ID))
+	ids, err = repo.GetCurrentIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{first.ID}, ids)
+
+	// Marking another run official clears the previous one
+	assert.NoError(t, repo.SetOfficial(second.ID, jabatan.ID))
+	run, err := repo.GetByID(first.ID)
+	assert.NoError(t, err)
+	assert.False(t, run.IsOfficial)
+}
+
+func TestCalculationRunRepository_UpdateStatus(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewCalculationRunRepository(db)
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	NewJabatanRepository(db).Create(jabatan)
+
+	run := &models.CalculationRun{
+		JabatanID:  jabatan.ID,
+		Parameters: models.CalculationParameters{GapMode: "round", Method: "saw"},
+		Status:     "running",
+	}
+	assert.NoError(t, repo.Create(run))
+	assert.NoError(t, repo.UpdateStatus(run.ID, "failed", "could not save"))
+
+	found, err := repo.GetByID(run.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "failed", found.Status)
+	assert.Equal(t, "could not save", found.Error)
+	assert.Equal(t, "saw", found.Parameters.Method)
+}
+
+func TestCalculationRunRepository_WithJabatanLock(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewCalculationRunRepository(db)
+
+	called := false
+	err := repo.WithJabatanLock(1, func() error {
+		called = true
+		// A second calculation of the same jabatan is rejected, another
+		// jabatan is not affected
+		assert.Equal(t, ErrLockNotAcquired, repo.WithJabatanLock(1, func() error { return nil }))
+		assert.NoError(t, repo.WithJabatanLock(2, func() error { return nil }))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+
+	// The lock is released afterwards
+	assert.NoError(t, repo.WithJabatanLock(1, func() error { return nil }))
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type GapWeightTableRepository struct {
+	db *gorm.DB
+}
+
+func NewGapWeightTableRepository(db *gorm.DB) *GapWeightTableRepository {
+	return &GapWeightTableRepository{db: db}
+}
+
+// WithActor returns the repository recording its changes for the user
+func (r *GapWeightTableRepository) WithActor(userID *uint) *GapWeightTableRepository {
+	return &GapWeightTableRepository{db: withActor(r.db, userID)}
+}
+
+func (r *GapWeightTableRepository) Create(t *models.GapWeightTable) error {
+	return auditedCreate(r.db, "gap_weight_table", t, func(tx *gorm.DB) error {
+		return tx.Create(t).Error
+	})
+}
+
+func (r *GapWeightTableRepository) GetAll() ([]models.GapWeightTable, error) {
+	var list []models.GapWeightTable
+	if err := r.db.Preload("Entries", orderEntriesByGap).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *GapWeightTableRepository) GetByID(id uint) (*models.GapWeightTable, error) {
+	var t models.GapWeightTable
+	if err := r.db.Preload("Entries", orderEntriesByGap).First(&t, id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Update updates the table fields. When entries is not nil the existing
+// entries are replaced by it in the same transaction.
+func (r *GapWeightTableRepository) Update(id uint, t *models.GapWeightTable, entries []models.GapWeightEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		before, err := NewGapWeightTableRepository(tx).GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.GapWeightTable{}).Where("id = ?", id).Omit("Entries").Updates(t).Error; err != nil {
+			return err
+		}
+		if entries != nil {
+			if err := tx.Unscoped().Where("gap_weight_table_id = ?", id).Delete(&models.GapWeightEntry{}).Error; err != nil {
+				return err
+			}
+			for i := range entries {
+				entries[i].GapWeightTableID = id
+			}
+			if len(entries) > 0 {
+				if err := tx.Create(&entries).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		after, err := NewGapWeightTableRepository(tx).GetByID(id)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, "gap_weight_table", id, "update", before, after)
+	})
+}
+
+func (r *GapWeightTableRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		before, err := NewGapWeightTableRepository(tx).GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("gap_weight_table_id = ?", id).Delete(&models.GapWeightEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.GapWeightTable{}, id).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, "gap_weight_table", id, "delete", before, nil)
+	})
+}
+
+func (r *GapWeightTableRepository) ExistsByNama(nama string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.GapWeightTable{}).Where("nama = ?", nama).Count(&count).Error
+	return count > 0, err
+}
+
+func orderEntriesByGap(db *gorm.DB) *gorm.DB {
+	return db.Order("gap ASC")
+}
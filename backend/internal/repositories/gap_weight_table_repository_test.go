@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"testing"
+
+	"backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGapWeightTable() *models.GapWeightTable {
+	return &models.GapWeightTable{
+		Nama:       "Asimetris",
+		BelowRange: "zero",
+		AboveRange: "clamp",
+		Entries: []models.GapWeightEntry{
+			{Gap: 1, Bobot: 4.5},
+			{Gap: 0, Bobot: 5},
+			{Gap: -1, Bobot: 3},
+		},
+	}
+}
+
+func TestGapWeightTableRepository_Create(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewGapWeightTableRepository(db)
+
+	table := newTestGapWeightTable()
+	err := repo.Create(table)
+	assert.NoError(t, err)
+	assert.NotZero(t, table.ID)
+}
+
+func TestGapWeightTableRepository_GetByID_OrdersEntries(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewGapWeightTableRepository(db)
+
+	table := newTestGapWeightTable()
+	repo.Create(table)
+
+	found, err := repo.GetByID(table.ID)
+	assert.NoError(t, err)
+	assert.Len(t, found.Entries, 3)
+	assert.Equal(t, -1.0, found.Entries[0].Gap)
+	assert.Equal(t, 1.0, found.Entries[2].Gap)
+}
+
+func TestGapWeightTableRepository_Update_ReplacesEntries(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewGapWeightTableRepository(db)
+
+	table := newTestGapWeightTable()
+	repo.Create(table)
+
+	err := repo.Update(table.ID, &models.GapWeightTable{Nama: "Baru"}, []models.GapWeightEntry{{Gap: 0, Bobot: 5}})
+	assert.NoError(t, err)
+
+	updated, _ := repo.GetByID(table.ID)
+	assert.Equal(t, "Baru", updated.Nama)
+	assert.Len(t, updated.Entries, 1)
+}
+
+func TestGapWeightTableRepository_Delete(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewGapWeightTableRepository(db)
+
+	table := newTestGapWeightTable()
+	repo.Create(table)
+
+	err := repo.Delete(table.ID)
+	assert.NoError(t, err)
+
+	_, err = repo.GetByID(table.ID)
+	assert.Error(t, err)
+}
@@ -14,8 +14,15 @@ func NewJabatanRepository(db *gorm.DB) *JabatanRepository {
 	return &JabatanRepository{db: db}
 }
 
+// WithActor returns the repository recording its changes for the user
+func (r *JabatanRepository) WithActor(userID *uint) *JabatanRepository {
+	return &JabatanRepository{db: withActor(r.db, userID)}
+}
+
 func (r *JabatanRepository) Create(j *models.Jabatan) error {
-	return r.db.Create(j).Error
+	return auditedCreate(r.db, "jabatan", j, func(tx *gorm.DB) error {
+		return tx.Create(j).Error
+	})
 }
 
 func (r *JabatanRepository) GetAll() ([]models.Jabatan, error) {
@@ -35,9 +42,30 @@ func (r *JabatanRepository) GetByID(id uint) (*models.Jabatan, error) {
 }
 
 func (r *JabatanRepository) Update(id uint, j *models.Jabatan) error {
-	return r.db.Model(&models.Jabatan{}).Where("id = ?", id).Updates(j).Error
+	return auditedChange(r.db, "jabatan", &models.Jabatan{}, id, "update", func(tx *gorm.DB) error {
+		return tx.Model(&models.Jabatan{}).Where("id = ?", id).Updates(j).Error
+	})
 }
 
 func (r *JabatanRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Jabatan{}, id).Error
+	return auditedChange(r.db, "jabatan", &models.Jabatan{}, id, "delete", func(tx *gorm.DB) error {
+		return tx.Delete(&models.Jabatan{}, id).Error
+	})
+}
+
+// UpdateWithColumns sets the columns, which may hold the nil and zero values
+// Updates skips, and then updates the jabatan, in one transaction.
+func (r *JabatanRepository) UpdateWithColumns(id uint, j *models.Jabatan, columns map[string]interface{}) error {
+	return auditedChange(r.db, "jabatan", &models.Jabatan{}, id, "update", func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Jabatan{}).Where("id = ?", id).Updates(columns).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Jabatan{}).Where("id = ?", id).Updates(j).Error
+	})
+}
+
+func (r *JabatanRepository) ExistsByGapWeightTableID(gapWeightTableID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Jabatan{}).Where("gap_weight_table_id = ?", gapWeightTableID).Count(&count).Error
+	return count > 0, err
 }
@@ -14,8 +14,15 @@ func NewKriteriaRepository(db *gorm.DB) *KriteriaRepository {
 	return &KriteriaRepository{db: db}
 }
 
+// WithActor returns the repository recording its changes for the user
+func (r *KriteriaRepository) WithActor(userID *uint) *KriteriaRepository {
+	return &KriteriaRepository{db: withActor(r.db, userID)}
+}
+
 func (r *KriteriaRepository) Create(k *models.Kriteria) error {
-	return r.db.Create(k).Error
+	return auditedCreate(r.db, "kriteria", k, func(tx *gorm.DB) error {
+		return tx.Create(k).Error
+	})
 }
 
 func (r *KriteriaRepository) GetAll() ([]models.Kriteria, error) {
@@ -51,10 +58,13 @@ func (r *KriteriaRepository) GetByAspekID(aspekID uint) ([]models.Kriteria, erro
 }
 
 func (r *KriteriaRepository) Update(id uint, k *models.Kriteria) error {
-	return r.db.Model(&models.Kriteria{}).Where("id = ?", id).Updates(k).Error
+	return auditedChange(r.db, "kriteria", &models.Kriteria{}, id, "update", func(tx *gorm.DB) error {
+		return tx.Model(&models.Kriteria{}).Where("id = ?", id).Updates(k).Error
+	})
 }
 
 func (r *KriteriaRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Kriteria{}, id).Error
+	return auditedChange(r.db, "kriteria", &models.Kriteria{}, id, "delete", func(tx *gorm.DB) error {
+		return tx.Delete(&models.Kriteria{}, id).Error
+	})
 }
-
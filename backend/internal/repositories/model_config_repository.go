@@ -0,0 +1,320 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"backend/internal/dto"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errConfigDryRun rolls back the transaction of a dry-run import
+var errConfigDryRun = errors.New("dry run")
+
+type ModelConfigRepository struct {
+	db *gorm.DB
+}
+
+func NewModelConfigRepository(db *gorm.DB) *ModelConfigRepository {
+	return &ModelConfigRepository{db: db}
+}
+
+// WithActor returns the repository recording its changes for the user
+func (r *ModelConfigRepository) WithActor(userID *uint) *ModelConfigRepository {
+	return &ModelConfigRepository{db: withActor(r.db, userID)}
+}
+
+// Import applies a configuration in one transaction, matching existing
+// records by natural key and overwriting them. With replace, records missing
+// from the configuration are deleted; otherwise they are kept. Every change
+// is audited. A dry run counts the changes and rolls them back.
+func (r *ModelConfigRepository) Import(cfg *dto.ModelConfig, replace, dryRun bool) (dto.ConfigImportSummary, error) {
+	var summary dto.ConfigImportSummary
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := importModelConfig(tx, cfg, replace, &summary); err != nil {
+			return err
+		}
+		if dryRun {
+			return errConfigDryRun
+		}
+		return nil
+	})
+	if err != nil && err != errConfigDryRun {
+		return dto.ConfigImportSummary{}, err
+	}
+	return summary, nil
+}
+
+func importModelConfig(tx *gorm.DB, cfg *dto.ModelConfig, replace bool, summary *dto.ConfigImportSummary) error {
+	// save writes every field, so values missing from the configuration are
+	// cleared; associations are written separately
+	save := func(value interface{}, isNew bool, counts *dto.ConfigImportCounts) error {
+		if err := tx.Omit(clause.Associations).Save(value).Error; err != nil {
+			return err
+		}
+		if isNew {
+			counts.Created++
+		} else {
+			counts.Updated++
+		}
+		return nil
+	}
+	// record audits the change of a record from before, nil when the record
+	// is new
+	record := func(entity string, id uint, before, after interface{}) error {
+		if before == nil {
+			return recordAudit(tx, entity, id, "create", nil, after)
+		}
+		return recordAudit(tx, entity, id, "update", before, after)
+	}
+
+	var tables []models.GapWeightTable
+	if err := tx.Preload("Entries", orderEntriesByGap).Order("id ASC").Find(&tables).Error; err != nil {
+		return err
+	}
+	tableByNama := make(map[string]*models.GapWeightTable)
+	for i := range tables {
+		if _, ok := tableByNama[tables[i].Nama]; !ok {
+			tableByNama[tables[i].Nama] = &tables[i]
+		}
+	}
+	keptTables := []uint{}
+	for _, c := range cfg.GapWeightTables {
+		t, ok := tableByNama[c.Nama]
+		var before interface{}
+		if ok {
+			old := *t
+			before = &old
+		} else {
+			t = &models.GapWeightTable{}
+			tableByNama[c.Nama] = t
+		}
+		t.Nama, t.Deskripsi, t.BelowRange, t.AboveRange = c.Nama, c.Deskripsi, c.BelowRange, c.AboveRange
+		if err := save(t, !ok, &summary.GapWeightTables); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("gap_weight_table_id = ?", t.ID).Delete(&models.GapWeightEntry{}).Error; err != nil {
+			return err
+		}
+		entries := make([]models.GapWeightEntry, len(c.Entries))
+		for i, e := range c.Entries {
+			entries[i] = models.GapWeightEntry{GapWeightTableID: t.ID, Gap: e.Gap, Bobot: e.Bobot}
+		}
+		if len(entries) > 0 {
+			if err := tx.Create(&entries).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("gap_weight_table_id = ?", t.ID).Scopes(orderEntriesByGap).Find(&t.Entries).Error; err != nil {
+			return err
+		}
+		if err := record("gap_weight_table", t.ID, before, t); err != nil {
+			return err
+		}
+		keptTables = append(keptTables, t.ID)
+	}
+
+	var aspeks []models.Aspek
+	if err := tx.Order("id ASC").Find(&aspeks).Error; err != nil {
+		return err
+	}
+	aspekByNama := make(map[string]*models.Aspek)
+	for i := range aspeks {
+		if _, ok := aspekByNama[aspeks[i].Nama]; !ok {
+			aspekByNama[aspeks[i].Nama] = &aspeks[i]
+		}
+	}
+	keptAspek := []uint{}
+	for _, c := range cfg.Aspek {
+		a, ok := aspekByNama[c.Nama]
+		var before interface{}
+		if ok {
+			old := *a
+			before = &old
+		} else {
+			a = &models.Aspek{}
+			aspekByNama[c.Nama] = a
+		}
+		a.Nama, a.Deskripsi, a.Persentase = c.Nama, c.Deskripsi, c.Persentase
+		a.CoreFactorPersen, a.SecondaryFactorPersen = c.CoreFactorPersen, c.SecondaryFactorPersen
+		if err := save(a, !ok, &summary.Aspek); err != nil {
+			return err
+		}
+		if err := record("aspek", a.ID, before, a); err != nil {
+			return err
+		}
+		keptAspek = append(keptAspek, a.ID)
+	}
+
+	var kriterias []models.Kriteria
+	if err := tx.Order("id ASC").Find(&kriterias).Error; err != nil {
+		return err
+	}
+	kriteriaByKode := make(map[string]*models.Kriteria)
+	for i := range kriterias {
+		if _, ok := kriteriaByKode[kriterias[i].Kode]; !ok {
+			kriteriaByKode[kriterias[i].Kode] = &kriterias[i]
+		}
+	}
+	keptKriteria := []uint{}
+	for _, c := range cfg.Kriteria {
+		aspek, ok := aspekByNama[c.Aspek]
+		if !ok {
+			return fmt.Errorf("kriteria %s: aspek %q not found", c.Kode, c.Aspek)
+		}
+		k, ok := kriteriaByKode[c.Kode]
+		var before interface{}
+		if ok {
+			old := *k
+			before = &old
+		} else {
+			k = &models.Kriteria{}
+			kriteriaByKode[c.Kode] = k
+		}
+		k.Kode, k.Nama, k.AspekID, k.IsCore, k.Bobot = c.Kode, c.Nama, aspek.ID, c.IsCore, c.Bobot
+		if err := save(k, !ok, &summary.Kriteria); err != nil {
+			return err
+		}
+		if err := record("kriteria", k.ID, before, k); err != nil {
+			return err
+		}
+		keptKriteria = append(keptKriteria, k.ID)
+	}
+
+	var jabatans []models.Jabatan
+	if err := tx.Order("id ASC").Find(&jabatans).Error; err != nil {
+		return err
+	}
+	jabatanByNama := make(map[string]*models.Jabatan)
+	for i := range jabatans {
+		if _, ok := jabatanByNama[jabatans[i].Nama]; !ok {
+			jabatanByNama[jabatans[i].Nama] = &jabatans[i]
+		}
+	}
+	keptJabatan, keptTargetProfiles := []uint{}, []uint{}
+	for _, c := range cfg.Jabatan {
+		j, ok := jabatanByNama[c.Nama]
+		var before interface{}
+		if ok {
+			old := *j
+			before = &old
+		} else {
+			j = &models.Jabatan{}
+			jabatanByNama[c.Nama] = j
+		}
+		j.Nama, j.Deskripsi, j.JumlahLowongan, j.SkorMinimum = c.Nama, c.Deskripsi, c.JumlahLowongan, c.SkorMinimum
+		j.CoreFactorPersen, j.SecondaryFactorPersen = c.CoreFactorPersen, c.SecondaryFactorPersen
+		j.GapWeightTableID = nil
+		if c.GapWeightTable != "" {
+			table, found := tableByNama[c.GapWeightTable]
+			if !found {
+				return fmt.Errorf("jabatan %s: gap weight table %q not found", c.Nama, c.GapWeightTable)
+			}
+			j.GapWeightTableID = &table.ID
+		}
+		if err := save(j, !ok, &summary.Jabatan); err != nil {
+			return err
+		}
+		if err := record("jabatan", j.ID, before, j); err != nil {
+			return err
+		}
+		keptJabatan = append(keptJabatan, j.ID)
+
+		var targetProfiles []models.TargetProfile
+		if err := tx.Where("jabatan_id = ?", j.ID).Order("id ASC").Find(&targetProfiles).Error; err != nil {
+			return err
+		}
+		targetByKriteria := make(map[uint]*models.TargetProfile)
+		for i := range targetProfiles {
+			if _, found := targetByKriteria[targetProfiles[i].KriteriaID]; !found {
+				targetByKriteria[targetProfiles[i].KriteriaID] = &targetProfiles[i]
+			}
+		}
+		kodes := make([]string, 0, len(c.TargetProfiles))
+		for kode := range c.TargetProfiles {
+			kodes = append(kodes, kode)
+		}
+		sort.Strings(kodes)
+		for _, kode := range kodes {
+			kriteria, found := kriteriaByKode[kode]
+			if !found {
+				return fmt.Errorf("jabatan %s: kriteria %q not found", c.Nama, kode)
+			}
+			tp, found := targetByKriteria[kriteria.ID]
+			var before interface{}
+			if found {
+				old := *tp
+				before = &old
+			} else {
+				tp = &models.TargetProfile{JabatanID: j.ID, KriteriaID: kriteria.ID}
+			}
+			tp.TargetNilai, tp.NilaiMinimum = c.TargetProfiles[kode].TargetNilai, c.TargetProfiles[kode].NilaiMinimum
+			if err := save(tp, !found, &summary.TargetProfiles); err != nil {
+				return err
+			}
+			if err := record("target_profile", tp.ID, before, tp); err != nil {
+				return err
+			}
+			keptTargetProfiles = append(keptTargetProfiles, tp.ID)
+		}
+	}
+
+	if !replace {
+		return nil
+	}
+	// Dependents go first; deletes are soft, so stored results keep their
+	// references
+	var err error
+	if summary.TargetProfiles.Deleted, err = deleteExcept(tx, "target_profile", &models.TargetProfile{}, keptTargetProfiles); err != nil {
+		return err
+	}
+	if summary.Jabatan.Deleted, err = deleteExcept(tx, "jabatan", &models.Jabatan{}, keptJabatan); err != nil {
+		return err
+	}
+	if summary.Kriteria.Deleted, err = deleteExcept(tx, "kriteria", &models.Kriteria{}, keptKriteria); err != nil {
+		return err
+	}
+	if summary.Aspek.Deleted, err = deleteExcept(tx, "aspek", &models.Aspek{}, keptAspek); err != nil {
+		return err
+	}
+	if summary.GapWeightTables.Deleted, err = deleteExcept(tx, "gap_weight_table", &models.GapWeightTable{}, keptTables, "Entries"); err != nil {
+		return err
+	}
+	return tx.Where("gap_weight_table_id NOT IN ?", append(keptTables, 0)).Delete(&models.GapWeightEntry{}).Error
+}
+
+// deleteExcept soft-deletes and audits the records of the model whose ID is
+// not kept, loaded with the preloaded associations for their audit entries
+func deleteExcept(tx *gorm.DB, entity string, model interface{}, kept []uint, preload ...string) (int, error) {
+	// An empty IN list would match nothing, so 0 stands in for it
+	kept = append(kept, 0)
+	records := reflect.New(reflect.SliceOf(reflect.TypeOf(model).Elem()))
+	query := tx
+	for _, association := range preload {
+		query = query.Preload(association)
+	}
+	if err := query.Where("id NOT IN ?", kept).Find(records.Interface()).Error; err != nil {
+		return 0, err
+	}
+	list := records.Elem()
+	if list.Len() == 0 {
+		return 0, nil
+	}
+
+	result := tx.Where("id NOT IN ?", kept).Delete(model)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	for i := 0; i < list.Len(); i++ {
+		record := list.Index(i).Addr().Interface()
+		if err := recordAudit(tx, entity, recordID(record), "delete", record, nil); err != nil {
+			return 0, err
+		}
+	}
+	return int(result.RowsAffected), nil
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"testing"
+
+	"backend/internal/dto"
+	"backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// seedModelConfig stores a jabatan "Manager" targeting K1 and K9, a jabatan
+// "Staf", and the aspek, kriteria and gap weight tables they use. Only part
+// of it is in newTestModelConfig.
+func seedModelConfig(t *testing.T, db *gorm.DB) *models.Jabatan {
+	tables := NewGapWeightTableRepository(db)
+	assert.NoError(t, tables.Create(&models.GapWeightTable{Nama: "Ketat", Entries: []models.GapWeightEntry{{Gap: 0, Bobot: 5}}}))
+	assert.NoError(t, tables.Create(&models.GapWeightTable{Nama: "Lama", Entries: []models.GapWeightEntry{{Gap: 0, Bobot: 4}}}))
+
+	kompetensi := &models.Aspek{Nama: "Kompetensi", Persentase: 50}
+	lama := &models.Aspek{Nama: "Lama", Persentase: 50}
+	assert.NoError(t, NewAspekRepository(db).Create(kompetensi))
+	assert.NoError(t, NewAspekRepository(db).Create(lama))
+
+	k1 := &models.Kriteria{AspekID: kompetensi.ID, Kode: "K1", Nama: "Komunikasi", Bobot: 1}
+	k9 := &models.Kriteria{AspekID: lama.ID, Kode: "K9", Nama: "Lama", Bobot: 1}
+	assert.NoError(t, NewKriteriaRepository(db).Create(k1))
+	assert.NoError(t, NewKriteriaRepository(db).Create(k9))
+
+	manager := &models.Jabatan{Nama: "Manager"}
+	assert.NoError(t, NewJabatanRepository(db).Create(manager))
+	assert.NoError(t, NewJabatanRepository(db).Create(&models.Jabatan{Nama: "Staf"}))
+	assert.NoError(t, NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: manager.ID, KriteriaID: k1.ID, TargetNilai: 3}))
+	assert.NoError(t, NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: manager.ID, KriteriaID: k9.ID, TargetNilai: 3}))
+	return manager
+}
+
+// newTestModelConfig updates Ketat, Kompetensi, K1 and Manager, adds the
+// aspek Kepribadian with K2, and leaves out everything else
+func newTestModelConfig() *dto.ModelConfig {
+	return &dto.ModelConfig{
+		Version: dto.ModelConfigVersion,
+		GapWeightTables: []dto.GapWeightTableConfig{
+			{Nama: "Ketat", BelowRange: "zero", AboveRange: "clamp", Entries: []dto.GapWeightEntryConfig{{Gap: 0, Bobot: 5}, {Gap: -1, Bobot: 3}}},
+		},
+		Aspek: []dto.AspekConfig{
+			{Nama: "Kompetensi", Persentase: 60},
+			{Nama: "Kepribadian", Persentase: 40},
+		},
+		Kriteria: []dto.KriteriaConfig{
+			{Kode: "K1", Nama: "Komunikasi Lisan", Aspek: "Kompetensi", IsCore: true, Bobot: 1},
+			{Kode: "K2", Nama: "Integritas", Aspek: "Kepribadian", Bobot: 1},
+		},
+		Jabatan: []dto.JabatanConfig{
+			{Nama: "Manager", GapWeightTable: "Ketat", JumlahLowongan: 2, TargetProfiles: map[string]dto.TargetProfileConfig{
+				"K1": {TargetNilai: 4},
+				"K2": {TargetNilai: 3},
+			}},
+		},
+	}
+}
+
+func TestModelConfigRepository_Import_Merge(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	user := &models.User{Email: "admin@example.com", Password: "x", Nama: "Admin"}
+	assert.NoError(t, NewUserRepository(db).Create(user))
+	repo := NewModelConfigRepository(db).WithActor(&user.ID)
+	manager := seedModelConfig(t, db)
+
+	summary, err := repo.Import(newTestModelConfig(), false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.ConfigImportSummary{
+		GapWeightTables: dto.ConfigImportCounts{Updated: 1},
+		Aspek:           dto.ConfigImportCounts{Created: 1, Updated: 1},
+		Kriteria:        dto.ConfigImportCounts{Created: 1, Updated: 1},
+		Jabatan:         dto.ConfigImportCounts{Updated: 1},
+		TargetProfiles:  dto.ConfigImportCounts{Created: 1, Updated: 1},
+	}, summary)
+
+	// Records missing from the configuration are kept
+	aspeks, _ := NewAspekRepository(db).GetAll()
+	assert.Len(t, aspeks, 3)
+	kriterias, _ := NewKriteriaRepository(db).GetAll()
+	assert.Len(t, kriterias, 3)
+	jabatans, _ := NewJabatanRepository(db).GetAll()
+	assert.Len(t, jabatans, 2)
+	tables, _ := NewGapWeightTableRepository(db).GetAll()
+	assert.Len(t, tables, 2)
+
+	targetProfiles, _ := NewTargetProfileRepository(db).GetByJabatanID(manager.ID)
+	assert.Len(t, targetProfiles, 3)
+	updated, _ := NewJabatanRepository(db).GetByID(manager.ID)
+	assert.Equal(t, 2, updated.JumlahLowongan)
+	if assert.NotNil(t, updated.GapWeightTableID) {
+		table, _ := NewGapWeightTableRepository(db).GetByID(*updated.GapWeightTableID)
+		assert.Equal(t, "Ketat", table.Nama)
+		assert.Len(t, table.Entries, 2)
+	}
+
+	// Each change is audited as made by the user; the unchanged K9 is not
+	entries, err := NewAuditLogRepository(db).GetAll(AuditLogFilter{UserID: user.ID})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 8)
+	entries, _ = NewAuditLogRepository(db).GetAll(AuditLogFilter{Entity: "gap_weight_table", UserID: user.ID})
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "update", entries[0].Action)
+		assert.Contains(t, entries[0].Changes, "entries")
+	}
+}
+
+func TestModelConfigRepository_Import_Replace(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewModelConfigRepository(db)
+	manager := seedModelConfig(t, db)
+
+	deleted := dto.ConfigImportSummary{
+		GapWeightTables: dto.ConfigImportCounts{Updated: 1, Deleted: 1},
+		Aspek:           dto.ConfigImportCounts{Created: 1, Updated: 1, Deleted: 1},
+		Kriteria:        dto.ConfigImportCounts{Created: 1, Updated: 1, Deleted: 1},
+		Jabatan:         dto.ConfigImportCounts{Updated: 1, Deleted: 1},
+		TargetProfiles:  dto.ConfigImportCounts{Created: 1, Updated: 1, Deleted: 1},
+	}
+
+	// A dry run counts the same changes and saves none
+	summary, err := repo.Import(newTestModelConfig(), true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, deleted, summary)
+	jabatans, _ := NewJabatanRepository(db).GetAll()
+	assert.Len(t, jabatans, 2)
+	aspeks, _ := NewAspekRepository(db).GetAll()
+	assert.Len(t, aspeks, 2)
+
+	summary, err = repo.Import(newTestModelConfig(), true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, deleted, summary)
+
+	// Records missing from the configuration are soft-deleted
+	aspeks, _ = NewAspekRepository(db).GetAll()
+	assert.Len(t, aspeks, 2)
+	kriterias, _ := NewKriteriaRepository(db).GetAll()
+	assert.Len(t, kriterias, 2)
+	jabatans, _ = NewJabatanRepository(db).GetAll()
+	assert.Len(t, jabatans, 1)
+	assert.Equal(t, "Manager", jabatans[0].Nama)
+	tables, _ := NewGapWeightTableRepository(db).GetAll()
+	assert.Len(t, tables, 1)
+	targetProfiles, _ := NewTargetProfileRepository(db).GetByJabatanID(manager.ID)
+	assert.Len(t, targetProfiles, 2)
+
+	var staf models.Jabatan
+	assert.NoError(t, db.Unscoped().Where("nama = ?", "Staf").First(&staf).Error)
+	assert.True(t, staf.DeletedAt.Valid)
+}
@@ -14,8 +14,15 @@ func NewNilaiTenagaKerjaRepository(db *gorm.DB) *NilaiTenagaKerjaRepository {
 	return &NilaiTenagaKerjaRepository{db: db}
 }
 
+// WithActor returns the repository recording its changes for the user
+func (r *NilaiTenagaKerjaRepository) WithActor(userID *uint) *NilaiTenagaKerjaRepository {
+	return &NilaiTenagaKerjaRepository{db: withActor(r.db, userID)}
+}
+
 func (r *NilaiTenagaKerjaRepository) Create(ntk *models.NilaiTenagaKerja) error {
-	return r.db.Create(ntk).Error
+	return auditedCreate(r.db, "nilai_tenaga_kerja", ntk, func(tx *gorm.DB) error {
+		return tx.Create(ntk).Error
+	})
 }
 
 func (r *NilaiTenagaKerjaRepository) GetAll() ([]models.NilaiTenagaKerja, error) {
@@ -42,6 +49,26 @@ func (r *NilaiTenagaKerjaRepository) GetByTenagaKerjaID(tenagaKerjaID uint) ([]m
 	return list, nil
 }
 
+// GetTenagaKerjaIDsByKriteriaIDs returns, in ID order, the tenaga kerja
+// having a nilai for any of the kriteria
+func (r *NilaiTenagaKerjaRepository) GetTenagaKerjaIDsByKriteriaIDs(kriteriaIDs []uint) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.NilaiTenagaKerja{}).Where("kriteria_id IN ?", kriteriaIDs).Distinct().Order("tenaga_kerja_id").Pluck("tenaga_kerja_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetByTenagaKerjaIDsAndKriteriaIDs returns the nilai of the tenaga kerja
+// for the given kriteria in one query
+func (r *NilaiTenagaKerjaRepository) GetByTenagaKerjaIDsAndKriteriaIDs(tenagaKerjaIDs, kriteriaIDs []uint) ([]models.NilaiTenagaKerja, error) {
+	var list []models.NilaiTenagaKerja
+	if err := r.db.Where("tenaga_kerja_id IN ? AND kriteria_id IN ?", tenagaKerjaIDs, kriteriaIDs).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 func (r *NilaiTenagaKerjaRepository) GetByKriteriaID(kriteriaID uint) ([]models.NilaiTenagaKerja, error) {
 	var list []models.NilaiTenagaKerja
 	if err := r.db.Where("kriteria_id = ?", kriteriaID).Find(&list).Error; err != nil {
@@ -51,10 +78,13 @@ func (r *NilaiTenagaKerjaRepository) GetByKriteriaID(kriteriaID uint) ([]models.
 }
 
 func (r *NilaiTenagaKerjaRepository) Update(id uint, ntk *models.NilaiTenagaKerja) error {
-	return r.db.Model(&models.NilaiTenagaKerja{}).Where("id = ?", id).Updates(ntk).Error
+	return auditedChange(r.db, "nilai_tenaga_kerja", &models.NilaiTenagaKerja{}, id, "update", func(tx *gorm.DB) error {
+		return tx.Model(&models.NilaiTenagaKerja{}).Where("id = ?", id).Updates(ntk).Error
+	})
 }
 
 func (r *NilaiTenagaKerjaRepository) Delete(id uint) error {
-	return r.db.Delete(&models.NilaiTenagaKerja{}, id).Error
+	return auditedChange(r.db, "nilai_tenaga_kerja", &models.NilaiTenagaKerja{}, id, "delete", func(tx *gorm.DB) error {
+		return tx.Delete(&models.NilaiTenagaKerja{}, id).Error
+	})
 }
-
@@ -6,6 +6,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// rankOrder orders the results of one jabatan by their stored rank, falling
+// back to the score for results stored before ranks were recorded
+const rankOrder = "ranking ASC, incomplete ASC, total_score DESC, id ASC"
+
 type ProfileMatchResultRepository struct {
 	db *gorm.DB
 }
@@ -20,15 +24,7 @@ func (r *ProfileMatchResultRepository) Create(pmr *models.ProfileMatchResult) er
 
 func (r *ProfileMatchResultRepository) GetAll() ([]models.ProfileMatchResult, error) {
 	var list []models.ProfileMatchResult
-	if err := r.db.Preload("TenagaKerja").Preload("Jabatan").Order("total_score DESC").Find(&list).Error; err != nil {
-		return nil, err
-	}
-	return list, nil
-}
-
-func (r *ProfileMatchResultRepository) GetAllWithRelations() ([]models.ProfileMatchResult, error) {
-	var list []models.ProfileMatchResult
-	if err := r.db.Preload("TenagaKerja").Preload("Jabatan").Order("total_score DESC").Find(&list).Error; err != nil {
+	if err := r.db.Preload("TenagaKerja").Preload("Jabatan").Order("incomplete ASC, total_score DESC").Find(&list).Error; err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -36,7 +32,9 @@ func (r *ProfileMatchResultRepository) GetAllWithRelations() ([]models.ProfileMa
 
 func (r *ProfileMatchResultRepository) GetByID(id uint) (*models.ProfileMatchResult, error) {
 	var pmr models.ProfileMatchResult
-	if err := r.db.Preload("TenagaKerja").Preload("Jabatan").First(&pmr, id).Error; err != nil {
+	if err := r.db.Preload("TenagaKerja").Preload("Jabatan").Preload("Aspek", func(db *gorm.DB) *gorm.DB {
+		return db.Order("aspek_id ASC")
+	}).Preload("Aspek.Kriteria").First(&pmr, id).Error; err != nil {
 		return nil, err
 	}
 	return &pmr, nil
@@ -44,7 +42,7 @@ func (r *ProfileMatchResultRepository) GetByID(id uint) (*models.ProfileMatchRes
 
 func (r *ProfileMatchResultRepository) GetByJabatanID(jabatanID uint) ([]models.ProfileMatchResult, error) {
 	var list []models.ProfileMatchResult
-	if err := r.db.Where("jabatan_id = ?", jabatanID).Preload("TenagaKerja").Preload("Jabatan").Order("total_score DESC").Find(&list).Error; err != nil {
+	if err := r.db.Where("jabatan_id = ?", jabatanID).Preload("TenagaKerja").Preload("Jabatan").Order(rankOrder).Find(&list).Error; err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -58,10 +56,72 @@ func (r *ProfileMatchResultRepository) GetByTenagaKerjaID(tenagaKerjaID uint) ([
 	return list, nil
 }
 
-func (r *ProfileMatchResultRepository) CreateBatch(results []models.ProfileMatchResult) error {
-	return r.db.Create(&results).Error
+// GetByRunID returns the results of a calculation run in rank order
+func (r *ProfileMatchResultRepository) GetByRunID(runID uint) ([]models.ProfileMatchResult, error) {
+	var list []models.ProfileMatchResult
+	if err := r.db.Where("run_id = ?", runID).Preload("TenagaKerja").Preload("Jabatan").Order(rankOrder).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetIneligibleByRunIDs returns the tenaga kerja the runs knocked out of
+// their rankings, by tenaga kerja; a non-zero jabatanID limits them to that
+// jabatan
+func (r *ProfileMatchResultRepository) GetIneligibleByRunIDs(runIDs []uint, jabatanID uint) ([]models.IneligibleResult, error) {
+	var list []models.IneligibleResult
+	query := r.db.Where("run_id IN ?", runIDs)
+	if jabatanID != 0 {
+		query = query.Where("jabatan_id = ?", jabatanID)
+	}
+	if err := query.Preload("TenagaKerja").Order("tenaga_kerja_id ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetByRunIDWithSnapshot returns the results of a calculation run in rank
+// order with their per-aspek and per-kriteria breakdown
+func (r *ProfileMatchResultRepository) GetByRunIDWithSnapshot(runID uint) ([]models.ProfileMatchResult, error) {
+	var list []models.ProfileMatchResult
+	if err := r.db.Where("run_id = ?", runID).Preload("TenagaKerja").Preload("Aspek", func(db *gorm.DB) *gorm.DB {
+		return db.Order("aspek_id ASC")
+	}).Preload("Aspek.Kriteria").Order(rankOrder).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetCurrent returns the results of the given current runs, plus results
+// stored before runs were recorded for jabatan without any run. A non-zero
+// jabatanID limits them to that jabatan.
+func (r *ProfileMatchResultRepository) GetCurrent(runIDs []uint, jabatanID uint) ([]models.ProfileMatchResult, error) {
+	var list []models.ProfileMatchResult
+	if err := r.currentQuery(runIDs, jabatanID).Preload("Jabatan").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetCurrentWithSnapshot is GetCurrent with the per-aspek and per-kriteria
+// breakdown of each result
+func (r *ProfileMatchResultRepository) GetCurrentWithSnapshot(runIDs []uint, jabatanID uint) ([]models.ProfileMatchResult, error) {
+	var list []models.ProfileMatchResult
+	if err := r.currentQuery(runIDs, jabatanID).Preload("Aspek", func(db *gorm.DB) *gorm.DB {
+		return db.Order("aspek_id ASC")
+	}).Preload("Aspek.Kriteria").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
 }
 
-func (r *ProfileMatchResultRepository) DeleteByJabatanID(jabatanID uint) error {
-	return r.db.Where("jabatan_id = ?", jabatanID).Delete(&models.ProfileMatchResult{}).Error
+func (r *ProfileMatchResultRepository) currentQuery(runIDs []uint, jabatanID uint) *gorm.DB {
+	jabatanWithRuns := r.db.Model(&models.CalculationRun{}).Select("jabatan_id")
+	query := r.db.Where("run_id IN ? OR (run_id IS NULL AND jabatan_id NOT IN (?))", runIDs, jabatanWithRuns)
+	order := "incomplete ASC, total_score DESC"
+	if jabatanID != 0 {
+		query = query.Where("jabatan_id = ?", jabatanID)
+		order = rankOrder
+	}
+	return query.Preload("TenagaKerja").Order(order)
 }
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ResultStatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewResultStatusHistoryRepository(db *gorm.DB) *ResultStatusHistoryRepository {
+	return &ResultStatusHistoryRepository{db: db}
+}
+
+// WithActor returns the repository recording its changes for the user
+func (r *ResultStatusHistoryRepository) WithActor(userID *uint) *ResultStatusHistoryRepository {
+	return &ResultStatusHistoryRepository{db: withActor(r.db, userID)}
+}
+
+// GetByPair returns the status history of a tenaga kerja for a jabatan,
+// newest first
+func (r *ResultStatusHistoryRepository) GetByPair(jabatanID, tenagaKerjaID uint) ([]models.ResultStatusHistory, error) {
+	var list []models.ResultStatusHistory
+	if err := r.db.Preload("User").Where("jabatan_id = ? AND tenaga_kerja_id = ?", jabatanID, tenagaKerjaID).
+		Order("id DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetLatestManual returns, per tenaga kerja, the latest status given by a
+// user for the jabatan
+func (r *ResultStatusHistoryRepository) GetLatestManual(jabatanID uint) (map[uint]models.ResultStatusHistory, error) {
+	latest := r.db.Model(&models.ResultStatusHistory{}).Select("MAX(id)").
+		Where("jabatan_id = ? AND auto = ?", jabatanID, false).Group("tenaga_kerja_id")
+	var list []models.ResultStatusHistory
+	if err := r.db.Where("id IN (?)", latest).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	byTenagaKerja := make(map[uint]models.ResultStatusHistory, len(list))
+	for _, h := range list {
+		byTenagaKerja[h.TenagaKerjaID] = h
+	}
+	return byTenagaKerja, nil
+}
+
+// ChangeStatus sets the status of the entry's result and records the entry
+// and the audit entry of the result in one transaction
+func (r *ResultStatusHistoryRepository) ChangeStatus(entry *models.ResultStatusHistory) error {
+	return auditedChange(r.db, "profile_match_result", &models.ProfileMatchResult{}, entry.ProfileMatchResultID, "update", func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ProfileMatchResult{}).Where("id = ?", entry.ProfileMatchResultID).
+			Update("status", entry.Status).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
@@ -14,8 +14,15 @@ func NewTargetProfileRepository(db *gorm.DB) *TargetProfileRepository {
 	return &TargetProfileRepository{db: db}
 }
 
+// WithActor returns the repository recording its changes for the user
+func (r *TargetProfileRepository) WithActor(userID *uint) *TargetProfileRepository {
+	return &TargetProfileRepository{db: withActor(r.db, userID)}
+}
+
 func (r *TargetProfileRepository) Create(tp *models.TargetProfile) error {
-	return r.db.Create(tp).Error
+	return auditedCreate(r.db, "target_profile", tp, func(tx *gorm.DB) error {
+		return tx.Create(tp).Error
+	})
 }
 
 func (r *TargetProfileRepository) GetAll() ([]models.TargetProfile, error) {
@@ -43,10 +50,24 @@ func (r *TargetProfileRepository) GetByJabatanID(jabatanID uint) ([]models.Targe
 }
 
 func (r *TargetProfileRepository) Update(id uint, tp *models.TargetProfile) error {
-	return r.db.Model(&models.TargetProfile{}).Where("id = ?", id).Updates(tp).Error
+	return auditedChange(r.db, "target_profile", &models.TargetProfile{}, id, "update", func(tx *gorm.DB) error {
+		return tx.Model(&models.TargetProfile{}).Where("id = ?", id).Updates(tp).Error
+	})
 }
 
-func (r *TargetProfileRepository) Delete(id uint) error {
-	return r.db.Delete(&models.TargetProfile{}, id).Error
+// UpdateWithColumns sets the columns, which may hold the nil and zero values
+// Updates skips, and then updates the target profile, in one transaction.
+func (r *TargetProfileRepository) UpdateWithColumns(id uint, tp *models.TargetProfile, columns map[string]interface{}) error {
+	return auditedChange(r.db, "target_profile", &models.TargetProfile{}, id, "update", func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TargetProfile{}).Where("id = ?", id).Updates(columns).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.TargetProfile{}).Where("id = ?", id).Updates(tp).Error
+	})
 }
 
+func (r *TargetProfileRepository) Delete(id uint) error {
+	return auditedChange(r.db, "target_profile", &models.TargetProfile{}, id, "delete", func(tx *gorm.DB) error {
+		return tx.Delete(&models.TargetProfile{}, id).Error
+	})
+}
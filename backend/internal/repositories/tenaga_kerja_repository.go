@@ -1,11 +1,18 @@
 package repositories
 
 import (
+	"errors"
+
 	"backend/internal/models"
 
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
 
+// ErrDuplicateNIK is returned by CreateBatch when a NIK in the batch was
+// registered in the meantime
+var ErrDuplicateNIK = errors.New("duplicate NIK")
+
 type TenagaKerjaRepository struct {
 	db *gorm.DB
 }
@@ -14,8 +21,15 @@ func NewTenagaKerjaRepository(db *gorm.DB) *TenagaKerjaRepository {
 	return &TenagaKerjaRepository{db: db}
 }
 
+// WithActor returns the repository recording its changes for the user
+func (r *TenagaKerjaRepository) WithActor(userID *uint) *TenagaKerjaRepository {
+	return &TenagaKerjaRepository{db: withActor(r.db, userID)}
+}
+
 func (r *TenagaKerjaRepository) Create(tk *models.TenagaKerja) error {
-	return r.db.Create(tk).Error
+	return auditedCreate(r.db, "tenaga_kerja", tk, func(tx *gorm.DB) error {
+		return tx.Create(tk).Error
+	})
 }
 
 func (r *TenagaKerjaRepository) GetAll() ([]models.TenagaKerja, error) {
@@ -43,11 +57,15 @@ func (r *TenagaKerjaRepository) GetByIDs(ids []uint) ([]models.TenagaKerja, erro
 }
 
 func (r *TenagaKerjaRepository) Update(id uint, tk *models.TenagaKerja) error {
-	return r.db.Model(&models.TenagaKerja{}).Where("id = ?", id).Updates(tk).Error
+	return auditedChange(r.db, "tenaga_kerja", &models.TenagaKerja{}, id, "update", func(tx *gorm.DB) error {
+		return tx.Model(&models.TenagaKerja{}).Where("id = ?", id).Updates(tk).Error
+	})
 }
 
 func (r *TenagaKerjaRepository) Delete(id uint) error {
-	return r.db.Delete(&models.TenagaKerja{}, id).Error
+	return auditedChange(r.db, "tenaga_kerja", &models.TenagaKerja{}, id, "delete", func(tx *gorm.DB) error {
+		return tx.Delete(&models.TenagaKerja{}, id).Error
+	})
 }
 
 func (r *TenagaKerjaRepository) ExistsByNIK(nik string) (bool, error) {
@@ -56,3 +74,54 @@ func (r *TenagaKerjaRepository) ExistsByNIK(nik string) (bool, error) {
 	return count > 0, err
 }
 
+// GetExistingNIKs returns which of the given NIK are already registered,
+// including by deleted tenaga kerja as they keep their NIK unique
+func (r *TenagaKerjaRepository) GetExistingNIKs(niks []string) (map[string]bool, error) {
+	var found []string
+	if err := r.db.Unscoped().Model(&models.TenagaKerja{}).Where("nik IN ?", niks).Pluck("nik", &found).Error; err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(found))
+	for _, nik := range found {
+		existing[nik] = true
+	}
+	return existing, nil
+}
+
+// CreateBatch inserts the tenaga kerja with their audit entries in one
+// transaction, filling in their IDs. Nothing is inserted when a NIK is
+// taken; the error is then ErrDuplicateNIK.
+func (r *TenagaKerjaRepository) CreateBatch(list []models.TenagaKerja) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(list, 500).Error; err != nil {
+			return err
+		}
+		entries := make([]models.AuditLog, 0, len(list))
+		for i := range list {
+			changes, err := auditChanges(nil, &list[i])
+			if err != nil {
+				return err
+			}
+			entries = append(entries, models.AuditLog{
+				UserID:   auditActor(tx),
+				Entity:   "tenaga_kerja",
+				EntityID: list[i].ID,
+				Action:   "create",
+				Changes:  changes,
+			})
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Omit("User").CreateInBatches(entries, 500).Error
+	})
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+		return ErrDuplicateNIK
+	}
+	return err
+}
+
+// mysqlErrDuplicateEntry is the MySQL error for a unique key violation; NIK
+// is the only unique key of tenaga kerja
+const mysqlErrDuplicateEntry = 1062
@@ -68,3 +68,22 @@ func TestTenagaKerjaRepository_GetByIDs(t *testing.T) {
 	assert.Len(t, tenagaKerjas, 2)
 }
 
+
+func TestTenagaKerjaRepository_CreateBatch_DuplicateNIK(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewTenagaKerjaRepository(db)
+
+	registered := &models.TenagaKerja{NIK: "TK001", Nama: "John Doe"}
+	repo.Create(registered)
+	repo.Delete(registered.ID)
+
+	// The deleted tenaga kerja keeps its NIK
+	existing, err := repo.GetExistingNIKs([]string{"TK001", "TK002"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"TK001": true}, existing)
+
+	err = repo.CreateBatch([]models.TenagaKerja{{NIK: "TK002", Nama: "Jane Doe"}, {NIK: "TK001", Nama: "Budi"}})
+	assert.ErrorIs(t, err, ErrDuplicateNIK)
+	exists, _ := repo.ExistsByNIK("TK002")
+	assert.False(t, exists)
+}
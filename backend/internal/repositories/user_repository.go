@@ -14,6 +14,11 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// WithActor returns the repository recording its changes for the user
+func (r *UserRepository) WithActor(userID *uint) *UserRepository {
+	return &UserRepository{db: withActor(r.db, userID)}
+}
+
 func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	var u models.User
 	if err := r.db.Where("email = ?", email).First(&u).Error; err != nil {
@@ -39,15 +44,21 @@ func (r *UserRepository) GetByID(id uint) (*models.User, error) {
 }
 
 func (r *UserRepository) Create(u *models.User) error {
-	return r.db.Create(u).Error
+	return auditedCreate(r.db, "user", u, func(tx *gorm.DB) error {
+		return tx.Create(u).Error
+	})
 }
 
 func (r *UserRepository) Update(id uint, u *models.User) error {
-	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(u).Error
+	return auditedChange(r.db, "user", &models.User{}, id, "update", func(tx *gorm.DB) error {
+		return tx.Model(&models.User{}).Where("id = ?", id).Updates(u).Error
+	})
 }
 
 func (r *UserRepository) Delete(id uint) error {
-	return r.db.Delete(&models.User{}, id).Error
+	return auditedChange(r.db, "user", &models.User{}, id, "delete", func(tx *gorm.DB) error {
+		return tx.Delete(&models.User{}, id).Error
+	})
 }
 
 func (r *UserRepository) ExistsByEmail(email string) (bool, error) {
@@ -17,6 +17,13 @@ func NewAspekService(aspekRepo *repositories.AspekRepository) *AspekService {
 	return &AspekService{aspekRepo: aspekRepo}
 }
 
+// WithActor returns the service recording the changes it makes for the user
+func (s *AspekService) WithActor(userID *uint) *AspekService {
+	scoped := *s
+	scoped.aspekRepo = s.aspekRepo.WithActor(userID)
+	return &scoped
+}
+
 func (s *AspekService) GetAll() ([]models.Aspek, error) {
 	return s.aspekRepo.GetAll()
 }
@@ -36,10 +43,21 @@ func (s *AspekService) Create(aspek *models.Aspek) error {
 	if aspek.Nama == "" {
 		return errors.New("nama aspek tidak boleh kosong")
 	}
+	if err := validateFactorRatio(aspek.CoreFactorPersen, aspek.SecondaryFactorPersen); err != nil {
+		return err
+	}
 	return s.aspekRepo.Create(aspek)
 }
 
-func (s *AspekService) Update(id uint, aspek *models.Aspek) error {
+// AspekUpdateOptions are the changes to an aspek its zero values cannot
+// express
+type AspekUpdateOptions struct {
+	// ResetFactorRatio removes the aspek's core/secondary ratio override
+	ResetFactorRatio bool
+}
+
+// Update validates and updates the aspek in one transaction
+func (s *AspekService) Update(id uint, aspek *models.Aspek, opts AspekUpdateOptions) error {
 	// Check if aspek exists
 	_, err := s.aspekRepo.GetByID(id)
 	if err != nil {
@@ -49,6 +67,16 @@ func (s *AspekService) Update(id uint, aspek *models.Aspek) error {
 		return err
 	}
 
+	if err := validateFactorRatio(aspek.CoreFactorPersen, aspek.SecondaryFactorPersen); err != nil {
+		return err
+	}
+
+	if opts.ResetFactorRatio {
+		return s.aspekRepo.UpdateWithColumns(id, aspek, map[string]interface{}{
+			"core_factor_persen":      nil,
+			"secondary_factor_persen": nil,
+		})
+	}
 	return s.aspekRepo.Update(id, aspek)
 }
 
@@ -64,4 +92,3 @@ func (s *AspekService) Delete(id uint) error {
 
 	return s.aspekRepo.Delete(id)
 }
-
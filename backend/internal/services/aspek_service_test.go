@@ -68,7 +68,7 @@ func TestAspekService_Update(t *testing.T) {
 	service.Create(aspek)
 
 	aspek.Nama = "Kompetensi Teknis"
-	err := service.Update(aspek.ID, aspek)
+	err := service.Update(aspek.ID, aspek, AspekUpdateOptions{})
 	assert.NoError(t, err)
 
 	updated, _ := service.GetByID(aspek.ID)
@@ -92,3 +92,25 @@ func TestAspekService_Delete(t *testing.T) {
 	_, err = service.GetByID(aspek.ID)
 	assert.Error(t, err)
 }
+
+func TestAspekService_Update_ResetFactorRatio(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := NewAspekService(repositories.NewAspekRepository(db))
+
+	core, secondary := 70.0, 30.0
+	aspek := &models.Aspek{Nama: "Kompetensi", Persentase: 50, CoreFactorPersen: &core, SecondaryFactorPersen: &secondary}
+	assert.NoError(t, service.Create(aspek))
+
+	// A rejected update keeps the ratio
+	invalid := 80.0
+	err := service.Update(aspek.ID, &models.Aspek{CoreFactorPersen: &invalid}, AspekUpdateOptions{ResetFactorRatio: true})
+	assert.Error(t, err)
+	updated, _ := service.GetByID(aspek.ID)
+	assert.Equal(t, &core, updated.CoreFactorPersen)
+
+	assert.NoError(t, service.Update(aspek.ID, &models.Aspek{Nama: "Kompetensi Teknis"}, AspekUpdateOptions{ResetFactorRatio: true}))
+	updated, _ = service.GetByID(aspek.ID)
+	assert.Nil(t, updated.CoreFactorPersen)
+	assert.Nil(t, updated.SecondaryFactorPersen)
+	assert.Equal(t, "Kompetensi Teknis", updated.Nama)
+}
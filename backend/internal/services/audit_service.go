@@ -0,0 +1,27 @@
+package services
+
+import (
+	"errors"
+
+	"backend/internal/models"
+	"backend/internal/repositories"
+)
+
+// AuditService reads the log of who created, updated or deleted which
+// record, with the values of the fields that changed. The repositories write
+// the entries with the changes they record.
+type AuditService struct {
+	auditLogRepo *repositories.AuditLogRepository
+}
+
+func NewAuditService(auditLogRepo *repositories.AuditLogRepository) *AuditService {
+	return &AuditService{auditLogRepo: auditLogRepo}
+}
+
+// GetAll returns the audit log entries matching the filter, newest first
+func (s *AuditService) GetAll(filter repositories.AuditLogFilter) ([]models.AuditLog, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, errors.New("invalid date range")
+	}
+	return s.auditLogRepo.GetAll(filter)
+}
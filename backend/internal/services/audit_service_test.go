@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditService_GetAll(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := NewAuditService(repositories.NewAuditLogRepository(db))
+	user := &models.User{Email: "auditor@example.com", Password: "x", Nama: "Auditor"}
+	assert.NoError(t, repositories.NewUserRepository(db).Create(user))
+
+	aspekService := NewAspekService(repositories.NewAspekRepository(db)).WithActor(&user.ID)
+	aspek := &models.Aspek{Nama: "Kompetensi", Persentase: 50}
+	assert.NoError(t, repositories.NewAspekRepository(db).Create(aspek))
+	assert.NoError(t, aspekService.Update(aspek.ID, &models.Aspek{Nama: "Kompetensi", Persentase: 60}, AspekUpdateOptions{}))
+	assert.NoError(t, repositories.NewJabatanRepository(db).Create(&models.Jabatan{Nama: "Manager"}))
+
+	entries, err := service.GetAll(repositories.AuditLogFilter{Entity: "aspek", UserID: user.ID})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "update", entries[0].Action)
+		assert.Equal(t, models.AuditChange{Old: float64(50), New: float64(60)}, entries[0].Changes["persentase"])
+		assert.Equal(t, "Auditor", entries[0].User.Nama)
+	}
+
+	from := time.Now().Add(-time.Hour)
+	to := from.Add(2 * time.Hour)
+	entries, err = service.GetAll(repositories.AuditLogFilter{From: &from, To: &to})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, "jabatan", entries[0].Entity) // Newest first
+
+	_, err = service.GetAll(repositories.AuditLogFilter{From: &to, To: &from})
+	assert.EqualError(t, err, "invalid date range")
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/profilematching"
+
+	"gorm.io/gorm"
+)
+
+// CalculationJobQueueSize is the number of jobs that can wait for a worker
+const CalculationJobQueueSize = 100
+
+const (
+	// CalculationJobHeartbeatInterval is how often an instance marks its
+	// unfinished jobs as still being worked on
+	CalculationJobHeartbeatInterval = 30 * time.Second
+	// CalculationJobStaleAfter is how long an unfinished job may go without
+	// a heartbeat before it is failed as abandoned
+	CalculationJobStaleAfter = 2 * time.Minute
+)
+
+// CalculationJobService runs calculations in the background on a bounded
+// pool of workers inside the API process. Jobs are queued in memory and
+// tracked in the calculation_jobs table, owned by the instance that queued
+// them; several instances can share the table.
+type CalculationJobService struct {
+	calculationJobRepo     *repositories.CalculationJobRepository
+	jabatanRepo            *repositories.JabatanRepository
+	profileMatchingService *ProfileMatchingService
+	queue                  chan uint
+	instanceID             string
+	heartbeatInterval      time.Duration
+	staleAfter             time.Duration
+}
+
+func NewCalculationJobService(
+	calculationJobRepo *repositories.CalculationJobRepository,
+	jabatanRepo *repositories.JabatanRepository,
+	profileMatchingService *ProfileMatchingService,
+) *CalculationJobService {
+	return &CalculationJobService{
+		calculationJobRepo:     calculationJobRepo,
+		jabatanRepo:            jabatanRepo,
+		profileMatchingService: profileMatchingService,
+		queue:                  make(chan uint, CalculationJobQueueSize),
+		instanceID:             newInstanceID(),
+		heartbeatInterval:      CalculationJobHeartbeatInterval,
+		staleAfter:             CalculationJobStaleAfter,
+	}
+}
+
+// newInstanceID identifies this process among the API instances sharing the
+// calculation_jobs table
+func newInstanceID() string {
+	hostname, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%x", hostname, os.Getpid(), suffix)
+}
+
+// Start fails the jobs abandoned by stopped instances, then starts the
+// workers and the heartbeat of this instance's jobs. Jobs of other running
+// instances are left alone.
+func (s *CalculationJobService) Start(workers int) error {
+	if workers < 1 {
+		return errors.New("at least one calculation worker is required")
+	}
+	if err := s.failStale(); err != nil {
+		return err
+	}
+	for i := 0; i < workers; i++ {
+		go s.work()
+	}
+	go s.heartbeat()
+	return nil
+}
+
+func (s *CalculationJobService) failStale() error {
+	return s.calculationJobRepo.FailStale(time.Now().Add(-s.staleAfter), "interrupted by server restart")
+}
+
+// heartbeat keeps this instance's jobs from going stale and fails those
+// abandoned by instances that stopped while this one runs
+func (s *CalculationJobService) heartbeat() {
+	ticker := time.NewTicker(s.heartbeatInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.calculationJobRepo.Heartbeat(s.instanceID); err != nil {
+			log.Printf("calculation job heartbeat: %v", err)
+		}
+		if err := s.failStale(); err != nil {
+			log.Printf("calculation job heartbeat: %v", err)
+		}
+	}
+}
+
+type CalculationJobRequest struct {
+	// JabatanIDs are calculated in order; empty means every jabatan
+	JabatanIDs    []uint
+	GapMode       string
+	RoundingRule  string
+	MissingPolicy string
+	MinimumNilai  *float64
+	Method        string
+	RankingStyle  string
+	TieBreakers   []string
+	UserID        *uint
+}
+
+// Submit validates the request and queues a job for it
+func (s *CalculationJobService) Submit(req CalculationJobRequest) (*models.CalculationJob, error) {
+	if _, err := profilematching.NewGapMode(req.GapMode, req.RoundingRule); err != nil {
+		return nil, err
+	}
+	if _, err := profilematching.NewMissingPolicy(req.MissingPolicy, req.MinimumNilai); err != nil {
+		return nil, err
+	}
+	if _, err := profilematching.ValidateMethod(req.Method); err != nil {
+		return nil, err
+	}
+	if _, err := profilematching.NewRankingRule(req.RankingStyle, req.TieBreakers); err != nil {
+		return nil, err
+	}
+
+	jabatanIDs := req.JabatanIDs
+	if len(jabatanIDs) == 0 {
+		jabatans, err := s.jabatanRepo.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, j := range jabatans {
+			jabatanIDs = append(jabatanIDs, j.ID)
+		}
+	} else {
+		for _, id := range jabatanIDs {
+			if _, err := s.jabatanRepo.GetByID(id); err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return nil, errors.New("jabatan not found")
+				}
+				return nil, err
+			}
+		}
+	}
+	if len(jabatanIDs) == 0 {
+		return nil, errors.New("no jabatan to calculate")
+	}
+
+	now := time.Now()
+	job := &models.CalculationJob{
+		UserID: req.UserID,
+		Request: models.CalculationJobRequest{
+			JabatanIDs:    jabatanIDs,
+			GapMode:       req.GapMode,
+			RoundingRule:  req.RoundingRule,
+			MissingPolicy: req.MissingPolicy,
+			MinimumNilai:  req.MinimumNilai,
+			Method:        req.Method,
+			RankingStyle:  req.RankingStyle,
+			TieBreakers:   req.TieBreakers,
+		},
+		Status:      "queued",
+		Total:       len(jabatanIDs),
+		Owner:       s.instanceID,
+		HeartbeatAt: &now,
+	}
+	if err := s.calculationJobRepo.WithActor(req.UserID).Create(job); err != nil {
+		return nil, errors.New("could not create calculation job")
+	}
+
+	select {
+	case s.queue <- job.ID:
+	default:
+		job.Errors = []string{"calculation job queue is full"}
+		s.calculationJobRepo.Finish(job, "failed")
+		return nil, errors.New("calculation job queue is full")
+	}
+	return job, nil
+}
+
+func (s *CalculationJobService) GetByID(id uint) (*models.CalculationJob, error) {
+	job, err := s.calculationJobRepo.GetByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("calculation job not found")
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// Cancel cancels a queued job at once; a running job stops before its next
+// jabatan, keeping the runs already recorded. userID is the user cancelling
+// the job.
+func (s *CalculationJobService) Cancel(id uint, userID *uint) (*models.CalculationJob, error) {
+	if _, err := s.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	calculationJobRepo := s.calculationJobRepo.WithActor(userID)
+	cancelled, err := calculationJobRepo.CancelQueued(id)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		cancelled, err = calculationJobRepo.RequestCancel(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !cancelled {
+		return nil, errors.New("calculation job already finished")
+	}
+	return s.GetByID(id)
+}
+
+func (s *CalculationJobService) work() {
+	for id := range s.queue {
+		if err := s.run(id); err != nil {
+			log.Printf("calculation job %d: %v", id, err)
+		}
+	}
+}
+
+// run calculates each jabatan of the job, recording progress after each one.
+// A jabatan that fails is listed in the job's errors and the job goes on.
+func (s *CalculationJobService) run(id uint) error {
+	started, err := s.calculationJobRepo.Start(id)
+	if err != nil || !started {
+		return err // cancelled while queued
+	}
+	job, err := s.calculationJobRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Fail the job rather than leave it running when tracking it fails
+	fail := func(err error) error {
+		job.Errors = append(job.Errors, err.Error())
+		s.calculationJobRepo.Finish(job, "failed")
+		return err
+	}
+
+	for _, jabatanID := range job.Request.JabatanIDs {
+		cancelRequested, err := s.calculationJobRepo.CancelRequested(id)
+		if err != nil {
+			return fail(err)
+		}
+		if cancelRequested {
+			return s.calculationJobRepo.Finish(job, "cancelled")
+		}
+
+		run, _, err := s.profileMatchingService.calculateRun(CalculationRequest{
+			JabatanID:     jabatanID,
+			GapMode:       job.Request.GapMode,
+			RoundingRule:  job.Request.RoundingRule,
+			MissingPolicy: job.Request.MissingPolicy,
+			MinimumNilai:  job.Request.MinimumNilai,
+			Method:        job.Request.Method,
+			RankingStyle:  job.Request.RankingStyle,
+			TieBreakers:   job.Request.TieBreakers,
+			UserID:        job.UserID,
+		})
+		if err != nil {
+			job.Errors = append(job.Errors, fmt.Sprintf("jabatan %d: %s", jabatanID, err.Error()))
+		} else {
+			job.RunIDs = append(job.RunIDs, run.ID)
+		}
+		job.Processed++
+		running, err := s.calculationJobRepo.UpdateProgress(job)
+		if err != nil {
+			return fail(err)
+		}
+		if !running {
+			return errors.New("job is no longer running")
+		}
+	}
+
+	status := "completed"
+	if len(job.Errors) == job.Total {
+		status = "failed"
+	}
+	return s.calculationJobRepo.Finish(job, status)
+}
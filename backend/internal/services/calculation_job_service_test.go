@@ -0,0 +1,135 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func newTestCalculationJobService(db *gorm.DB) *CalculationJobService {
+	return NewCalculationJobService(
+		repositories.NewCalculationJobRepository(db),
+		repositories.NewJabatanRepository(db),
+		newTestProfileMatchingService(db),
+	)
+}
+
+func TestCalculationJobService_Run(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := newTestCalculationJobService(db)
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+	aspek := &models.Aspek{Nama: "Test Aspek", Deskripsi: "Test", Persentase: 100.0}
+	repositories.NewAspekRepository(db).Create(aspek)
+	kriteria := &models.Kriteria{AspekID: aspek.ID, Kode: "K1", Nama: "Kriteria 1", IsCore: true, Bobot: 1.0}
+	repositories.NewKriteriaRepository(db).Create(kriteria)
+	repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: kriteria.ID, TargetNilai: 4.0})
+
+	// The second jabatan has no target profile and fails on its own
+	other := &models.Jabatan{Nama: "Staff", Deskripsi: "Staff Position"}
+	repositories.NewJabatanRepository(db).Create(other)
+
+	assert.NoError(t, service.Start(1))
+	job, err := service.Submit(CalculationJobRequest{JabatanIDs: []uint{jabatan.ID, other.ID}})
+	assert.NoError(t, err)
+	assert.Equal(t, "queued", job.Status)
+	assert.Equal(t, 2, job.Total)
+
+	assert.Eventually(t, func() bool {
+		job, err = service.GetByID(job.ID)
+		return err == nil && job.FinishedAt != nil
+	}, 5*time.Second, 20*time.Millisecond)
+
+	assert.Equal(t, "completed", job.Status)
+	assert.Equal(t, 2, job.Processed)
+	assert.Len(t, job.RunIDs, 1)
+	assert.Len(t, job.Errors, 1)
+}
+
+func TestCalculationJobService_CancelQueued(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := newTestCalculationJobService(db)
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+
+	// No workers are started, so the job stays queued
+	job, err := service.Submit(CalculationJobRequest{})
+	assert.NoError(t, err)
+
+	job, err = service.Cancel(job.ID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelled", job.Status)
+
+	_, err = service.Cancel(job.ID, nil)
+	assert.EqualError(t, err, "calculation job already finished")
+
+	_, err = service.Submit(CalculationJobRequest{Method: "electre"})
+	assert.EqualError(t, err, "invalid method")
+}
+
+func TestCalculationJobService_TwoInstances(t *testing.T) {
+	db := setupServiceTestDB(t)
+	first := newTestCalculationJobService(db)
+	second := newTestCalculationJobService(db)
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+
+	// A job of an instance that stopped without a heartbeat since
+	calculationJobRepo := repositories.NewCalculationJobRepository(db)
+	staleAt := time.Now().Add(-time.Hour)
+	stale := &models.CalculationJob{Status: "running", Total: 1, Owner: "stopped", HeartbeatAt: &staleAt}
+	assert.NoError(t, calculationJobRepo.Create(stale))
+
+	// The first instance queues a job before its workers start
+	job, err := first.Submit(CalculationJobRequest{JabatanIDs: []uint{jabatan.ID}})
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.instanceID, second.instanceID)
+
+	// Starting the second instance fails only the abandoned job
+	assert.NoError(t, second.Start(1))
+	job, err = first.GetByID(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "queued", job.Status)
+	stale, err = second.GetByID(stale.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "failed", stale.Status)
+
+	// The first instance still runs its own job
+	assert.NoError(t, first.Start(1))
+	assert.Eventually(t, func() bool {
+		job, err = first.GetByID(job.ID)
+		return err == nil && job.FinishedAt != nil
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, first.instanceID, job.Owner)
+	assert.NotEqual(t, "failed", job.Status)
+}
+
+func TestCalculationJobService_Heartbeat(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := newTestCalculationJobService(db)
+	service.heartbeatInterval = 20 * time.Millisecond
+	service.staleAfter = time.Hour
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+
+	job, err := service.Submit(CalculationJobRequest{})
+	assert.NoError(t, err)
+	submittedAt := *job.HeartbeatAt
+
+	// No workers are started, so only the heartbeat touches the queued job
+	go service.heartbeat()
+	assert.Eventually(t, func() bool {
+		job, err = service.GetByID(job.ID)
+		return err == nil && job.HeartbeatAt != nil && job.HeartbeatAt.After(submittedAt)
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, "queued", job.Status)
+}
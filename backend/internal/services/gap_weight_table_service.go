@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"math"
+
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/profilematching"
+
+	"gorm.io/gorm"
+)
+
+type GapWeightTableService struct {
+	gapWeightTableRepo *repositories.GapWeightTableRepository
+	jabatanRepo        *repositories.JabatanRepository
+}
+
+func NewGapWeightTableService(gapWeightTableRepo *repositories.GapWeightTableRepository, jabatanRepo *repositories.JabatanRepository) *GapWeightTableService {
+	return &GapWeightTableService{
+		gapWeightTableRepo: gapWeightTableRepo,
+		jabatanRepo:        jabatanRepo,
+	}
+}
+
+// WithActor returns the service recording the changes it makes for the user
+func (s *GapWeightTableService) WithActor(userID *uint) *GapWeightTableService {
+	scoped := *s
+	scoped.gapWeightTableRepo = s.gapWeightTableRepo.WithActor(userID)
+	return &scoped
+}
+
+func (s *GapWeightTableService) GetAll() ([]models.GapWeightTable, error) {
+	return s.gapWeightTableRepo.GetAll()
+}
+
+func (s *GapWeightTableService) GetByID(id uint) (*models.GapWeightTable, error) {
+	table, err := s.gapWeightTableRepo.GetByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("gap weight table not found")
+		}
+		return nil, err
+	}
+	return table, nil
+}
+
+func (s *GapWeightTableService) Create(table *models.GapWeightTable) error {
+	if table.Nama == "" {
+		return errors.New("nama tabel bobot tidak boleh kosong")
+	}
+	if table.BelowRange == "" {
+		table.BelowRange = "zero"
+	}
+	if table.AboveRange == "" {
+		table.AboveRange = "zero"
+	}
+	if err := validateOutOfRange(table.BelowRange, table.AboveRange); err != nil {
+		return err
+	}
+	if err := validateGapWeightEntries(table.Entries); err != nil {
+		return err
+	}
+
+	exists, err := s.gapWeightTableRepo.ExistsByNama(table.Nama)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("nama tabel bobot sudah terdaftar")
+	}
+
+	return s.gapWeightTableRepo.Create(table)
+}
+
+// Update updates the table. Entries are replaced only when entries is not nil.
+func (s *GapWeightTableService) Update(id uint, table *models.GapWeightTable, entries []models.GapWeightEntry) error {
+	// Check if table exists
+	existing, err := s.gapWeightTableRepo.GetByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New("gap weight table not found")
+		}
+		return err
+	}
+
+	if err := validateOutOfRange(table.BelowRange, table.AboveRange); err != nil {
+		return err
+	}
+	if entries != nil {
+		if err := validateGapWeightEntries(entries); err != nil {
+			return err
+		}
+	}
+
+	// Check if nama is being changed and if new nama already exists
+	if table.Nama != "" && table.Nama != existing.Nama {
+		exists, err := s.gapWeightTableRepo.ExistsByNama(table.Nama)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("nama tabel bobot sudah terdaftar")
+		}
+	}
+
+	return s.gapWeightTableRepo.Update(id, table, entries)
+}
+
+func (s *GapWeightTableService) Delete(id uint) error {
+	// Check if table exists
+	_, err := s.gapWeightTableRepo.GetByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New("gap weight table not found")
+		}
+		return err
+	}
+
+	inUse, err := s.jabatanRepo.ExistsByGapWeightTableID(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.New("gap weight table is in use by a jabatan")
+	}
+
+	return s.gapWeightTableRepo.Delete(id)
+}
+
+func validateOutOfRange(values ...string) error {
+	for _, v := range values {
+		if v != "" && v != "zero" && v != "clamp" {
+			return errors.New("perilaku di luar rentang harus 'zero' atau 'clamp'")
+		}
+	}
+	return nil
+}
+
+func validateGapWeightEntries(entries []models.GapWeightEntry) error {
+	if len(entries) == 0 {
+		return errors.New("tabel bobot harus memiliki minimal satu entri")
+	}
+	for i, e := range entries {
+		if e.Bobot < 0 {
+			return errors.New("bobot nilai tidak boleh negatif")
+		}
+		for _, other := range entries[:i] {
+			if math.Abs(other.Gap-e.Gap) < profilematching.Epsilon {
+				return errors.New("nilai GAP dalam tabel bobot tidak boleh duplikat")
+			}
+		}
+	}
+	return nil
+}
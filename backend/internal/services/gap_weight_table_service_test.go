@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"backend/internal/models"
+	"backend/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGapWeightTableService_Create(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := NewGapWeightTableService(repositories.NewGapWeightTableRepository(db), repositories.NewJabatanRepository(db))
+
+	table := &models.GapWeightTable{
+		Nama:    "Standar",
+		Entries: []models.GapWeightEntry{{Gap: 0, Bobot: 5}, {Gap: -1, Bobot: 4}},
+	}
+	err := service.Create(table)
+	assert.NoError(t, err)
+	assert.NotZero(t, table.ID)
+	assert.Equal(t, "zero", table.BelowRange)
+	assert.Equal(t, "zero", table.AboveRange)
+}
+
+func TestGapWeightTableService_Create_DuplicateGap(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := NewGapWeightTableService(repositories.NewGapWeightTableRepository(db), repositories.NewJabatanRepository(db))
+
+	table := &models.GapWeightTable{
+		Nama:    "Duplikat",
+		Entries: []models.GapWeightEntry{{Gap: 0, Bobot: 5}, {Gap: 0, Bobot: 4}},
+	}
+	err := service.Create(table)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "duplikat")
+}
+
+func TestGapWeightTableService_Create_NoEntries(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := NewGapWeightTableService(repositories.NewGapWeightTableRepository(db), repositories.NewJabatanRepository(db))
+
+	err := service.Create(&models.GapWeightTable{Nama: "Kosong"})
+	assert.Error(t, err)
+}
+
+func TestGapWeightTableService_Delete_InUse(t *testing.T) {
+	db := setupServiceTestDB(t)
+	gapWeightTableRepo := repositories.NewGapWeightTableRepository(db)
+	jabatanRepo := repositories.NewJabatanRepository(db)
+	service := NewGapWeightTableService(gapWeightTableRepo, jabatanRepo)
+
+	table := &models.GapWeightTable{Nama: "Dipakai", Entries: []models.GapWeightEntry{{Gap: 0, Bobot: 5}}}
+	service.Create(table)
+	jabatanRepo.Create(&models.Jabatan{Nama: "Manager", GapWeightTableID: &table.ID})
+
+	err := service.Delete(table.ID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "in use")
+}
@@ -10,11 +10,22 @@ import (
 )
 
 type JabatanService struct {
-	jabatanRepo *repositories.JabatanRepository
+	jabatanRepo        *repositories.JabatanRepository
+	gapWeightTableRepo *repositories.GapWeightTableRepository
 }
 
-func NewJabatanService(jabatanRepo *repositories.JabatanRepository) *JabatanService {
-	return &JabatanService{jabatanRepo: jabatanRepo}
+func NewJabatanService(jabatanRepo *repositories.JabatanRepository, gapWeightTableRepo *repositories.GapWeightTableRepository) *JabatanService {
+	return &JabatanService{
+		jabatanRepo:        jabatanRepo,
+		gapWeightTableRepo: gapWeightTableRepo,
+	}
+}
+
+// WithActor returns the service recording the changes it makes for the user
+func (s *JabatanService) WithActor(userID *uint) *JabatanService {
+	scoped := *s
+	scoped.jabatanRepo = s.jabatanRepo.WithActor(userID)
+	return &scoped
 }
 
 func (s *JabatanService) GetAll() ([]models.Jabatan, error) {
@@ -36,10 +47,37 @@ func (s *JabatanService) Create(jabatan *models.Jabatan) error {
 	if jabatan.Nama == "" {
 		return errors.New("nama jabatan tidak boleh kosong")
 	}
+	if jabatan.JumlahLowongan < 0 {
+		return errors.New("jumlah lowongan tidak boleh negatif")
+	}
+	if jabatan.GapWeightTableID != nil && *jabatan.GapWeightTableID == 0 {
+		jabatan.GapWeightTableID = nil
+	}
+	if err := s.validateGapWeightTable(jabatan.GapWeightTableID); err != nil {
+		return err
+	}
+	if err := validateFactorRatio(jabatan.CoreFactorPersen, jabatan.SecondaryFactorPersen); err != nil {
+		return err
+	}
 	return s.jabatanRepo.Create(jabatan)
 }
 
-func (s *JabatanService) Update(id uint, jabatan *models.Jabatan) error {
+// JabatanUpdateOptions are the changes to a jabatan its zero values cannot
+// express
+type JabatanUpdateOptions struct {
+	// JumlahLowongan, when set, sets the number of vacancies, including to 0
+	JumlahLowongan *int
+	// ResetFactorRatio makes the jabatan use the system default
+	// core/secondary ratio
+	ResetFactorRatio bool
+	// ResetSkorMinimum removes the knockout total score of the jabatan
+	ResetSkorMinimum bool
+}
+
+// Update validates and updates the jabatan in one transaction. A
+// GapWeightTableID pointing to 0 resets the jabatan to the default GAP
+// weight table.
+func (s *JabatanService) Update(id uint, jabatan *models.Jabatan, opts JabatanUpdateOptions) error {
 	// Check if jabatan exists
 	_, err := s.jabatanRepo.GetByID(id)
 	if err != nil {
@@ -49,6 +87,35 @@ func (s *JabatanService) Update(id uint, jabatan *models.Jabatan) error {
 		return err
 	}
 
+	columns := make(map[string]interface{})
+	// A GAP weight table ID of 0 resets the jabatan to the default table
+	if jabatan.GapWeightTableID != nil && *jabatan.GapWeightTableID == 0 {
+		jabatan.GapWeightTableID = nil
+		columns["gap_weight_table_id"] = nil
+	}
+	if opts.JumlahLowongan != nil {
+		if *opts.JumlahLowongan < 0 {
+			return errors.New("jumlah lowongan tidak boleh negatif")
+		}
+		columns["jumlah_lowongan"] = *opts.JumlahLowongan
+	}
+	if opts.ResetFactorRatio {
+		columns["core_factor_persen"] = nil
+		columns["secondary_factor_persen"] = nil
+	}
+	if opts.ResetSkorMinimum {
+		columns["skor_minimum"] = nil
+	}
+	if err := s.validateGapWeightTable(jabatan.GapWeightTableID); err != nil {
+		return err
+	}
+	if err := validateFactorRatio(jabatan.CoreFactorPersen, jabatan.SecondaryFactorPersen); err != nil {
+		return err
+	}
+
+	if len(columns) > 0 {
+		return s.jabatanRepo.UpdateWithColumns(id, jabatan, columns)
+	}
 	return s.jabatanRepo.Update(id, jabatan)
 }
 
@@ -65,3 +132,16 @@ func (s *JabatanService) Delete(id uint) error {
 	return s.jabatanRepo.Delete(id)
 }
 
+func (s *JabatanService) validateGapWeightTable(gapWeightTableID *uint) error {
+	if gapWeightTableID == nil {
+		return nil
+	}
+	_, err := s.gapWeightTableRepo.GetByID(*gapWeightTableID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New("gap weight table not found")
+		}
+		return err
+	}
+	return nil
+}
@@ -12,7 +12,7 @@ import (
 func TestJabatanService_Create(t *testing.T) {
 	db := setupServiceTestDB(t)
 	repo := repositories.NewJabatanRepository(db)
-	service := NewJabatanService(repo)
+	service := NewJabatanService(repo, repositories.NewGapWeightTableRepository(db))
 
 	jabatan := &models.Jabatan{
 		Nama:      "Manager",
@@ -27,7 +27,7 @@ func TestJabatanService_Create(t *testing.T) {
 func TestJabatanService_Create_EmptyName(t *testing.T) {
 	db := setupServiceTestDB(t)
 	repo := repositories.NewJabatanRepository(db)
-	service := NewJabatanService(repo)
+	service := NewJabatanService(repo, repositories.NewGapWeightTableRepository(db))
 
 	jabatan := &models.Jabatan{
 		Nama:      "",
@@ -42,7 +42,7 @@ func TestJabatanService_Create_EmptyName(t *testing.T) {
 func TestJabatanService_GetByID(t *testing.T) {
 	db := setupServiceTestDB(t)
 	repo := repositories.NewJabatanRepository(db)
-	service := NewJabatanService(repo)
+	service := NewJabatanService(repo, repositories.NewGapWeightTableRepository(db))
 
 	jabatan := &models.Jabatan{
 		Nama:      "Manager",
@@ -58,7 +58,7 @@ func TestJabatanService_GetByID(t *testing.T) {
 func TestJabatanService_GetByID_NotFound(t *testing.T) {
 	db := setupServiceTestDB(t)
 	repo := repositories.NewJabatanRepository(db)
-	service := NewJabatanService(repo)
+	service := NewJabatanService(repo, repositories.NewGapWeightTableRepository(db))
 
 	_, err := service.GetByID(999)
 	assert.Error(t, err)
@@ -68,7 +68,7 @@ func TestJabatanService_GetByID_NotFound(t *testing.T) {
 func TestJabatanService_Update(t *testing.T) {
 	db := setupServiceTestDB(t)
 	repo := repositories.NewJabatanRepository(db)
-	service := NewJabatanService(repo)
+	service := NewJabatanService(repo, repositories.NewGapWeightTableRepository(db))
 
 	jabatan := &models.Jabatan{
 		Nama:      "Manager",
@@ -77,7 +77,7 @@ func TestJabatanService_Update(t *testing.T) {
 	service.Create(jabatan)
 
 	jabatan.Nama = "Senior Manager"
-	err := service.Update(jabatan.ID, jabatan)
+	err := service.Update(jabatan.ID, jabatan, JabatanUpdateOptions{})
 	assert.NoError(t, err)
 
 	updated, _ := service.GetByID(jabatan.ID)
@@ -87,7 +87,7 @@ func TestJabatanService_Update(t *testing.T) {
 func TestJabatanService_Delete(t *testing.T) {
 	db := setupServiceTestDB(t)
 	repo := repositories.NewJabatanRepository(db)
-	service := NewJabatanService(repo)
+	service := NewJabatanService(repo, repositories.NewGapWeightTableRepository(db))
 
 	jabatan := &models.Jabatan{
 		Nama:      "Manager",
@@ -102,3 +102,82 @@ func TestJabatanService_Delete(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestJabatanService_Create_InvalidFactorRatio(t *testing.T) {
+	db := setupServiceTestDB(t)
+	repo := repositories.NewJabatanRepository(db)
+	service := NewJabatanService(repo, repositories.NewGapWeightTableRepository(db))
+
+	core, secondary := 70.0, 40.0
+	jabatan := &models.Jabatan{Nama: "Manager", CoreFactorPersen: &core, SecondaryFactorPersen: &secondary}
+
+	err := service.Create(jabatan)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "factor ratio")
+}
+
+func TestJabatanService_Update_ResetGapWeightTable(t *testing.T) {
+	db := setupServiceTestDB(t)
+	repo := repositories.NewJabatanRepository(db)
+	gapWeightTableRepo := repositories.NewGapWeightTableRepository(db)
+	service := NewJabatanService(repo, gapWeightTableRepo)
+
+	table := &models.GapWeightTable{Nama: "Ketat"}
+	assert.NoError(t, gapWeightTableRepo.Create(table))
+	jabatan := &models.Jabatan{Nama: "Manager", GapWeightTableID: &table.ID}
+	assert.NoError(t, service.Create(jabatan))
+
+	// A rejected update leaves the table assigned
+	core, secondary := 70.0, 40.0
+	reset := uint(0)
+	err := service.Update(jabatan.ID, &models.Jabatan{Nama: "Manager", GapWeightTableID: &reset, CoreFactorPersen: &core, SecondaryFactorPersen: &secondary}, JabatanUpdateOptions{})
+	assert.Error(t, err)
+	updated, _ := service.GetByID(jabatan.ID)
+	assert.Equal(t, &table.ID, updated.GapWeightTableID)
+
+	reset = 0
+	assert.NoError(t, service.Update(jabatan.ID, &models.Jabatan{Nama: "Senior Manager", GapWeightTableID: &reset}, JabatanUpdateOptions{}))
+	updated, _ = service.GetByID(jabatan.ID)
+	assert.Nil(t, updated.GapWeightTableID)
+	assert.Equal(t, "Senior Manager", updated.Nama)
+}
+
+func TestJabatanService_Update_JumlahLowongan(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := NewJabatanService(repositories.NewJabatanRepository(db), repositories.NewGapWeightTableRepository(db))
+
+	jabatan := &models.Jabatan{Nama: "Manager", JumlahLowongan: 2}
+	assert.NoError(t, service.Create(jabatan))
+
+	// A rejected update leaves the vacancies unchanged
+	core, secondary := 70.0, 40.0
+	zero := 0
+	err := service.Update(jabatan.ID, &models.Jabatan{CoreFactorPersen: &core, SecondaryFactorPersen: &secondary}, JabatanUpdateOptions{JumlahLowongan: &zero})
+	assert.Error(t, err)
+	updated, _ := service.GetByID(jabatan.ID)
+	assert.Equal(t, 2, updated.JumlahLowongan)
+
+	assert.NoError(t, service.Update(jabatan.ID, &models.Jabatan{Nama: "Senior Manager"}, JabatanUpdateOptions{JumlahLowongan: &zero}))
+	updated, _ = service.GetByID(jabatan.ID)
+	assert.Equal(t, 0, updated.JumlahLowongan)
+	assert.Equal(t, "Senior Manager", updated.Nama)
+}
+
+func TestJabatanService_Update_ResetSkorMinimum(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := NewJabatanService(repositories.NewJabatanRepository(db), repositories.NewGapWeightTableRepository(db))
+
+	skor := 3.5
+	jabatan := &models.Jabatan{Nama: "Manager", SkorMinimum: &skor}
+	assert.NoError(t, service.Create(jabatan))
+
+	// A rejected update keeps the minimum
+	missing := uint(999)
+	err := service.Update(jabatan.ID, &models.Jabatan{GapWeightTableID: &missing}, JabatanUpdateOptions{ResetSkorMinimum: true})
+	assert.EqualError(t, err, "gap weight table not found")
+	updated, _ := service.GetByID(jabatan.ID)
+	assert.Equal(t, &skor, updated.SkorMinimum)
+
+	assert.NoError(t, service.Update(jabatan.ID, &models.Jabatan{}, JabatanUpdateOptions{ResetSkorMinimum: true}))
+	updated, _ = service.GetByID(jabatan.ID)
+	assert.Nil(t, updated.SkorMinimum)
+}
@@ -21,6 +21,13 @@ func NewKriteriaService(kriteriaRepo *repositories.KriteriaRepository, aspekRepo
 	}
 }
 
+// WithActor returns the service recording the changes it makes for the user
+func (s *KriteriaService) WithActor(userID *uint) *KriteriaService {
+	scoped := *s
+	scoped.kriteriaRepo = s.kriteriaRepo.WithActor(userID)
+	return &scoped
+}
+
 func (s *KriteriaService) GetAll() ([]models.Kriteria, error) {
 	return s.kriteriaRepo.GetAll()
 }
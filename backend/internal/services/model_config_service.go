@@ -0,0 +1,281 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend/internal/dto"
+	"backend/internal/models"
+	"backend/internal/repositories"
+)
+
+type ModelConfigService struct {
+	gapWeightTableRepo *repositories.GapWeightTableRepository
+	aspekRepo          *repositories.AspekRepository
+	kriteriaRepo       *repositories.KriteriaRepository
+	jabatanRepo        *repositories.JabatanRepository
+	targetProfileRepo  *repositories.TargetProfileRepository
+	modelConfigRepo    *repositories.ModelConfigRepository
+}
+
+func NewModelConfigService(
+	gapWeightTableRepo *repositories.GapWeightTableRepository,
+	aspekRepo *repositories.AspekRepository,
+	kriteriaRepo *repositories.KriteriaRepository,
+	jabatanRepo *repositories.JabatanRepository,
+	targetProfileRepo *repositories.TargetProfileRepository,
+	modelConfigRepo *repositories.ModelConfigRepository,
+) *ModelConfigService {
+	return &ModelConfigService{
+		gapWeightTableRepo: gapWeightTableRepo,
+		aspekRepo:          aspekRepo,
+		kriteriaRepo:       kriteriaRepo,
+		jabatanRepo:        jabatanRepo,
+		targetProfileRepo:  targetProfileRepo,
+		modelConfigRepo:    modelConfigRepo,
+	}
+}
+
+// WithActor returns the service recording the changes it makes for the user
+func (s *ModelConfigService) WithActor(userID *uint) *ModelConfigService {
+	scoped := *s
+	scoped.modelConfigRepo = s.modelConfigRepo.WithActor(userID)
+	return &scoped
+}
+
+// Export returns the configuration as a document, each list ordered by
+// natural key. It fails when a natural key is shared by several records,
+// since the document could not be imported again.
+func (s *ModelConfigService) Export() (*dto.ModelConfig, error) {
+	tables, err := s.gapWeightTableRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	aspeks, err := s.aspekRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	kriterias, err := s.kriteriaRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	jabatans, err := s.jabatanRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	targetProfiles, err := s.targetProfileRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &dto.ModelConfig{
+		Version:         dto.ModelConfigVersion,
+		ExportedAt:      time.Now().UTC(),
+		GapWeightTables: make([]dto.GapWeightTableConfig, 0, len(tables)),
+		Aspek:           make([]dto.AspekConfig, 0, len(aspeks)),
+		Kriteria:        make([]dto.KriteriaConfig, 0, len(kriterias)),
+		Jabatan:         make([]dto.JabatanConfig, 0, len(jabatans)),
+	}
+	unique := make(map[string]bool)
+	claim := func(kind, key string) error {
+		if unique[kind+"\x00"+key] {
+			return fmt.Errorf("%s %q is not unique", kind, key)
+		}
+		unique[kind+"\x00"+key] = true
+		return nil
+	}
+
+	tableNama := make(map[uint]string, len(tables))
+	for _, t := range tables {
+		if err := claim("gap weight table", t.Nama); err != nil {
+			return nil, err
+		}
+		tableNama[t.ID] = t.Nama
+		c := dto.GapWeightTableConfig{Nama: t.Nama, Deskripsi: t.Deskripsi, BelowRange: t.BelowRange, AboveRange: t.AboveRange}
+		for _, e := range t.Entries {
+			c.Entries = append(c.Entries, dto.GapWeightEntryConfig{Gap: e.Gap, Bobot: e.Bobot})
+		}
+		cfg.GapWeightTables = append(cfg.GapWeightTables, c)
+	}
+
+	aspekNama := make(map[uint]string, len(aspeks))
+	for _, a := range aspeks {
+		if err := claim("aspek", a.Nama); err != nil {
+			return nil, err
+		}
+		aspekNama[a.ID] = a.Nama
+		cfg.Aspek = append(cfg.Aspek, dto.AspekConfig{
+			Nama:                  a.Nama,
+			Deskripsi:             a.Deskripsi,
+			Persentase:            a.Persentase,
+			CoreFactorPersen:      a.CoreFactorPersen,
+			SecondaryFactorPersen: a.SecondaryFactorPersen,
+		})
+	}
+
+	kriteriaKode := make(map[uint]string, len(kriterias))
+	for _, k := range kriterias {
+		if err := claim("kriteria", k.Kode); err != nil {
+			return nil, err
+		}
+		kriteriaKode[k.ID] = k.Kode
+		cfg.Kriteria = append(cfg.Kriteria, dto.KriteriaConfig{
+			Kode:   k.Kode,
+			Nama:   k.Nama,
+			Aspek:  aspekNama[k.AspekID],
+			IsCore: k.IsCore,
+			Bobot:  k.Bobot,
+		})
+	}
+
+	byJabatan := make(map[uint]map[string]dto.TargetProfileConfig)
+	for _, tp := range targetProfiles {
+		kode, ok := kriteriaKode[tp.KriteriaID]
+		if !ok {
+			continue // The kriteria was deleted
+		}
+		if byJabatan[tp.JabatanID] == nil {
+			byJabatan[tp.JabatanID] = make(map[string]dto.TargetProfileConfig)
+		}
+		byJabatan[tp.JabatanID][kode] = dto.TargetProfileConfig{TargetNilai: tp.TargetNilai, NilaiMinimum: tp.NilaiMinimum}
+	}
+	for _, j := range jabatans {
+		if err := claim("jabatan", j.Nama); err != nil {
+			return nil, err
+		}
+		c := dto.JabatanConfig{
+			Nama:                  j.Nama,
+			Deskripsi:             j.Deskripsi,
+			CoreFactorPersen:      j.CoreFactorPersen,
+			SecondaryFactorPersen: j.SecondaryFactorPersen,
+			JumlahLowongan:        j.JumlahLowongan,
+			SkorMinimum:           j.SkorMinimum,
+			TargetProfiles:        byJabatan[j.ID],
+		}
+		if j.GapWeightTableID != nil {
+			c.GapWeightTable = tableNama[*j.GapWeightTableID]
+		}
+		if c.TargetProfiles == nil {
+			c.TargetProfiles = map[string]dto.TargetProfileConfig{}
+		}
+		cfg.Jabatan = append(cfg.Jabatan, c)
+	}
+
+	sort.Slice(cfg.GapWeightTables, func(i, j int) bool { return cfg.GapWeightTables[i].Nama < cfg.GapWeightTables[j].Nama })
+	sort.Slice(cfg.Aspek, func(i, j int) bool { return cfg.Aspek[i].Nama < cfg.Aspek[j].Nama })
+	sort.Slice(cfg.Kriteria, func(i, j int) bool { return cfg.Kriteria[i].Kode < cfg.Kriteria[j].Kode })
+	sort.Slice(cfg.Jabatan, func(i, j int) bool { return cfg.Jabatan[i].Nama < cfg.Jabatan[j].Nama })
+	return cfg, nil
+}
+
+// Import applies a configuration document. Records are matched by natural
+// key: "merge" creates or overwrites the records in the document and keeps
+// the others, "replace" also deletes the records missing from it. A dry run
+// reports the changes without saving them.
+func (s *ModelConfigService) Import(cfg *dto.ModelConfig, mode string, dryRun bool) (dto.ConfigImportSummary, error) {
+	if mode != "merge" && mode != "replace" {
+		return dto.ConfigImportSummary{}, errors.New("import mode must be merge or replace")
+	}
+	if err := validateModelConfig(cfg, mode == "replace"); err != nil {
+		return dto.ConfigImportSummary{}, err
+	}
+	return s.modelConfigRepo.Import(cfg, mode == "replace", dryRun)
+}
+
+// validateModelConfig applies the rules of the create endpoints to each
+// record and requires natural keys to be unique. In replace mode references
+// must resolve within the document; in merge mode they may also name
+// existing records, which the import checks. Empty out-of-range behaviours
+// of gap weight tables are set to "zero".
+func validateModelConfig(cfg *dto.ModelConfig, replace bool) error {
+	if cfg.Version != dto.ModelConfigVersion {
+		return fmt.Errorf("unsupported config version %d, expected %d", cfg.Version, dto.ModelConfigVersion)
+	}
+
+	tables := make(map[string]bool)
+	for i := range cfg.GapWeightTables {
+		t := &cfg.GapWeightTables[i]
+		if t.Nama == "" {
+			return errors.New("nama tabel bobot tidak boleh kosong")
+		}
+		if tables[t.Nama] {
+			return fmt.Errorf("gap weight table %q appears more than once", t.Nama)
+		}
+		tables[t.Nama] = true
+		if err := validateOutOfRange(t.BelowRange, t.AboveRange); err != nil {
+			return fmt.Errorf("gap weight table %q: %v", t.Nama, err)
+		}
+		if t.BelowRange == "" {
+			t.BelowRange = "zero"
+		}
+		if t.AboveRange == "" {
+			t.AboveRange = "zero"
+		}
+		entries := make([]models.GapWeightEntry, len(t.Entries))
+		for j, e := range t.Entries {
+			entries[j] = models.GapWeightEntry{Gap: e.Gap, Bobot: e.Bobot}
+		}
+		if err := validateGapWeightEntries(entries); err != nil {
+			return fmt.Errorf("gap weight table %q: %v", t.Nama, err)
+		}
+	}
+
+	aspek := make(map[string]bool)
+	for _, a := range cfg.Aspek {
+		if a.Nama == "" {
+			return errors.New("nama aspek tidak boleh kosong")
+		}
+		if aspek[a.Nama] {
+			return fmt.Errorf("aspek %q appears more than once", a.Nama)
+		}
+		aspek[a.Nama] = true
+		if err := validateFactorRatio(a.CoreFactorPersen, a.SecondaryFactorPersen); err != nil {
+			return fmt.Errorf("aspek %q: %v", a.Nama, err)
+		}
+	}
+
+	kriteria := make(map[string]bool)
+	for _, k := range cfg.Kriteria {
+		if k.Kode == "" {
+			return errors.New("kode kriteria tidak boleh kosong")
+		}
+		if k.Nama == "" {
+			return fmt.Errorf("kriteria %s: nama kriteria tidak boleh kosong", k.Kode)
+		}
+		if kriteria[k.Kode] {
+			return fmt.Errorf("kriteria %q appears more than once", k.Kode)
+		}
+		kriteria[k.Kode] = true
+		if replace && !aspek[k.Aspek] {
+			return fmt.Errorf("kriteria %s: aspek %q is not in the configuration", k.Kode, k.Aspek)
+		}
+	}
+
+	jabatan := make(map[string]bool)
+	for _, j := range cfg.Jabatan {
+		if j.Nama == "" {
+			return errors.New("nama jabatan tidak boleh kosong")
+		}
+		if jabatan[j.Nama] {
+			return fmt.Errorf("jabatan %q appears more than once", j.Nama)
+		}
+		jabatan[j.Nama] = true
+		if j.JumlahLowongan < 0 {
+			return fmt.Errorf("jabatan %q: jumlah lowongan tidak boleh negatif", j.Nama)
+		}
+		if err := validateFactorRatio(j.CoreFactorPersen, j.SecondaryFactorPersen); err != nil {
+			return fmt.Errorf("jabatan %q: %v", j.Nama, err)
+		}
+		if replace && j.GapWeightTable != "" && !tables[j.GapWeightTable] {
+			return fmt.Errorf("jabatan %q: gap weight table %q is not in the configuration", j.Nama, j.GapWeightTable)
+		}
+		for kode := range j.TargetProfiles {
+			if replace && !kriteria[kode] {
+				return fmt.Errorf("jabatan %q: kriteria %q is not in the configuration", j.Nama, kode)
+			}
+		}
+	}
+	return nil
+}
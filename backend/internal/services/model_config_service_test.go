@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+
+	"backend/internal/dto"
+	"backend/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestModelConfigService(t *testing.T) *ModelConfigService {
+	db := setupServiceTestDB(t)
+	return NewModelConfigService(
+		repositories.NewGapWeightTableRepository(db),
+		repositories.NewAspekRepository(db),
+		repositories.NewKriteriaRepository(db),
+		repositories.NewJabatanRepository(db),
+		repositories.NewTargetProfileRepository(db),
+		repositories.NewModelConfigRepository(db),
+	)
+}
+
+func testModelConfig() *dto.ModelConfig {
+	minimum := 2.0
+	return &dto.ModelConfig{
+		Version: dto.ModelConfigVersion,
+		GapWeightTables: []dto.GapWeightTableConfig{
+			{Nama: "Ketat", Entries: []dto.GapWeightEntryConfig{{Gap: 0, Bobot: 5}, {Gap: -1, Bobot: 2}}},
+		},
+		Aspek: []dto.AspekConfig{{Nama: "Teknis", Persentase: 100}},
+		Kriteria: []dto.KriteriaConfig{
+			{Kode: "K1", Nama: "Kriteria 1", Aspek: "Teknis", IsCore: true, Bobot: 1},
+			{Kode: "K2", Nama: "Kriteria 2", Aspek: "Teknis", Bobot: 2},
+		},
+		Jabatan: []dto.JabatanConfig{{
+			Nama:           "Supervisor",
+			GapWeightTable: "Ketat",
+			JumlahLowongan: 2,
+			TargetProfiles: map[string]dto.TargetProfileConfig{
+				"K1": {TargetNilai: 4, NilaiMinimum: &minimum},
+				"K2": {TargetNilai: 3},
+			},
+		}},
+	}
+}
+
+func TestValidateModelConfig(t *testing.T) {
+	assert.NoError(t, validateModelConfig(testModelConfig(), true))
+
+	cfg := testModelConfig()
+	assert.Equal(t, "", cfg.GapWeightTables[0].BelowRange)
+	validateModelConfig(cfg, false)
+	assert.Equal(t, "zero", cfg.GapWeightTables[0].BelowRange)
+
+	cfg = testModelConfig()
+	cfg.Version = 2
+	assert.EqualError(t, validateModelConfig(cfg, false), "unsupported config version 2, expected 1")
+
+	cfg = testModelConfig()
+	cfg.Kriteria = append(cfg.Kriteria, dto.KriteriaConfig{Kode: "K1", Nama: "Lagi", Aspek: "Teknis"})
+	assert.EqualError(t, validateModelConfig(cfg, false), `kriteria "K1" appears more than once`)
+
+	// Merge may refer to existing records; replace may not
+	cfg = testModelConfig()
+	cfg.Jabatan[0].TargetProfiles["K9"] = dto.TargetProfileConfig{TargetNilai: 3}
+	assert.NoError(t, validateModelConfig(cfg, false))
+	assert.EqualError(t, validateModelConfig(cfg, true), `jabatan "Supervisor": kriteria "K9" is not in the configuration`)
+}
+
+func TestModelConfigService_ImportExport(t *testing.T) {
+	service := newTestModelConfigService(t)
+
+	// A dry run saves nothing
+	summary, err := service.Import(testModelConfig(), "merge", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Kriteria.Created)
+	exported, err := service.Export()
+	assert.NoError(t, err)
+	assert.Empty(t, exported.Kriteria)
+
+	summary, err = service.Import(testModelConfig(), "merge", false)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.ConfigImportCounts{Created: 2}, summary.TargetProfiles)
+
+	exported, err = service.Export()
+	assert.NoError(t, err)
+	assert.Equal(t, dto.ModelConfigVersion, exported.Version)
+	assert.Len(t, exported.Kriteria, 2)
+	assert.Equal(t, "Teknis", exported.Kriteria[0].Aspek)
+	assert.Equal(t, "Ketat", exported.Jabatan[0].GapWeightTable)
+	assert.Equal(t, 2.0, *exported.Jabatan[0].TargetProfiles["K1"].NilaiMinimum)
+
+	// Replace matches by natural key, updating K1 and dropping K2
+	cfg := testModelConfig()
+	cfg.Kriteria = cfg.Kriteria[:1]
+	cfg.Kriteria[0].Bobot = 3
+	delete(cfg.Jabatan[0].TargetProfiles, "K2")
+	summary, err = service.Import(cfg, "replace", false)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.ConfigImportCounts{Updated: 1, Deleted: 1}, summary.Kriteria)
+	assert.Equal(t, dto.ConfigImportCounts{Updated: 1, Deleted: 1}, summary.TargetProfiles)
+
+	exported, err = service.Export()
+	assert.NoError(t, err)
+	assert.Len(t, exported.Kriteria, 1)
+	assert.Equal(t, 3.0, exported.Kriteria[0].Bobot)
+	assert.Len(t, exported.Jabatan[0].TargetProfiles, 1)
+
+	_, err = service.Import(cfg, "overwrite", false)
+	assert.EqualError(t, err, "import mode must be merge or replace")
+}
@@ -27,6 +27,13 @@ func NewNilaiTenagaKerjaService(
 	}
 }
 
+// WithActor returns the service recording the changes it makes for the user
+func (s *NilaiTenagaKerjaService) WithActor(userID *uint) *NilaiTenagaKerjaService {
+	scoped := *s
+	scoped.nilaiTenagaKerjaRepo = s.nilaiTenagaKerjaRepo.WithActor(userID)
+	return &scoped
+}
+
 func (s *NilaiTenagaKerjaService) GetAll() ([]models.NilaiTenagaKerja, error) {
 	return s.nilaiTenagaKerjaRepo.GetAll()
 }
@@ -115,4 +122,3 @@ func (s *NilaiTenagaKerjaService) Delete(id uint) error {
 
 	return s.nilaiTenagaKerjaRepo.Delete(id)
 }
-
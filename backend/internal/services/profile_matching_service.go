@@ -2,20 +2,27 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"sort"
 
 	"backend/internal/models"
 	"backend/internal/repositories"
+	"backend/pkg/profilematching"
 
 	"gorm.io/gorm"
 )
 
 type ProfileMatchingService struct {
-	targetProfileRepo      *repositories.TargetProfileRepository
-	kriteriaRepo           *repositories.KriteriaRepository
-	nilaiTenagaKerjaRepo   *repositories.NilaiTenagaKerjaRepository
-	tenagaKerjaRepo        *repositories.TenagaKerjaRepository
-	profileMatchResultRepo *repositories.ProfileMatchResultRepository
-	jabatanRepo            *repositories.JabatanRepository
+	targetProfileRepo       *repositories.TargetProfileRepository
+	kriteriaRepo            *repositories.KriteriaRepository
+	nilaiTenagaKerjaRepo    *repositories.NilaiTenagaKerjaRepository
+	tenagaKerjaRepo         *repositories.TenagaKerjaRepository
+	profileMatchResultRepo  *repositories.ProfileMatchResultRepository
+	jabatanRepo             *repositories.JabatanRepository
+	gapWeightTableRepo      *repositories.GapWeightTableRepository
+	calculationRunRepo      *repositories.CalculationRunRepository
+	resultStatusHistoryRepo *repositories.ResultStatusHistoryRepository
 }
 
 func NewProfileMatchingService(
@@ -25,25 +32,66 @@ func NewProfileMatchingService(
 	tenagaKerjaRepo *repositories.TenagaKerjaRepository,
 	profileMatchResultRepo *repositories.ProfileMatchResultRepository,
 	jabatanRepo *repositories.JabatanRepository,
+	gapWeightTableRepo *repositories.GapWeightTableRepository,
+	calculationRunRepo *repositories.CalculationRunRepository,
+	resultStatusHistoryRepo *repositories.ResultStatusHistoryRepository,
 ) *ProfileMatchingService {
 	return &ProfileMatchingService{
-		targetProfileRepo:      targetProfileRepo,
-		kriteriaRepo:           kriteriaRepo,
-		nilaiTenagaKerjaRepo:   nilaiTenagaKerjaRepo,
-		tenagaKerjaRepo:        tenagaKerjaRepo,
-		profileMatchResultRepo: profileMatchResultRepo,
-		jabatanRepo:            jabatanRepo,
+		targetProfileRepo:       targetProfileRepo,
+		kriteriaRepo:            kriteriaRepo,
+		nilaiTenagaKerjaRepo:    nilaiTenagaKerjaRepo,
+		tenagaKerjaRepo:         tenagaKerjaRepo,
+		profileMatchResultRepo:  profileMatchResultRepo,
+		jabatanRepo:             jabatanRepo,
+		gapWeightTableRepo:      gapWeightTableRepo,
+		calculationRunRepo:      calculationRunRepo,
+		resultStatusHistoryRepo: resultStatusHistoryRepo,
 	}
 }
 
 type CalculationRequest struct {
 	JabatanID      uint
 	TenagaKerjaIDs []uint
+	// GapMode is "exact" (default), "interpolate" or "round"
+	GapMode string
+	// RoundingRule applies to the "round" mode: "nearest" (default),
+	// "half_up", "half_down", "floor" or "ceil"
+	RoundingRule string
+	// MissingPolicy handles target kriteria without nilai: "skip" (default),
+	// "minimum", "exclude" or "incomplete"
+	MissingPolicy string
+	// MinimumNilai is the nilai assumed by the "minimum" policy
+	MinimumNilai *float64
+	// Method is "profile_matching" (default), "saw", "wp" or "topsis"
+	Method string
+	// RankingStyle is "ordinal" (default), "competition" or "dense"
+	RankingStyle string
+	// TieBreakers order equal scores: "core_factor", "secondary_factor",
+	// "kriteria:<kode>" or "nik"
+	TieBreakers []string
+	// UserID is the user requesting the calculation, recorded on the run
+	UserID *uint
 }
 
-func (s *ProfileMatchingService) Calculate(req CalculationRequest) ([]models.ProfileMatchResult, error) {
+// calculation holds everything loaded for a jabatan before ranking
+type calculation struct {
+	jabatan     *models.Jabatan
+	engine      *profilematching.Engine
+	profile     profilematching.Profile
+	candidates  []profilematching.Candidate
+	tenagaKerja map[uint]models.TenagaKerja
+	mode        profilematching.GapMode
+	policy      profilematching.MissingPolicy
+	method      string
+	ratio       profilematching.Ratio
+	ranking     profilematching.RankingRule
+}
+
+// prepareCalculation validates the request and loads the jabatan's profile
+// and the candidates' nilai into the engine's plain inputs
+func (s *ProfileMatchingService) prepareCalculation(req CalculationRequest) (*calculation, error) {
 	// Validate jabatan exists
-	_, err := s.jabatanRepo.GetByID(req.JabatanID)
+	jabatan, err := s.jabatanRepo.GetByID(req.JabatanID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("jabatan not found")
@@ -51,6 +99,33 @@ func (s *ProfileMatchingService) Calculate(req CalculationRequest) ([]models.Pro
 		return nil, err
 	}
 
+	mode, err := profilematching.NewGapMode(req.GapMode, req.RoundingRule)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := profilematching.NewMissingPolicy(req.MissingPolicy, req.MinimumNilai)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := profilematching.ValidateMethod(req.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking, err := profilematching.NewRankingRule(req.RankingStyle, req.TieBreakers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the GAP weight table and factor ratio in effect for the jabatan
+	table, err := s.gapTableForJabatan(jabatan)
+	if err != nil {
+		return nil, err
+	}
+	ratio := factorRatioForJabatan(jabatan)
+
 	// Get target profiles for the position
 	targetProfiles, err := s.targetProfileRepo.GetByJabatanID(req.JabatanID)
 	if err != nil {
@@ -61,140 +136,534 @@ func (s *ProfileMatchingService) Calculate(req CalculationRequest) ([]models.Pro
 		return nil, errors.New("no target profiles found for this jabatan")
 	}
 
-	// Get all kriteria
-	kriterias, err := s.kriteriaRepo.GetAll()
+	profile, err := s.loadProfile(targetProfiles)
 	if err != nil {
-		return nil, errors.New("could not fetch kriteria")
+		return nil, err
 	}
+	profile.MinimumScore = jabatan.SkorMinimum
 
-	// Create map of kriteria for easy lookup
-	kriteriaMap := make(map[uint]models.Kriteria)
-	for _, k := range kriterias {
-		kriteriaMap[k.ID] = k
+	// Kriteria tie-breakers must refer to the target profile
+	targetKode := make(map[string]bool)
+	for _, t := range profile.Targets {
+		targetKode[profile.Kriteria[t.KriteriaID].Kode] = true
+	}
+	for _, kode := range ranking.KriteriaCodes() {
+		if !targetKode[kode] {
+			return nil, errors.New("tie breaker kriteria not in target profile")
+		}
+	}
+
+	// Only nilai of the jabatan's target kriteria are loaded
+	kriteriaIDs := make([]uint, 0, len(profile.Targets))
+	for _, t := range profile.Targets {
+		kriteriaIDs = append(kriteriaIDs, t.KriteriaID)
 	}
 
 	// Get tenaga kerja IDs to process
 	tenagaKerjaIDs := req.TenagaKerjaIDs
 	if len(tenagaKerjaIDs) == 0 {
-		// If no tenaga_kerja_ids provided, get all tenaga kerja that have nilai
-		allNilai, err := s.nilaiTenagaKerjaRepo.GetAll()
+		// If no tenaga_kerja_ids provided, get all tenaga kerja that have
+		// nilai for the jabatan's kriteria
+		tenagaKerjaIDs, err = s.nilaiTenagaKerjaRepo.GetTenagaKerjaIDsByKriteriaIDs(kriteriaIDs)
 		if err != nil {
 			return nil, errors.New("could not fetch nilai tenaga kerja")
 		}
+	}
 
-		// Get unique tenaga kerja IDs
-		idMap := make(map[uint]bool)
-		for _, n := range allNilai {
-			idMap[n.TenagaKerjaID] = true
-		}
+	calc := &calculation{
+		jabatan:     jabatan,
+		engine:      profilematching.New(profilematching.NewGapWeighter(table, mode), ratio, policy).WithMethod(method).WithRanking(ranking),
+		profile:     profile,
+		tenagaKerja: make(map[uint]models.TenagaKerja),
+		mode:        mode,
+		policy:      policy,
+		method:      method,
+		ratio:       ratio,
+		ranking:     ranking,
+	}
 
-		for id := range idMap {
-			tenagaKerjaIDs = append(tenagaKerjaIDs, id)
-		}
+	if err := s.loadCandidates(calc, tenagaKerjaIDs, kriteriaIDs); err != nil {
+		return nil, err
 	}
 
-	var results []models.ProfileMatchResult
+	return calc, nil
+}
+
+// candidateChunkSize is the number of tenaga kerja loaded per query, keeping
+// the IN lists of the bulk queries bounded
+const candidateChunkSize = 1000
+
+// loadCandidates loads the tenaga kerja and their nilai for the kriteria in
+// chunks, two queries per chunk. Tenaga kerja that do not exist are skipped.
+func (s *ProfileMatchingService) loadCandidates(calc *calculation, tenagaKerjaIDs, kriteriaIDs []uint) error {
+	for start := 0; start < len(tenagaKerjaIDs); start += candidateChunkSize {
+		chunk := tenagaKerjaIDs[start:min(start+candidateChunkSize, len(tenagaKerjaIDs))]
 
-	for _, tenagaKerjaID := range tenagaKerjaIDs {
-		// Validate tenaga kerja exists
-		_, err := s.tenagaKerjaRepo.GetByID(tenagaKerjaID)
+		tenagaKerjas, err := s.tenagaKerjaRepo.GetByIDs(chunk)
 		if err != nil {
-			if err == gorm.ErrRecordNotFound {
-				continue // Skip invalid tenaga kerja
-			}
-			return nil, err
+			return err
 		}
-
-		// Get nilai for this tenaga kerja
-		nilaiList, err := s.nilaiTenagaKerjaRepo.GetByTenagaKerjaID(tenagaKerjaID)
+		nilaiList, err := s.nilaiTenagaKerjaRepo.GetByTenagaKerjaIDsAndKriteriaIDs(chunk, kriteriaIDs)
 		if err != nil {
-			continue // Skip if no nilai found
+			return errors.New("could not fetch nilai tenaga kerja")
 		}
 
-		// Create map of nilai for easy lookup
-		nilaiMap := make(map[uint]float64)
+		nilai := make(map[uint]map[uint]float64, len(tenagaKerjas))
 		for _, n := range nilaiList {
-			nilaiMap[n.KriteriaID] = n.Nilai
+			if nilai[n.TenagaKerjaID] == nil {
+				nilai[n.TenagaKerjaID] = make(map[uint]float64)
+			}
+			nilai[n.TenagaKerjaID][n.KriteriaID] = n.Nilai
 		}
 
-		// Calculate scores
-		var totalCoreGap float64
-		var totalSecondaryCap float64
-		var countCore int
-		var countSecondary int
-
-		for _, target := range targetProfiles {
-			kriteria, exists := kriteriaMap[target.KriteriaID]
+		existing := make(map[uint]models.TenagaKerja, len(tenagaKerjas))
+		for _, tk := range tenagaKerjas {
+			existing[tk.ID] = tk
+		}
+		// Keep the requested order so equal scores rank the same way each time
+		for _, id := range chunk {
+			tk, exists := existing[id]
 			if !exists {
+				continue // Skip invalid tenaga kerja
+			}
+			if _, seen := calc.tenagaKerja[id]; seen {
 				continue
 			}
-
-			nilai, exists := nilaiMap[target.KriteriaID]
-			if !exists {
-				continue // Skip if no nilai for this kriteria
+			candidateNilai := nilai[id]
+			if candidateNilai == nil {
+				candidateNilai = make(map[uint]float64)
 			}
+			calc.tenagaKerja[id] = tk
+			calc.candidates = append(calc.candidates, profilematching.Candidate{ID: id, NIK: tk.NIK, Nilai: candidateNilai})
+		}
+	}
+	return nil
+}
 
-			// Calculate GAP
-			gap := nilai - target.TargetNilai
+// rank ranks the eligible candidates and returns the results in rank order,
+// with the engine's breakdown of each, and the candidates knocked out
+func (calc *calculation) rank() ([]models.ProfileMatchResult, []profilematching.Result, []models.IneligibleResult) {
+	var results []models.ProfileMatchResult
+	var evaluations []profilematching.Result
 
-			// Convert GAP to weight based on profile matching rules
-			weight := calculateWeight(gap)
+	rankedCandidates, ineligibleCandidates := calc.engine.RankEligible(calc.profile, calc.candidates)
+	for _, ranked := range rankedCandidates {
+		evaluation := ranked.Result
 
-			if kriteria.IsCore {
-				totalCoreGap += weight
-				countCore++
-			} else {
-				totalSecondaryCap += weight
-				countSecondary++
-			}
+		// Create result
+		result := models.ProfileMatchResult{
+			TenagaKerjaID:         ranked.ID,
+			JabatanID:             calc.jabatan.ID,
+			TotalScore:            ranked.Score,
+			CoreFactor:            evaluation.CoreFactor,
+			SecondaryFactor:       evaluation.SecondaryFactor,
+			CoreFactorPersen:      calc.ratio.Core,
+			SecondaryFactorPersen: calc.ratio.Secondary,
+			GapMode:               calc.mode.Mode,
+			RoundingRule:          calc.mode.RoundingRule,
+			MissingPolicy:         calc.policy.Policy,
+			MinimumNilai:          calc.policy.MinimumNilai,
+			KriteriaDinilai:       evaluation.KriteriaDinilai,
+			KriteriaTotal:         evaluation.KriteriaTotal,
+			MissingKriteria:       evaluation.Missing,
+			Incomplete:            evaluation.Incomplete,
+			Method:                calc.method,
+			Rank:                  ranked.Rank,
+			RankingStyle:          calc.ranking.Style,
+			TieBreakers:           calc.ranking.TieBreakers,
+			Aspek:                 snapshotFromEvaluation(evaluation),
 		}
 
-		// Avoid divide by zero
-		var coreFactor float64
-		var secondaryFactor float64
-		if countCore > 0 {
-			coreFactor = totalCoreGap / float64(countCore)
+		results = append(results, result)
+		evaluations = append(evaluations, evaluation)
+	}
+
+	ineligible := make([]models.IneligibleResult, len(ineligibleCandidates))
+	for i, c := range ineligibleCandidates {
+		ineligible[i] = models.IneligibleResult{
+			TenagaKerjaID:    c.ID,
+			JabatanID:        calc.jabatan.ID,
+			BelowSkorMinimum: c.BelowMinimumScore,
+			TotalScore:       c.Score,
 		}
-		if countSecondary > 0 {
-			secondaryFactor = totalSecondaryCap / float64(countSecondary)
+		for _, f := range c.Failures {
+			kriteria := calc.profile.Kriteria[f.KriteriaID]
+			ineligible[i].Failures = append(ineligible[i].Failures, models.KnockoutFailure{
+				KriteriaID:   f.KriteriaID,
+				Kode:         kriteria.Kode,
+				Nama:         kriteria.Nama,
+				NilaiMinimum: f.Minimum,
+				Nilai:        f.Nilai,
+			})
 		}
+	}
+
+	return results, evaluations, ineligible
+}
 
-		// Final calculation (60% core factor + 40% secondary factor)
-		totalScore := (0.6 * coreFactor) + (0.4 * secondaryFactor)
+// Calculate ranks the jabatan's tenaga kerja and stores the ranking as a new
+// run. Calculations of the same jabatan are mutually exclusive, also across
+// API instances; a concurrent one fails instead of waiting.
+func (s *ProfileMatchingService) Calculate(req CalculationRequest) ([]models.ProfileMatchResult, error) {
+	_, results, err := s.calculateRun(req)
+	return results, err
+}
 
-		// Create result
-		result := models.ProfileMatchResult{
-			TenagaKerjaID:   tenagaKerjaID,
-			JabatanID:       req.JabatanID,
-			TotalScore:      totalScore,
-			CoreFactor:      coreFactor,
-			SecondaryFactor: secondaryFactor,
+// calculateRun is Calculate, also returning the run recorded
+func (s *ProfileMatchingService) calculateRun(req CalculationRequest) (*models.CalculationRun, []models.ProfileMatchResult, error) {
+	var run *models.CalculationRun
+	var results []models.ProfileMatchResult
+	err := s.calculationRunRepo.WithJabatanLock(req.JabatanID, func() error {
+		var err error
+		run, results, err = s.calculate(req)
+		return err
+	})
+	if err == repositories.ErrLockNotAcquired {
+		return nil, nil, errors.New("calculation already running for this jabatan")
+	}
+	return run, results, err
+}
+
+func (s *ProfileMatchingService) calculate(req CalculationRequest) (*models.CalculationRun, []models.ProfileMatchResult, error) {
+	calc, err := s.prepareCalculation(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, _, ineligible := calc.rank()
+
+	// Record the run; earlier runs and their results are kept
+	run := &models.CalculationRun{
+		JabatanID:  req.JabatanID,
+		UserID:     req.UserID,
+		Parameters: calc.parameters(req.TenagaKerjaIDs),
+		Status:     "running",
+	}
+	if err := s.calculationRunRepo.WithActor(req.UserID).Create(run); err != nil {
+		return nil, nil, errors.New("could not record calculation run")
+	}
+	for i := range results {
+		results[i].RunID = &run.ID
+	}
+	for i := range ineligible {
+		ineligible[i].RunID = run.ID
+	}
+	history, err := s.proposeStatuses(calc.jabatan, results, run.ID, req.UserID)
+	if err != nil {
+		return nil, nil, s.failRun(run.ID, err, err)
+	}
+
+	// Save the results and complete the run atomically; the previous
+	// ranking stays current if this fails
+	if err := s.calculationRunRepo.Complete(run.ID, results, history, ineligible); err != nil {
+		return nil, nil, s.failRun(run.ID, err, errors.New("could not save calculation results"))
+	}
+	run.Status = "completed"
+
+	return run, results, nil
+}
+
+// failRun marks the run failed by cause and returns result. When the run
+// cannot be marked, which would leave it running, that error is added to
+// result.
+func (s *ProfileMatchingService) failRun(id uint, cause, result error) error {
+	if err := s.calculationRunRepo.UpdateStatus(id, "failed", cause.Error()); err != nil {
+		return fmt.Errorf("%w; could not mark calculation run %d failed: %v", result, id, err)
+	}
+	return result
+}
+
+// proposeStatuses gives each result the latest status a user gave its
+// tenaga kerja for the jabatan, and shortlists the other complete results
+// ranked within the jabatan's vacancies. The shortlists are returned as
+// history entries.
+func (s *ProfileMatchingService) proposeStatuses(jabatan *models.Jabatan, results []models.ProfileMatchResult, runID uint, userID *uint) ([]models.ResultStatusHistory, error) {
+	manual, err := s.resultStatusHistoryRepo.GetLatestManual(jabatan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []models.ResultStatusHistory
+	for i := range results {
+		result := &results[i]
+		if h, ok := manual[result.TenagaKerjaID]; ok {
+			result.Status = h.Status
+			continue
+		}
+		result.Status = "candidate"
+		if result.Rank <= jabatan.JumlahLowongan && !result.Incomplete {
+			result.Status = "shortlisted"
+			history = append(history, models.ResultStatusHistory{
+				JabatanID:     jabatan.ID,
+				TenagaKerjaID: result.TenagaKerjaID,
+				RunID:         &runID,
+				Status:        result.Status,
+				Auto:          true,
+				UserID:        userID,
+			})
 		}
+	}
+	return history, nil
+}
 
-		results = append(results, result)
+// parameters returns the settings of the calculation recorded on its run
+func (calc *calculation) parameters(tenagaKerjaIDs []uint) models.CalculationParameters {
+	return models.CalculationParameters{
+		TenagaKerjaIDs:        tenagaKerjaIDs,
+		GapWeightTableID:      calc.jabatan.GapWeightTableID,
+		GapMode:               calc.mode.Mode,
+		RoundingRule:          calc.mode.RoundingRule,
+		MissingPolicy:         calc.policy.Policy,
+		MinimumNilai:          calc.policy.MinimumNilai,
+		Method:                calc.method,
+		RankingStyle:          calc.ranking.Style,
+		TieBreakers:           calc.ranking.TieBreakers,
+		CoreFactorPersen:      calc.ratio.Core,
+		SecondaryFactorPersen: calc.ratio.Secondary,
+	}
+}
+
+// NilaiOverride replaces a tenaga kerja's nilai for a kriteria in a simulation
+type NilaiOverride struct {
+	TenagaKerjaID uint
+	KriteriaID    uint
+	Nilai         float64
+}
+
+// SimulationRequest is a calculation with in-memory what-if overrides. Target
+// and core overrides are keyed by kriteria ID.
+type SimulationRequest struct {
+	CalculationRequest
+	NilaiOverrides  []NilaiOverride
+	TargetOverrides map[uint]float64
+	CoreOverrides   map[uint]bool
+}
+
+// Simulate ranks like Calculate after applying the overrides, without
+// touching the stored results. It returns the results in rank order with the
+// per-aspek breakdown of each.
+func (s *ProfileMatchingService) Simulate(req SimulationRequest) ([]models.ProfileMatchResult, []map[string]interface{}, error) {
+	calc, err := s.prepareCalculation(req.CalculationRequest)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Delete existing results for the same jabatan
-	if err := s.profileMatchResultRepo.DeleteByJabatanID(req.JabatanID); err != nil {
-		return nil, errors.New("could not clear old results")
+	targetIndex := make(map[uint]int)
+	for i, t := range calc.profile.Targets {
+		if _, exists := calc.profile.Kriteria[t.KriteriaID]; exists {
+			targetIndex[t.KriteriaID] = i
+		}
 	}
 
-	// Save all results to database
-	if len(results) > 0 {
-		if err := s.profileMatchResultRepo.CreateBatch(results); err != nil {
-			return nil, errors.New("could not save calculation results")
+	for kriteriaID, nilai := range req.TargetOverrides {
+		i, exists := targetIndex[kriteriaID]
+		if !exists {
+			return nil, nil, errors.New("kriteria not in target profile")
 		}
+		calc.profile.Targets[i].Nilai = nilai
 	}
 
-	return results, nil
+	for kriteriaID, isCore := range req.CoreOverrides {
+		if _, exists := targetIndex[kriteriaID]; !exists {
+			return nil, nil, errors.New("kriteria not in target profile")
+		}
+		kriteria := calc.profile.Kriteria[kriteriaID]
+		kriteria.IsCore = isCore
+		calc.profile.Kriteria[kriteriaID] = kriteria
+	}
+
+	candidateIndex := make(map[uint]int)
+	for i, c := range calc.candidates {
+		candidateIndex[c.ID] = i
+	}
+	for _, o := range req.NilaiOverrides {
+		if _, exists := targetIndex[o.KriteriaID]; !exists {
+			return nil, nil, errors.New("kriteria not in target profile")
+		}
+		i, exists := candidateIndex[o.TenagaKerjaID]
+		if !exists {
+			return nil, nil, errors.New("tenaga kerja not in calculation")
+		}
+		calc.candidates[i].Nilai[o.KriteriaID] = o.Nilai
+	}
+
+	results, evaluations, _ := calc.rank()
+	details := make([]map[string]interface{}, len(results))
+	for i := range results {
+		results[i].TenagaKerja = calc.tenagaKerja[results[i].TenagaKerjaID]
+		results[i].Jabatan = *calc.jabatan
+		details[i] = evaluationDetails(evaluations[i])
+	}
+
+	return results, details, nil
 }
 
+// GetAllResults returns the results of the current run of every jabatan
 func (s *ProfileMatchingService) GetAllResults() ([]models.ProfileMatchResult, error) {
-	return s.profileMatchResultRepo.GetAllWithRelations()
+	return s.GetResultsByJabatanID(0)
 }
 
+// GetResultsByJabatanID returns the results of the jabatan's current run:
+// the official run, or the latest one when none is official
 func (s *ProfileMatchingService) GetResultsByJabatanID(jabatanID uint) ([]models.ProfileMatchResult, error) {
-	return s.profileMatchResultRepo.GetByJabatanID(jabatanID)
+	runIDs, err := s.calculationRunRepo.GetCurrentIDs()
+	if err != nil {
+		return nil, err
+	}
+	return s.profileMatchResultRepo.GetCurrent(runIDs, jabatanID)
+}
+
+// ExportResults returns the current results of a jabatan in rank order for
+// export, with their per-kriteria breakdown when withGaps is set
+func (s *ProfileMatchingService) ExportResults(jabatanID uint, withGaps bool) ([]models.ProfileMatchResult, error) {
+	if _, err := s.jabatanRepo.GetByID(jabatanID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("jabatan not found")
+		}
+		return nil, err
+	}
+	runIDs, err := s.calculationRunRepo.GetCurrentIDs()
+	if err != nil {
+		return nil, err
+	}
+	if withGaps {
+		return s.profileMatchResultRepo.GetCurrentWithSnapshot(runIDs, jabatanID)
+	}
+	return s.profileMatchResultRepo.GetCurrent(runIDs, jabatanID)
+}
+
+// DecisionReport is what the printable decision report of a jabatan shows
+type DecisionReport struct {
+	Jabatan models.Jabatan
+	// Run is the current run; nil for results stored before runs were recorded
+	Run *models.CalculationRun
+	// TargetProfiles carry their kriteria and aspek, ordered by aspek and kode
+	TargetProfiles []models.TargetProfile
+	Results        []models.ProfileMatchResult
+	// Details holds the breakdown of each result, as in GetResultDetailByID
+	Details []map[string]interface{}
+}
+
+// DecisionReport gathers the target profile, current ranking and per-result
+// breakdown of a jabatan
+func (s *ProfileMatchingService) DecisionReport(jabatanID uint) (*DecisionReport, error) {
+	jabatan, err := s.jabatanRepo.GetByID(jabatanID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("jabatan not found")
+		}
+		return nil, err
+	}
+	runIDs, err := s.calculationRunRepo.GetCurrentIDs()
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.profileMatchResultRepo.GetCurrentWithSnapshot(runIDs, jabatanID)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, errors.New("jabatan has no calculation results")
+	}
+	report := &DecisionReport{Jabatan: *jabatan, Results: results, Details: make([]map[string]interface{}, len(results))}
+
+	if results[0].RunID != nil {
+		if report.Run, err = s.calculationRunRepo.GetByID(*results[0].RunID); err != nil {
+			return nil, err
+		}
+	}
+
+	targetProfiles, err := s.targetProfileRepo.GetByJabatanID(jabatanID)
+	if err != nil {
+		return nil, errors.New("could not fetch target profiles")
+	}
+	kriterias, err := s.kriteriaRepo.GetAllWithAspek()
+	if err != nil {
+		return nil, errors.New("could not fetch kriteria")
+	}
+	kriteriaByID := make(map[uint]models.Kriteria, len(kriterias))
+	for _, k := range kriterias {
+		kriteriaByID[k.ID] = k
+	}
+	for i := range targetProfiles {
+		targetProfiles[i].Kriteria = kriteriaByID[targetProfiles[i].KriteriaID]
+	}
+	sort.SliceStable(targetProfiles, func(i, j int) bool {
+		a, b := targetProfiles[i].Kriteria, targetProfiles[j].Kriteria
+		if a.AspekID != b.AspekID {
+			return a.AspekID < b.AspekID
+		}
+		return a.Kode < b.Kode
+	})
+	report.TargetProfiles = targetProfiles
+
+	for i := range results {
+		if report.Details[i], err = s.resultDetails(&results[i]); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// GetIneligibleByRunID returns the tenaga kerja a calculation run knocked
+// out of its ranking
+func (s *ProfileMatchingService) GetIneligibleByRunID(runID uint) ([]models.IneligibleResult, error) {
+	return s.profileMatchResultRepo.GetIneligibleByRunIDs([]uint{runID}, 0)
+}
+
+// GetIneligibleByJabatanID returns the tenaga kerja the current run of the
+// jabatan knocked out of its ranking
+func (s *ProfileMatchingService) GetIneligibleByJabatanID(jabatanID uint) ([]models.IneligibleResult, error) {
+	if _, err := s.jabatanRepo.GetByID(jabatanID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("jabatan not found")
+		}
+		return nil, err
+	}
+	runIDs, err := s.calculationRunRepo.GetCurrentIDs()
+	if err != nil {
+		return nil, err
+	}
+	return s.profileMatchResultRepo.GetIneligibleByRunIDs(runIDs, jabatanID)
+}
+
+// GetRuns returns the calculation runs newest first; a non-zero jabatanID
+// limits them to that jabatan
+func (s *ProfileMatchingService) GetRuns(jabatanID uint) ([]models.CalculationRun, error) {
+	return s.calculationRunRepo.GetAll(jabatanID)
+}
+
+// GetRunByID returns a calculation run with its ranking
+func (s *ProfileMatchingService) GetRunByID(id uint) (*models.CalculationRun, []models.ProfileMatchResult, error) {
+	run, err := s.calculationRunRepo.GetByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.New("calculation run not found")
+		}
+		return nil, nil, err
+	}
+
+	results, err := s.profileMatchResultRepo.GetByRunID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return run, results, nil
+}
+
+// MarkRunOfficial makes the run the official result of its jabatan; userID
+// is the user marking it
+func (s *ProfileMatchingService) MarkRunOfficial(id uint, userID *uint) error {
+	run, err := s.calculationRunRepo.GetByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New("calculation run not found")
+		}
+		return err
+	}
+	if run.Status != "completed" {
+		return errors.New("only completed runs can be official")
+	}
+	return s.calculationRunRepo.WithActor(userID).SetOfficial(run.ID, run.JabatanID)
 }
 
 func (s *ProfileMatchingService) GetResultByID(id uint) (*models.ProfileMatchResult, error) {
@@ -218,160 +687,757 @@ func (s *ProfileMatchingService) GetResultDetailByID(id uint) (*models.ProfileMa
 		}
 		return nil, nil, err
 	}
+	details, err := s.resultDetails(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, details, nil
+}
 
-	// Get target profiles for the jabatan
+// resultDetails returns the per-aspek breakdown of a result
+func (s *ProfileMatchingService) resultDetails(result *models.ProfileMatchResult) (map[string]interface{}, error) {
+	// Serve the breakdown recorded at calculation time
+	if len(result.Aspek) > 0 {
+		return snapshotDetails(result), nil
+	}
+
+	// Results stored before snapshots were recorded are rebuilt from the
+	// current target profiles and nilai
 	targetProfiles, err := s.targetProfileRepo.GetByJabatanID(result.JabatanID)
 	if err != nil {
-		return nil, nil, errors.New("could not fetch target profiles")
+		return nil, errors.New("could not fetch target profiles")
 	}
 
-	// Get all kriteria with aspek preloaded
-	kriterias, err := s.kriteriaRepo.GetAllWithAspek()
+	// Get the GAP weight table in effect for the jabatan
+	jabatan, err := s.jabatanRepo.GetByID(result.JabatanID)
 	if err != nil {
-		return nil, nil, errors.New("could not fetch kriteria")
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("jabatan not found")
+		}
+		return nil, err
+	}
+	table, err := s.gapTableForJabatan(jabatan)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create map of kriteria for easy lookup
-	kriteriaMap := make(map[uint]models.Kriteria)
-	for _, k := range kriterias {
-		kriteriaMap[k.ID] = k
+	// Use the settings recorded on the result so the breakdown matches the stored score
+	mode, err := profilematching.NewGapMode(result.GapMode, result.RoundingRule)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := profilematching.NewMissingPolicy(result.MissingPolicy, &result.MinimumNilai)
+	if err != nil {
+		return nil, err
+	}
+	ratio := profilematching.Ratio{Core: result.CoreFactorPersen, Secondary: result.SecondaryFactorPersen}
+	engine := profilematching.New(profilematching.NewGapWeighter(table, mode), ratio, policy)
+
+	profile, err := s.loadProfile(targetProfiles)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get nilai for this tenaga kerja
 	nilaiList, err := s.nilaiTenagaKerjaRepo.GetByTenagaKerjaID(result.TenagaKerjaID)
 	if err != nil {
-		return nil, nil, errors.New("could not fetch nilai tenaga kerja")
+		return nil, errors.New("could not fetch nilai tenaga kerja")
+	}
+
+	evaluation := engine.Evaluate(profile, nilaiMapFromModels(nilaiList))
+
+	return evaluationDetails(evaluation), nil
+}
+
+// evaluationDetails converts an engine result to the per-aspek breakdown
+// returned by the detail endpoints
+func evaluationDetails(evaluation profilematching.Result) map[string]interface{} {
+	// Group by aspek
+	aspekMap := make(map[string]map[string]interface{})
+	for _, a := range evaluation.Aspek {
+		kriteriaList := make([]map[string]interface{}, 0, len(a.Kriteria))
+		for _, k := range a.Kriteria {
+			kriteriaList = append(kriteriaList, map[string]interface{}{
+				"kode":          k.Kriteria.Kode,
+				"nama":          k.Kriteria.Nama,
+				"target":        k.Target,
+				"actual":        k.Actual,
+				"gap":           k.Gap,
+				"effective_gap": k.EffectiveGap,
+				"bobot_nilai":   k.Weight,
+				"is_core":       k.Kriteria.IsCore,
+				"bobot":         k.Kriteria.Bobot,
+				"kontribusi":    k.Kontribusi,
+				"imputed":       k.Imputed,
+			})
+		}
+
+		aspekMap[a.Aspek.Nama] = map[string]interface{}{
+			"persentase":              a.Aspek.Persentase,
+			"kriteria":                kriteriaList,
+			"cf":                      a.CF,
+			"sf":                      a.SF,
+			"score":                   a.Score,
+			"kontribusi":              a.Kontribusi,
+			"core_factor_persen":      a.Ratio.Core,
+			"secondary_factor_persen": a.Ratio.Secondary,
+		}
+	}
+
+	return map[string]interface{}{
+		"aspek":            aspekMap,
+		"total_score":      evaluation.TotalScore,
+		"missing_kriteria": evaluation.Missing,
+	}
+}
+
+// snapshotFromEvaluation records an engine result's per-aspek and
+// per-kriteria breakdown for storage with the result
+func snapshotFromEvaluation(evaluation profilematching.Result) []models.ProfileMatchResultAspek {
+	snapshot := make([]models.ProfileMatchResultAspek, 0, len(evaluation.Aspek))
+	for _, a := range evaluation.Aspek {
+		aspek := models.ProfileMatchResultAspek{
+			AspekID:               a.Aspek.ID,
+			Nama:                  a.Aspek.Nama,
+			Persentase:            a.Aspek.Persentase,
+			CoreFactorPersen:      a.Ratio.Core,
+			SecondaryFactorPersen: a.Ratio.Secondary,
+			CF:                    a.CF,
+			SF:                    a.SF,
+			Score:                 a.Score,
+			Kontribusi:            a.Kontribusi,
+			Kriteria:              make([]models.ProfileMatchResultKriteria, 0, len(a.Kriteria)),
+		}
+		for _, k := range a.Kriteria {
+			aspek.Kriteria = append(aspek.Kriteria, models.ProfileMatchResultKriteria{
+				KriteriaID:   k.Kriteria.ID,
+				Kode:         k.Kriteria.Kode,
+				Nama:         k.Kriteria.Nama,
+				IsCore:       k.Kriteria.IsCore,
+				Bobot:        k.Kriteria.Bobot,
+				Target:       k.Target,
+				Actual:       k.Actual,
+				Gap:          k.Gap,
+				EffectiveGap: k.EffectiveGap,
+				BobotNilai:   k.Weight,
+				Kontribusi:   k.Kontribusi,
+				Imputed:      k.Imputed,
+			})
+		}
+		snapshot = append(snapshot, aspek)
+	}
+	return snapshot
+}
+
+// snapshotDetails converts a stored snapshot to the breakdown returned by
+// the detail endpoints, in the same shape as evaluationDetails
+func snapshotDetails(result *models.ProfileMatchResult) map[string]interface{} {
+	aspekMap := make(map[string]map[string]interface{})
+	var totalScore float64
+	for _, a := range result.Aspek {
+		kriteriaList := make([]map[string]interface{}, 0, len(a.Kriteria))
+		for _, k := range a.Kriteria {
+			kriteriaList = append(kriteriaList, map[string]interface{}{
+				"kode":          k.Kode,
+				"nama":          k.Nama,
+				"target":        k.Target,
+				"actual":        k.Actual,
+				"gap":           k.Gap,
+				"effective_gap": k.EffectiveGap,
+				"bobot_nilai":   k.BobotNilai,
+				"is_core":       k.IsCore,
+				"bobot":         k.Bobot,
+				"kontribusi":    k.Kontribusi,
+				"imputed":       k.Imputed,
+			})
+		}
+
+		aspekMap[a.Nama] = map[string]interface{}{
+			"persentase":              a.Persentase,
+			"kriteria":                kriteriaList,
+			"cf":                      a.CF,
+			"sf":                      a.SF,
+			"score":                   a.Score,
+			"kontribusi":              a.Kontribusi,
+			"core_factor_persen":      a.CoreFactorPersen,
+			"secondary_factor_persen": a.SecondaryFactorPersen,
+		}
+		// The profile matching total is the sum of the aspek contributions
+		totalScore += a.Kontribusi
+	}
+
+	return map[string]interface{}{
+		"aspek":            aspekMap,
+		"total_score":      totalScore,
+		"missing_kriteria": result.MissingKriteria,
+	}
+}
+
+// loadProfile converts the target profiles of a jabatan, with the kriteria
+// and aspek they reference, to the engine's plain profile
+func (s *ProfileMatchingService) loadProfile(targetProfiles []models.TargetProfile) (profilematching.Profile, error) {
+	// Get all kriteria with aspek preloaded
+	kriterias, err := s.kriteriaRepo.GetAllWithAspek()
+	if err != nil {
+		return profilematching.Profile{}, errors.New("could not fetch kriteria")
+	}
+	return profileFromModels(targetProfiles, kriterias), nil
+}
+
+func profileFromModels(targetProfiles []models.TargetProfile, kriterias []models.Kriteria) profilematching.Profile {
+	profile := profilematching.Profile{
+		Targets:  make([]profilematching.Target, 0, len(targetProfiles)),
+		Kriteria: make(map[uint]profilematching.Kriteria),
+		Aspek:    make(map[uint]profilematching.Aspek),
+	}
+	for _, t := range targetProfiles {
+		profile.Targets = append(profile.Targets, profilematching.Target{KriteriaID: t.KriteriaID, Nilai: t.TargetNilai, Minimum: t.NilaiMinimum})
+	}
+	for _, k := range kriterias {
+		profile.Kriteria[k.ID] = profilematching.Kriteria{
+			ID:      k.ID,
+			AspekID: k.AspekID,
+			Kode:    k.Kode,
+			Nama:    k.Nama,
+			IsCore:  k.IsCore,
+			Bobot:   k.Bobot,
+		}
+		profile.Aspek[k.AspekID] = aspekFromModel(k.Aspek)
+	}
+	return profile
+}
+
+func aspekFromModel(aspek models.Aspek) profilematching.Aspek {
+	a := profilematching.Aspek{ID: aspek.ID, Nama: aspek.Nama, Persentase: aspek.Persentase}
+	// The aspek's own ratio overrides the jabatan's
+	if aspek.CoreFactorPersen != nil && aspek.SecondaryFactorPersen != nil {
+		a.Ratio = &profilematching.Ratio{Core: *aspek.CoreFactorPersen, Secondary: *aspek.SecondaryFactorPersen}
 	}
+	return a
+}
 
-	// Create map of nilai for easy lookup
+func nilaiMapFromModels(nilaiList []models.NilaiTenagaKerja) map[uint]float64 {
 	nilaiMap := make(map[uint]float64)
 	for _, n := range nilaiList {
 		nilaiMap[n.KriteriaID] = n.Nilai
 	}
+	return nilaiMap
+}
 
-	// Group by aspek
-	aspekMap := make(map[string]map[string]interface{})
-	
-	for _, target := range targetProfiles {
-		kriteria, exists := kriteriaMap[target.KriteriaID]
+// defaultFactorRatio is used when the jabatan does not define a ratio
+var defaultFactorRatio = profilematching.DefaultRatio
+
+// SetDefaultFactorRatio sets the system default core/secondary factor ratio
+func SetDefaultFactorRatio(core, secondary float64) error {
+	ratio := profilematching.Ratio{Core: core, Secondary: secondary}
+	if err := ratio.Validate(); err != nil {
+		return err
+	}
+	defaultFactorRatio = ratio
+	return nil
+}
+
+// validateFactorRatio requires the ratio to be either unset or fully set,
+// non-negative and summing to 100%.
+func validateFactorRatio(core, secondary *float64) error {
+	if core == nil && secondary == nil {
+		return nil
+	}
+	if core == nil || secondary == nil {
+		return errors.New("invalid factor ratio: core and secondary must both be set and sum to 100")
+	}
+	return profilematching.Ratio{Core: *core, Secondary: *secondary}.Validate()
+}
+
+func factorRatioForJabatan(jabatan *models.Jabatan) profilematching.Ratio {
+	if jabatan.CoreFactorPersen == nil || jabatan.SecondaryFactorPersen == nil {
+		return defaultFactorRatio
+	}
+	return profilematching.Ratio{Core: *jabatan.CoreFactorPersen, Secondary: *jabatan.SecondaryFactorPersen}
+}
+
+// gapTableFromModel converts a GAP weight table; nil gives the standard
+// conversion
+func gapTableFromModel(table *models.GapWeightTable) profilematching.GapTable {
+	if table == nil || len(table.Entries) == 0 {
+		return profilematching.DefaultGapTable()
+	}
+	t := profilematching.GapTable{BelowRange: table.BelowRange, AboveRange: table.AboveRange}
+	for _, e := range table.Entries {
+		t.Entries = append(t.Entries, profilematching.GapEntry{Gap: e.Gap, Bobot: e.Bobot})
+	}
+	return t
+}
+
+// gapTableForJabatan returns the jabatan's GAP weight table, falling back to
+// the standard conversion when none is assigned.
+func (s *ProfileMatchingService) gapTableForJabatan(jabatan *models.Jabatan) (profilematching.GapTable, error) {
+	if jabatan.GapWeightTableID == nil {
+		return gapTableFromModel(nil), nil
+	}
+
+	table, err := s.gapWeightTableRepo.GetByID(*jabatan.GapWeightTableID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return profilematching.GapTable{}, errors.New("gap weight table not found")
+		}
+		return profilematching.GapTable{}, err
+	}
+	return gapTableFromModel(table), nil
+}
+
+type SensitivityRequest struct {
+	CalculationRequest
+	TopN int
+	// Perturbation ranges keyed by aspek ID and kriteria ID
+	AspekPersentase  map[uint]profilematching.Range
+	CoreFactorPersen *profilematching.Range
+	TargetNilai      map[uint]profilematching.Range
+}
+
+// Sensitivity recomputes the ranking of a jabatan over the perturbation
+// ranges. Nothing is stored; the tenaga kerja ranked are returned by ID.
+// Without a ranking rule in the request, ranks are numbered by the rule of
+// the jabatan's current run so they match its stored ranking.
+func (s *ProfileMatchingService) Sensitivity(req SensitivityRequest) (*profilematching.SensitivityReport, map[uint]models.TenagaKerja, error) {
+	if req.RankingStyle == "" && len(req.TieBreakers) == 0 {
+		run, err := s.calculationRunRepo.GetCurrent(req.JabatanID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, nil, err
+		}
+		if run != nil {
+			req.RankingStyle = run.Parameters.RankingStyle
+			req.TieBreakers = run.Parameters.TieBreakers
+		}
+	}
+
+	calc, err := s.prepareCalculation(req.CalculationRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Only inputs used by the target profile can be perturbed
+	targets := make(map[uint]bool)
+	aspek := make(map[uint]bool)
+	for _, t := range calc.profile.Targets {
+		if k, exists := calc.profile.Kriteria[t.KriteriaID]; exists {
+			targets[t.KriteriaID] = true
+			aspek[k.AspekID] = true
+		}
+	}
+	for id := range req.AspekPersentase {
+		if !aspek[id] {
+			return nil, nil, errors.New("aspek not in target profile")
+		}
+	}
+	for id := range req.TargetNilai {
+		if !targets[id] {
+			return nil, nil, errors.New("kriteria not in target profile")
+		}
+	}
+
+	report, err := calc.engine.Sensitivity(calc.profile, calc.candidates, profilematching.Sensitivity{
+		AspekPersentase:  req.AspekPersentase,
+		CoreFactorPersen: req.CoreFactorPersen,
+		TargetNilai:      req.TargetNilai,
+		TopN:             req.TopN,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &report, calc.tenagaKerja, nil
+}
+
+// CompareRequest compares the ranking of BaseRunID with that of CompareRunID
+// or, when it is nil, with a simulation on the current target profiles and
+// nilai. Simulation settings left empty are taken from the base run.
+type CompareRequest struct {
+	BaseRunID    uint
+	CompareRunID *uint
+	Simulation   *SimulationRequest
+}
+
+// KriteriaGapChange is a kriteria whose gap differs between the two results;
+// a nil gap means the kriteria was not evaluated on that side
+type KriteriaGapChange struct {
+	KriteriaID uint
+	Kode       string
+	Nama       string
+	OldGap     *float64
+	NewGap     *float64
+}
+
+// RankMovement is a tenaga kerja's position in both rankings. Ranks and
+// scores are 0 on the side the tenaga kerja is absent from.
+type RankMovement struct {
+	TenagaKerjaID uint
+	TenagaKerja   models.TenagaKerja
+	OldRank       int
+	NewRank       int
+	// RankChange is positive when the tenaga kerja moved up
+	RankChange      int
+	OldScore        float64
+	NewScore        float64
+	ScoreDelta      float64
+	KriteriaChanges []KriteriaGapChange
+}
+
+type Comparison struct {
+	JabatanID    uint
+	BaseRunID    uint
+	CompareRunID *uint
+	Movements    []RankMovement
+}
+
+// Compare reports the rank movements between a stored run and another run of
+// the same jabatan or a fresh simulation
+func (s *ProfileMatchingService) Compare(req CompareRequest) (*Comparison, error) {
+	if (req.CompareRunID == nil) == (req.Simulation == nil) {
+		return nil, errors.New("compare run or simulation is required")
+	}
+
+	baseRun, err := s.calculationRunRepo.GetByID(req.BaseRunID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("calculation run not found")
+		}
+		return nil, err
+	}
+	base, err := s.profileMatchResultRepo.GetByRunIDWithSnapshot(baseRun.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var other []models.ProfileMatchResult
+	if req.CompareRunID != nil {
+		compareRun, err := s.calculationRunRepo.GetByID(*req.CompareRunID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.New("calculation run not found")
+			}
+			return nil, err
+		}
+		if compareRun.JabatanID != baseRun.JabatanID {
+			return nil, errors.New("runs belong to different jabatan")
+		}
+		other, err = s.profileMatchResultRepo.GetByRunIDWithSnapshot(compareRun.ID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		simulation := *req.Simulation
+		simulation.CalculationRequest = simulationDefaults(simulation.CalculationRequest, baseRun)
+		other, _, err = s.Simulate(simulation)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Comparison{
+		JabatanID:    baseRun.JabatanID,
+		BaseRunID:    baseRun.ID,
+		CompareRunID: req.CompareRunID,
+		Movements:    compareResults(base, other),
+	}, nil
+}
+
+// simulationDefaults fills the settings a simulation leaves empty with those
+// recorded on the run it is compared against
+func simulationDefaults(req CalculationRequest, run *models.CalculationRun) CalculationRequest {
+	req.JabatanID = run.JabatanID
+	if len(req.TenagaKerjaIDs) == 0 {
+		req.TenagaKerjaIDs = run.Parameters.TenagaKerjaIDs
+	}
+	if req.GapMode == "" {
+		req.GapMode = run.Parameters.GapMode
+		if req.RoundingRule == "" {
+			req.RoundingRule = run.Parameters.RoundingRule
+		}
+	}
+	if req.MissingPolicy == "" {
+		req.MissingPolicy = run.Parameters.MissingPolicy
+		if req.MinimumNilai == nil && req.MissingPolicy == "minimum" {
+			minimumNilai := run.Parameters.MinimumNilai
+			req.MinimumNilai = &minimumNilai
+		}
+	}
+	if req.Method == "" {
+		req.Method = run.Parameters.Method
+	}
+	if req.RankingStyle == "" && len(req.TieBreakers) == 0 {
+		req.RankingStyle = run.Parameters.RankingStyle
+		req.TieBreakers = run.Parameters.TieBreakers
+	}
+	return req
+}
+
+// compareResults matches two rankings, each in rank order, by tenaga kerja.
+// Movements are ordered by new rank, with tenaga kerja missing from the new
+// ranking last.
+func compareResults(base, other []models.ProfileMatchResult) []RankMovement {
+	index := make(map[uint]int)
+	var movements []RankMovement
+	movement := func(result models.ProfileMatchResult) *RankMovement {
+		i, exists := index[result.TenagaKerjaID]
 		if !exists {
-			continue
+			i = len(movements)
+			index[result.TenagaKerjaID] = i
+			movements = append(movements, RankMovement{TenagaKerjaID: result.TenagaKerjaID})
+		}
+		m := &movements[i]
+		if result.TenagaKerja.ID != 0 {
+			m.TenagaKerja = result.TenagaKerja
+		}
+		return m
+	}
+
+	oldGaps := make(map[uint][]models.ProfileMatchResultKriteria)
+	newGaps := make(map[uint][]models.ProfileMatchResultKriteria)
+	for i, result := range base {
+		m := movement(result)
+		m.OldRank, m.OldScore = resultRank(result, i), result.TotalScore
+		oldGaps[result.TenagaKerjaID] = snapshotKriteria(result)
+	}
+	for i, result := range other {
+		m := movement(result)
+		m.NewRank, m.NewScore = resultRank(result, i), result.TotalScore
+		newGaps[result.TenagaKerjaID] = snapshotKriteria(result)
+	}
+
+	for i := range movements {
+		m := &movements[i]
+		m.ScoreDelta = m.NewScore - m.OldScore
+		if m.OldRank != 0 && m.NewRank != 0 {
+			m.RankChange = m.OldRank - m.NewRank
 		}
+		m.KriteriaChanges = gapChanges(oldGaps[m.TenagaKerjaID], newGaps[m.TenagaKerjaID])
+	}
 
-		// Load aspek if not loaded
-		if kriteria.Aspek.ID == 0 {
-			// Kriteria might not have aspek preloaded, skip for now
-			continue
+	sort.SliceStable(movements, func(i, j int) bool {
+		a, b := movements[i], movements[j]
+		if (a.NewRank == 0) != (b.NewRank == 0) {
+			return b.NewRank == 0
 		}
+		if a.NewRank != b.NewRank {
+			return a.NewRank < b.NewRank
+		}
+		return a.OldRank < b.OldRank
+	})
+	return movements
+}
+
+// resultRank returns the stored rank of a result, or its position in the
+// ranking for results stored before ranks were recorded
+func resultRank(result models.ProfileMatchResult, index int) int {
+	if result.Rank > 0 {
+		return result.Rank
+	}
+	return index + 1
+}
+
+func snapshotKriteria(result models.ProfileMatchResult) []models.ProfileMatchResultKriteria {
+	var kriteria []models.ProfileMatchResultKriteria
+	for _, a := range result.Aspek {
+		kriteria = append(kriteria, a.Kriteria...)
+	}
+	return kriteria
+}
 
-		nilai, exists := nilaiMap[target.KriteriaID]
+// gapChanges lists the kriteria whose gap differs between two snapshots, in
+// the order they appear in the old then the new snapshot
+func gapChanges(before, after []models.ProfileMatchResultKriteria) []KriteriaGapChange {
+	var changes []KriteriaGapChange
+	index := make(map[uint]int)
+	for _, k := range before {
+		gap := k.Gap
+		index[k.KriteriaID] = len(changes)
+		changes = append(changes, KriteriaGapChange{KriteriaID: k.KriteriaID, Kode: k.Kode, Nama: k.Nama, OldGap: &gap})
+	}
+	for _, k := range after {
+		gap := k.Gap
+		i, exists := index[k.KriteriaID]
 		if !exists {
-			continue // Skip if no nilai for this kriteria
-		}
-
-		aspekNama := kriteria.Aspek.Nama
-		
-		// Initialize aspek if not exists
-		if _, exists := aspekMap[aspekNama]; !exists {
-			aspekMap[aspekNama] = make(map[string]interface{})
-			aspekMap[aspekNama]["persentase"] = kriteria.Aspek.Persentase
-			aspekMap[aspekNama]["kriteria"] = []map[string]interface{}{}
-			aspekMap[aspekNama]["core_weights"] = []float64{}
-			aspekMap[aspekNama]["secondary_weights"] = []float64{}
-		}
-
-		// Calculate GAP
-		gap := nilai - target.TargetNilai
-		weight := calculateWeight(gap)
-
-		// Add to kriteria list
-		kriteriaList := aspekMap[aspekNama]["kriteria"].([]map[string]interface{})
-		kriteriaDetail := map[string]interface{}{
-			"kode":        kriteria.Kode,
-			"nama":        kriteria.Nama,
-			"target":      target.TargetNilai,
-			"actual":      nilai,
-			"gap":         gap,
-			"bobot_nilai": weight,
-			"is_core":     kriteria.IsCore,
-		}
-		aspekMap[aspekNama]["kriteria"] = append(kriteriaList, kriteriaDetail)
-
-		// Add weight to appropriate list
-		if kriteria.IsCore {
-			coreWeights := aspekMap[aspekNama]["core_weights"].([]float64)
-			aspekMap[aspekNama]["core_weights"] = append(coreWeights, weight)
-		} else {
-			secondaryWeights := aspekMap[aspekNama]["secondary_weights"].([]float64)
-			aspekMap[aspekNama]["secondary_weights"] = append(secondaryWeights, weight)
-		}
-	}
-
-	// Calculate CF, SF, and Score for each aspek
-	for aspekNama, aspekData := range aspekMap {
-		coreWeights := aspekData["core_weights"].([]float64)
-		secondaryWeights := aspekData["secondary_weights"].([]float64)
-
-		var cf float64
-		var sf float64
-
-		if len(coreWeights) > 0 {
-			var sum float64
-			for _, w := range coreWeights {
-				sum += w
+			i = len(changes)
+			index[k.KriteriaID] = i
+			changes = append(changes, KriteriaGapChange{KriteriaID: k.KriteriaID, Kode: k.Kode, Nama: k.Nama})
+		}
+		changes[i].NewGap = &gap
+	}
+
+	changed := changes[:0]
+	for _, c := range changes {
+		if c.OldGap != nil && c.NewGap != nil && math.Abs(*c.OldGap-*c.NewGap) <= profilematching.Epsilon {
+			continue
+		}
+		changed = append(changed, c)
+	}
+	return changed
+}
+
+type AssignmentRequest struct {
+	Vacancies []profilematching.Vacancy
+	Pinned    []profilematching.Pair
+	Excluded  []profilematching.Pair
+}
+
+// AssignmentPlan is an optimal assignment with the tenaga kerja and jabatan
+// it refers to
+type AssignmentPlan struct {
+	profilematching.AssignmentResult
+	TenagaKerja map[uint]models.TenagaKerja
+	Jabatan     map[uint]models.Jabatan
+}
+
+// Assign fills the openings of several jabatan from their current results so
+// that the total score is maximal and each tenaga kerja fills at most one
+// opening. Incomplete results are not assigned. The scores of different
+// methods are on different scales, so all results must share one method.
+func (s *ProfileMatchingService) Assign(req AssignmentRequest) (*AssignmentPlan, error) {
+	plan := &AssignmentPlan{
+		TenagaKerja: make(map[uint]models.TenagaKerja),
+		Jabatan:     make(map[uint]models.Jabatan),
+	}
+	scores := make(map[profilematching.Pair]float64)
+	method := ""
+	for _, v := range req.Vacancies {
+		jabatan, err := s.jabatanRepo.GetByID(v.JabatanID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.New("jabatan not found")
 			}
-			cf = sum / float64(len(coreWeights))
+			return nil, err
 		}
+		plan.Jabatan[jabatan.ID] = *jabatan
 
-		if len(secondaryWeights) > 0 {
-			var sum float64
-			for _, w := range secondaryWeights {
-				sum += w
+		results, err := s.GetResultsByJabatanID(v.JabatanID)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			return nil, errors.New("jabatan has no calculation results")
+		}
+		for _, result := range results {
+			resultMethod := result.Method
+			if resultMethod == "" {
+				resultMethod = profilematching.MethodProfileMatching
+			}
+			if method == "" {
+				method = resultMethod
+			} else if resultMethod != method {
+				return nil, errors.New("jabatan results use different methods")
+			}
+			if result.Incomplete {
+				continue
 			}
-			sf = sum / float64(len(secondaryWeights))
+			scores[profilematching.Pair{TenagaKerjaID: result.TenagaKerjaID, JabatanID: v.JabatanID}] = result.TotalScore
+			plan.TenagaKerja[result.TenagaKerjaID] = result.TenagaKerja
 		}
+	}
 
-		// Calculate score for this aspek (60% CF + 40% SF)
-		score := (0.6 * cf) + (0.4 * sf)
+	result, err := profilematching.Assign(profilematching.AssignmentInput{
+		Vacancies: req.Vacancies,
+		Scores:    scores,
+		Pinned:    req.Pinned,
+		Excluded:  req.Excluded,
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.AssignmentResult = result
+	return plan, nil
+}
 
-		aspekMap[aspekNama]["cf"] = cf
-		aspekMap[aspekNama]["sf"] = sf
-		aspekMap[aspekNama]["score"] = score
+// resultStatuses are the selection statuses a result can be given
+var resultStatuses = map[string]bool{
+	"candidate":   true,
+	"shortlisted": true,
+	"selected":    true,
+	"rejected":    true,
+	"reserve":     true,
+}
 
-		// Remove temporary fields
-		delete(aspekMap[aspekNama], "core_weights")
-		delete(aspekMap[aspekNama], "secondary_weights")
+// GetCandidates returns the current results of a jabatan in rank order,
+// limited to one status when status is not empty
+func (s *ProfileMatchingService) GetCandidates(jabatanID uint, status string) ([]models.ProfileMatchResult, error) {
+	if status != "" && !resultStatuses[status] {
+		return nil, errors.New("invalid result status")
 	}
+	if _, err := s.jabatanRepo.GetByID(jabatanID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("jabatan not found")
+		}
+		return nil, err
+	}
+
+	results, err := s.GetResultsByJabatanID(jabatanID)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		return results, nil
+	}
+	candidates := []models.ProfileMatchResult{}
+	for _, result := range results {
+		if result.Status == status {
+			candidates = append(candidates, result)
+		}
+	}
+	return candidates, nil
+}
 
-	details := map[string]interface{}{
-		"aspek": aspekMap,
+// UpdateResultStatus gives a current result a selection status and records
+// it in the status history. No more results can be selected than the
+// jabatan has vacancies, when it has any set.
+func (s *ProfileMatchingService) UpdateResultStatus(id uint, status, note string, userID *uint) (*models.ProfileMatchResult, error) {
+	if !resultStatuses[status] {
+		return nil, errors.New("invalid result status")
+	}
+	result, err := s.GetResultByID(id)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, details, nil
+	current, err := s.GetResultsByJabatanID(result.JabatanID)
+	if err != nil {
+		return nil, err
+	}
+	isCurrent, selected := false, 0
+	for _, r := range current {
+		if r.ID == result.ID {
+			isCurrent = true
+		} else if r.Status == "selected" {
+			selected++
+		}
+	}
+	if !isCurrent {
+		return nil, errors.New("only current results can change status")
+	}
+	if status == "selected" && result.Jabatan.JumlahLowongan > 0 && selected >= result.Jabatan.JumlahLowongan {
+		return nil, errors.New("jabatan has no remaining vacancies")
+	}
+
+	entry := &models.ResultStatusHistory{
+		JabatanID:            result.JabatanID,
+		TenagaKerjaID:        result.TenagaKerjaID,
+		ProfileMatchResultID: result.ID,
+		RunID:                result.RunID,
+		Status:               status,
+		UserID:               userID,
+		Note:                 note,
+	}
+	if err := s.resultStatusHistoryRepo.WithActor(userID).ChangeStatus(entry); err != nil {
+		return nil, err
+	}
+	result.Status = status
+	return result, nil
 }
 
-// calculateWeight converts GAP to weight according to profile matching rules
-func calculateWeight(gap float64) float64 {
-	switch gap {
-	case 0:
-		return 5 // No difference
-	case 1:
-		return 4.5 // Competency excess 1 level
-	case -1:
-		return 4 // Competency lack 1 level
-	case 2:
-		return 3.5 // Competency excess 2 levels
-	case -2:
-		return 3 // Competency lack 2 levels
-	case 3:
-		return 2.5 // Competency excess 3 levels
-	case -3:
-		return 2 // Competency lack 3 levels
-	case 4:
-		return 1.5 // Competency excess 4 levels
-	case -4:
-		return 1 // Competency lack 4 levels
-	default:
-		return 0 // Gap too large
+// GetStatusHistory returns the statuses given to the result's tenaga kerja
+// for its jabatan across every run, newest first
+func (s *ProfileMatchingService) GetStatusHistory(resultID uint) ([]models.ResultStatusHistory, error) {
+	result, err := s.GetResultByID(resultID)
+	if err != nil {
+		return nil, err
 	}
+	return s.resultStatusHistoryRepo.GetByPair(result.JabatanID, result.TenagaKerjaID)
 }
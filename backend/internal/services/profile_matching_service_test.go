@@ -1,12 +1,14 @@
 package services
 
 import (
+	"fmt"
 	"testing"
 
 	"backend/internal/models"
 	"backend/internal/repositories"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestProfileMatchingService_Calculate(t *testing.T) {
@@ -19,17 +21,9 @@ func TestProfileMatchingService_Calculate(t *testing.T) {
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
 	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
-	profileMatchResultRepo := repositories.NewProfileMatchResultRepository(db)
 
 	// Setup service
-	service := NewProfileMatchingService(
-		targetProfileRepo,
-		kriteriaRepo,
-		nilaiTenagaKerjaRepo,
-		tenagaKerjaRepo,
-		profileMatchResultRepo,
-		jabatanRepo,
-	)
+	service := newTestProfileMatchingService(db)
 
 	// Create test data
 	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
@@ -78,17 +72,9 @@ func TestProfileMatchingService_Calculate_NoTenagaKerjaIDs(t *testing.T) {
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
 	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
-	profileMatchResultRepo := repositories.NewProfileMatchResultRepository(db)
 
 	// Setup service
-	service := NewProfileMatchingService(
-		targetProfileRepo,
-		kriteriaRepo,
-		nilaiTenagaKerjaRepo,
-		tenagaKerjaRepo,
-		profileMatchResultRepo,
-		jabatanRepo,
-	)
+	service := newTestProfileMatchingService(db)
 
 	// Create test data
 	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
@@ -124,22 +110,9 @@ func TestProfileMatchingService_Calculate_InvalidJabatan(t *testing.T) {
 	db := setupServiceTestDB(t)
 
 	// Setup repositories
-	jabatanRepo := repositories.NewJabatanRepository(db)
-	kriteriaRepo := repositories.NewKriteriaRepository(db)
-	targetProfileRepo := repositories.NewTargetProfileRepository(db)
-	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
-	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
-	profileMatchResultRepo := repositories.NewProfileMatchResultRepository(db)
 
 	// Setup service
-	service := NewProfileMatchingService(
-		targetProfileRepo,
-		kriteriaRepo,
-		nilaiTenagaKerjaRepo,
-		tenagaKerjaRepo,
-		profileMatchResultRepo,
-		jabatanRepo,
-	)
+	service := newTestProfileMatchingService(db)
 
 	req := CalculationRequest{
 		JabatanID:      999, // Non-existent jabatan
@@ -155,22 +128,9 @@ func TestProfileMatchingService_GetAllResults(t *testing.T) {
 	db := setupServiceTestDB(t)
 
 	// Setup repositories
-	jabatanRepo := repositories.NewJabatanRepository(db)
-	kriteriaRepo := repositories.NewKriteriaRepository(db)
-	targetProfileRepo := repositories.NewTargetProfileRepository(db)
-	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
-	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
-	profileMatchResultRepo := repositories.NewProfileMatchResultRepository(db)
 
 	// Setup service
-	service := NewProfileMatchingService(
-		targetProfileRepo,
-		kriteriaRepo,
-		nilaiTenagaKerjaRepo,
-		tenagaKerjaRepo,
-		profileMatchResultRepo,
-		jabatanRepo,
-	)
+	service := newTestProfileMatchingService(db)
 
 	results, err := service.GetAllResults()
 	assert.NoError(t, err)
@@ -182,21 +142,11 @@ func TestProfileMatchingService_GetResultByID(t *testing.T) {
 
 	// Setup repositories
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	kriteriaRepo := repositories.NewKriteriaRepository(db)
-	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
-	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
 	profileMatchResultRepo := repositories.NewProfileMatchResultRepository(db)
 
 	// Setup service
-	service := NewProfileMatchingService(
-		targetProfileRepo,
-		kriteriaRepo,
-		nilaiTenagaKerjaRepo,
-		tenagaKerjaRepo,
-		profileMatchResultRepo,
-		jabatanRepo,
-	)
+	service := newTestProfileMatchingService(db)
 
 	// Create a result
 	jabatan := &models.Jabatan{Nama: "Manager"}
@@ -219,3 +169,410 @@ func TestProfileMatchingService_GetResultByID(t *testing.T) {
 	assert.Equal(t, result.TotalScore, found.TotalScore)
 }
 
+func newTestProfileMatchingService(db *gorm.DB) *ProfileMatchingService {
+	return NewProfileMatchingService(
+		repositories.NewTargetProfileRepository(db),
+		repositories.NewKriteriaRepository(db),
+		repositories.NewNilaiTenagaKerjaRepository(db),
+		repositories.NewTenagaKerjaRepository(db),
+		repositories.NewProfileMatchResultRepository(db),
+		repositories.NewJabatanRepository(db),
+		repositories.NewGapWeightTableRepository(db),
+		repositories.NewCalculationRunRepository(db),
+		repositories.NewResultStatusHistoryRepository(db),
+	)
+}
+
+func TestValidateFactorRatio(t *testing.T) {
+	core, secondary := 70.0, 30.0
+	assert.NoError(t, validateFactorRatio(nil, nil))
+	assert.NoError(t, validateFactorRatio(&core, &secondary))
+
+	assert.Error(t, validateFactorRatio(&core, nil))
+	wrong := 40.0
+	assert.Error(t, validateFactorRatio(&core, &wrong))
+}
+
+func TestProfileMatchingService_Calculate_KeepsRunHistory(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := newTestProfileMatchingService(db)
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+	aspek := &models.Aspek{Nama: "Test Aspek", Deskripsi: "Test", Persentase: 100.0}
+	repositories.NewAspekRepository(db).Create(aspek)
+	kriteria := &models.Kriteria{AspekID: aspek.ID, Kode: "K1", Nama: "Kriteria 1", IsCore: true, Bobot: 1.0}
+	repositories.NewKriteriaRepository(db).Create(kriteria)
+	tenagaKerja := &models.TenagaKerja{NIK: "TK001", Nama: "Test TK"}
+	repositories.NewTenagaKerjaRepository(db).Create(tenagaKerja)
+	repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: kriteria.ID, TargetNilai: 4.0})
+	repositories.NewNilaiTenagaKerjaRepository(db).Create(&models.NilaiTenagaKerja{TenagaKerjaID: tenagaKerja.ID, KriteriaID: kriteria.ID, Nilai: 4.0})
+
+	userID := uint(7)
+	first, err := service.Calculate(CalculationRequest{JabatanID: jabatan.ID, UserID: &userID})
+	assert.NoError(t, err)
+	second, err := service.Calculate(CalculationRequest{JabatanID: jabatan.ID, Method: "saw"})
+	assert.NoError(t, err)
+
+	runs, err := service.GetRuns(jabatan.ID)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.Equal(t, "completed", runs[1].Status)
+	assert.Equal(t, &userID, runs[1].UserID)
+	assert.Equal(t, "saw", runs[0].Parameters.Method)
+
+	// The first run's results are kept after recalculating
+	_, results, err := service.GetRunByID(*first[0].RunID)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	// The latest run is current until another is marked official
+	current, err := service.GetResultsByJabatanID(jabatan.ID)
+	assert.NoError(t, err)
+	assert.Len(t, current, 1)
+	assert.Equal(t, second[0].ID, current[0].ID)
+
+	assert.NoError(t, service.MarkRunOfficial(*first[0].RunID, nil))
+	current, err = service.GetResultsByJabatanID(jabatan.ID)
+	assert.NoError(t, err)
+	assert.Len(t, current, 1)
+	assert.Equal(t, first[0].ID, current[0].ID)
+
+	assert.EqualError(t, service.MarkRunOfficial(0, nil), "calculation run not found")
+}
+
+func TestProfileMatchingService_Calculate_PersistsRanks(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := newTestProfileMatchingService(db)
+	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
+	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+	aspek := &models.Aspek{Nama: "Test Aspek", Deskripsi: "Test", Persentase: 100.0}
+	repositories.NewAspekRepository(db).Create(aspek)
+	kriteria := &models.Kriteria{AspekID: aspek.ID, Kode: "K1", Nama: "Kriteria 1", IsCore: true, Bobot: 1.0}
+	repositories.NewKriteriaRepository(db).Create(kriteria)
+	repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: kriteria.ID, TargetNilai: 4.0})
+
+	// TK002 and TK001 tie; TK003 scores lower
+	for _, tk := range []struct {
+		nik   string
+		nilai float64
+	}{{"TK002", 4.0}, {"TK001", 4.0}, {"TK003", 3.0}} {
+		tenagaKerja := &models.TenagaKerja{NIK: tk.nik, Nama: "Test " + tk.nik}
+		tenagaKerjaRepo.Create(tenagaKerja)
+		nilaiTenagaKerjaRepo.Create(&models.NilaiTenagaKerja{TenagaKerjaID: tenagaKerja.ID, KriteriaID: kriteria.ID, Nilai: tk.nilai})
+	}
+
+	results, err := service.Calculate(CalculationRequest{
+		JabatanID:    jabatan.ID,
+		RankingStyle: "competition",
+		TieBreakers:  []string{"nik"},
+	})
+	assert.NoError(t, err)
+
+	stored, err := service.GetResultsByJabatanID(jabatan.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored, 3)
+	assert.Equal(t, "TK001", stored[0].TenagaKerja.NIK)
+	assert.Equal(t, []int{1, 1, 3}, []int{stored[0].Rank, stored[1].Rank, stored[2].Rank})
+	assert.Equal(t, "competition", stored[0].RankingStyle)
+	assert.Equal(t, []string{"nik"}, stored[0].TieBreakers)
+
+	run, _, err := service.GetRunByID(*results[0].RunID)
+	assert.NoError(t, err)
+	assert.Equal(t, "competition", run.Parameters.RankingStyle)
+
+	_, err = service.Calculate(CalculationRequest{JabatanID: jabatan.ID, TieBreakers: []string{"kriteria:K9"}})
+	assert.EqualError(t, err, "tie breaker kriteria not in target profile")
+}
+
+func TestProfileMatchingService_ResultStatuses(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := newTestProfileMatchingService(db)
+	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
+	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position", JumlahLowongan: 1}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+	aspek := &models.Aspek{Nama: "Test Aspek", Deskripsi: "Test", Persentase: 100.0}
+	repositories.NewAspekRepository(db).Create(aspek)
+	kriteria := &models.Kriteria{AspekID: aspek.ID, Kode: "K1", Nama: "Kriteria 1", IsCore: true, Bobot: 1.0}
+	repositories.NewKriteriaRepository(db).Create(kriteria)
+	repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: kriteria.ID, TargetNilai: 4.0})
+	var tenagaKerja []models.TenagaKerja
+	for i, nilai := range []float64{4.0, 3.0} {
+		tk := models.TenagaKerja{NIK: fmt.Sprintf("TK00%d", i+1), Nama: "Test TK"}
+		tenagaKerjaRepo.Create(&tk)
+		nilaiTenagaKerjaRepo.Create(&models.NilaiTenagaKerja{TenagaKerjaID: tk.ID, KriteriaID: kriteria.ID, Nilai: nilai})
+		tenagaKerja = append(tenagaKerja, tk)
+	}
+
+	// The top rank within the vacancies is shortlisted
+	results, err := service.Calculate(CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, "shortlisted", results[0].Status)
+	assert.Equal(t, "candidate", results[1].Status)
+
+	userID := uint(3)
+	_, err = service.UpdateResultStatus(results[1].ID, "reserve", "second choice", &userID)
+	assert.NoError(t, err)
+	_, err = service.UpdateResultStatus(results[0].ID, "selected", "", &userID)
+	assert.NoError(t, err)
+	_, err = service.UpdateResultStatus(results[1].ID, "selected", "", &userID)
+	assert.EqualError(t, err, "jabatan has no remaining vacancies")
+
+	selected, err := service.GetCandidates(jabatan.ID, "selected")
+	assert.NoError(t, err)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, tenagaKerja[0].ID, selected[0].TenagaKerjaID)
+
+	// Statuses given by users carry over to a recalculation
+	recalculated, err := service.Calculate(CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, "selected", recalculated[0].Status)
+	assert.Equal(t, "reserve", recalculated[1].Status)
+
+	_, err = service.UpdateResultStatus(results[1].ID, "rejected", "", &userID)
+	assert.EqualError(t, err, "only current results can change status")
+
+	history, err := service.GetStatusHistory(recalculated[0].ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "selected", history[0].Status)
+	assert.Equal(t, "shortlisted", history[1].Status)
+	assert.True(t, history[1].Auto)
+	assert.Equal(t, results[0].ID, history[1].ProfileMatchResultID)
+}
+
+func TestProfileMatchingService_Calculate_Knockouts(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := newTestProfileMatchingService(db)
+	tenagaKerjaRepo := repositories.NewTenagaKerjaRepository(db)
+	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
+
+	jabatan := &models.Jabatan{Nama: "Teknisi Maintenance", Deskripsi: "Teknisi"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+	aspek := &models.Aspek{Nama: "Teknis", Deskripsi: "Test", Persentase: 100.0}
+	repositories.NewAspekRepository(db).Create(aspek)
+	mesin := &models.Kriteria{AspekID: aspek.ID, Kode: "K1", Nama: "Pengetahuan Mesin", IsCore: true, Bobot: 1.0}
+	repositories.NewKriteriaRepository(db).Create(mesin)
+	lain := &models.Kriteria{AspekID: aspek.ID, Kode: "K2", Nama: "Komunikasi", IsCore: false, Bobot: 1.0}
+	repositories.NewKriteriaRepository(db).Create(lain)
+	minimum := 3.0
+	repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: mesin.ID, TargetNilai: 3.0, NilaiMinimum: &minimum})
+	repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: lain.ID, TargetNilai: 3.0})
+
+	// The second tenaga kerja scores well on K2 but is below the K1 minimum
+	var tenagaKerja []models.TenagaKerja
+	for i, nilai := range [][2]float64{{3, 3}, {2, 5}} {
+		tk := models.TenagaKerja{NIK: fmt.Sprintf("TK00%d", i+1), Nama: "Test TK"}
+		tenagaKerjaRepo.Create(&tk)
+		nilaiTenagaKerjaRepo.Create(&models.NilaiTenagaKerja{TenagaKerjaID: tk.ID, KriteriaID: mesin.ID, Nilai: nilai[0]})
+		nilaiTenagaKerjaRepo.Create(&models.NilaiTenagaKerja{TenagaKerjaID: tk.ID, KriteriaID: lain.ID, Nilai: nilai[1]})
+		tenagaKerja = append(tenagaKerja, tk)
+	}
+
+	results, err := service.Calculate(CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, tenagaKerja[0].ID, results[0].TenagaKerjaID)
+
+	ineligible, err := service.GetIneligibleByJabatanID(jabatan.ID)
+	assert.NoError(t, err)
+	assert.Len(t, ineligible, 1)
+	assert.Equal(t, tenagaKerja[1].ID, ineligible[0].TenagaKerjaID)
+	assert.Equal(t, "TK002", ineligible[0].TenagaKerja.NIK)
+	assert.Len(t, ineligible[0].Failures, 1)
+	assert.Equal(t, "Pengetahuan Mesin", ineligible[0].Failures[0].Nama)
+	assert.Equal(t, 2.0, *ineligible[0].Failures[0].Nilai)
+
+	// A skor minimum above every score leaves the ranking empty
+	skorMinimum := 99.0
+	repositories.NewJabatanRepository(db).Update(jabatan.ID, &models.Jabatan{SkorMinimum: &skorMinimum})
+	results, err = service.Calculate(CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	runs, err := service.GetRuns(jabatan.ID)
+	assert.NoError(t, err)
+	ineligible, err = service.GetIneligibleByRunID(runs[0].ID)
+	assert.NoError(t, err)
+	assert.Len(t, ineligible, 2)
+	assert.True(t, ineligible[0].BelowSkorMinimum)
+}
+
+func TestProfileMatchingService_GetResultDetailByID_ServesSnapshot(t *testing.T) {
+	db := setupServiceTestDB(t)
+	service := newTestProfileMatchingService(db)
+	nilaiTenagaKerjaRepo := repositories.NewNilaiTenagaKerjaRepository(db)
+
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+	aspek := &models.Aspek{Nama: "Test Aspek", Deskripsi: "Test", Persentase: 100.0}
+	repositories.NewAspekRepository(db).Create(aspek)
+	kriteria := &models.Kriteria{AspekID: aspek.ID, Kode: "K1", Nama: "Kriteria 1", IsCore: true, Bobot: 1.0}
+	repositories.NewKriteriaRepository(db).Create(kriteria)
+	tenagaKerja := &models.TenagaKerja{NIK: "TK001", Nama: "Test TK"}
+	repositories.NewTenagaKerjaRepository(db).Create(tenagaKerja)
+	repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: kriteria.ID, TargetNilai: 4.0})
+	nilai := &models.NilaiTenagaKerja{TenagaKerjaID: tenagaKerja.ID, KriteriaID: kriteria.ID, Nilai: 3.0}
+	nilaiTenagaKerjaRepo.Create(nilai)
+
+	results, err := service.Calculate(CalculationRequest{JabatanID: jabatan.ID})
+	assert.NoError(t, err)
+
+	// Editing the nilai afterwards does not change the stored breakdown
+	nilai.Nilai = 5.0
+	nilaiTenagaKerjaRepo.Update(nilai.ID, nilai)
+
+	result, details, err := service.GetResultDetailByID(results[0].ID)
+	assert.NoError(t, err)
+	assert.Len(t, result.Aspek, 1)
+
+	aspekDetails := details["aspek"].(map[string]map[string]interface{})
+	kriteriaList := aspekDetails["Test Aspek"]["kriteria"].([]map[string]interface{})
+	assert.Equal(t, 3.0, kriteriaList[0]["actual"])
+	assert.Equal(t, -1.0, kriteriaList[0]["gap"])
+	assert.InDelta(t, result.TotalScore, details["total_score"], 0.01)
+}
+
+func TestCompareResults(t *testing.T) {
+	snapshot := func(gaps ...float64) []models.ProfileMatchResultAspek {
+		aspek := models.ProfileMatchResultAspek{AspekID: 1}
+		for i, gap := range gaps {
+			aspek.Kriteria = append(aspek.Kriteria, models.ProfileMatchResultKriteria{KriteriaID: uint(i + 1), Kode: fmt.Sprintf("K%d", i+1), Gap: gap})
+		}
+		return []models.ProfileMatchResultAspek{aspek}
+	}
+
+	base := []models.ProfileMatchResult{
+		{TenagaKerjaID: 1, TotalScore: 4.5, Aspek: snapshot(0, -1)},
+		{TenagaKerjaID: 2, TotalScore: 4.0, Aspek: snapshot(-1, 0)},
+		{TenagaKerjaID: 3, TotalScore: 3.0, Aspek: snapshot(-2, -2)},
+	}
+	other := []models.ProfileMatchResult{
+		{TenagaKerjaID: 2, TotalScore: 5.0, Aspek: snapshot(0, 0)},
+		{TenagaKerjaID: 1, TotalScore: 4.5, Aspek: snapshot(0, -1)},
+		{TenagaKerjaID: 4, TotalScore: 2.0, Aspek: snapshot(-3)},
+	}
+
+	movements := compareResults(base, other)
+	assert.Len(t, movements, 4)
+
+	assert.Equal(t, uint(2), movements[0].TenagaKerjaID)
+	assert.Equal(t, 2, movements[0].OldRank)
+	assert.Equal(t, 1, movements[0].NewRank)
+	assert.Equal(t, 1, movements[0].RankChange)
+	assert.InDelta(t, 1.0, movements[0].ScoreDelta, 1e-9)
+	assert.Len(t, movements[0].KriteriaChanges, 1)
+	assert.Equal(t, "K1", movements[0].KriteriaChanges[0].Kode)
+	assert.Equal(t, -1.0, *movements[0].KriteriaChanges[0].OldGap)
+	assert.Equal(t, 0.0, *movements[0].KriteriaChanges[0].NewGap)
+
+	assert.Equal(t, uint(1), movements[1].TenagaKerjaID)
+	assert.Equal(t, -1, movements[1].RankChange)
+	assert.Empty(t, movements[1].KriteriaChanges)
+
+	// Only in the new ranking
+	assert.Equal(t, uint(4), movements[2].TenagaKerjaID)
+	assert.Equal(t, 0, movements[2].OldRank)
+	assert.Equal(t, 0, movements[2].RankChange)
+
+	// Missing from the new ranking comes last
+	assert.Equal(t, uint(3), movements[3].TenagaKerjaID)
+	assert.Equal(t, 0, movements[3].NewRank)
+	assert.Len(t, movements[3].KriteriaChanges, 2)
+	assert.Nil(t, movements[3].KriteriaChanges[0].NewGap)
+}
+
+// seedCalculationWorkforce creates a jabatan with two target kriteria and n
+// tenaga kerja with nilai for both
+func seedCalculationWorkforce(tb testing.TB, db *gorm.DB, n int) *models.Jabatan {
+	jabatan := &models.Jabatan{Nama: "Manager", Deskripsi: "Manager Position"}
+	repositories.NewJabatanRepository(db).Create(jabatan)
+	aspek := &models.Aspek{Nama: "Test Aspek", Deskripsi: "Test", Persentase: 100.0}
+	repositories.NewAspekRepository(db).Create(aspek)
+	kriteria := []models.Kriteria{
+		{AspekID: aspek.ID, Kode: "K1", Nama: "Kriteria 1", IsCore: true, Bobot: 1.0},
+		{AspekID: aspek.ID, Kode: "K2", Nama: "Kriteria 2", IsCore: false, Bobot: 1.0},
+	}
+	for i := range kriteria {
+		repositories.NewKriteriaRepository(db).Create(&kriteria[i])
+		repositories.NewTargetProfileRepository(db).Create(&models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: kriteria[i].ID, TargetNilai: 3.0})
+	}
+
+	tenagaKerja := make([]models.TenagaKerja, n)
+	for i := range tenagaKerja {
+		tenagaKerja[i] = models.TenagaKerja{NIK: fmt.Sprintf("TK%05d", i), Nama: fmt.Sprintf("Tenaga Kerja %d", i)}
+	}
+	if err := db.CreateInBatches(&tenagaKerja, 1000).Error; err != nil {
+		tb.Fatalf("Failed to seed tenaga kerja: %v", err)
+	}
+	nilai := make([]models.NilaiTenagaKerja, 0, 2*n)
+	for i, tk := range tenagaKerja {
+		for _, k := range kriteria {
+			nilai = append(nilai, models.NilaiTenagaKerja{TenagaKerjaID: tk.ID, KriteriaID: k.ID, Nilai: float64(1 + i%5)})
+		}
+	}
+	if err := db.CreateInBatches(&nilai, 1000).Error; err != nil {
+		tb.Fatalf("Failed to seed nilai: %v", err)
+	}
+	return jabatan
+}
+
+// countSelects counts the SELECT statements the database runs after it is
+// called. Results are written in fixed-size batches and not counted.
+func countSelects(db *gorm.DB) *int {
+	count := new(int)
+	inc := func(*gorm.DB) { *count++ }
+	db.Callback().Query().After("gorm:query").Register("test:count_query", inc)
+	db.Callback().Row().After("gorm:row").Register("test:count_row", inc)
+	return count
+}
+
+func TestProfileMatchingService_Calculate_SelectsDoNotScaleWithTenagaKerja(t *testing.T) {
+	selectsFor := func(n int) int {
+		db := setupServiceTestDB(t)
+		jabatan := seedCalculationWorkforce(t, db, n)
+		service := newTestProfileMatchingService(db)
+		count := countSelects(db)
+
+		results, err := service.Calculate(CalculationRequest{JabatanID: jabatan.ID})
+		assert.NoError(t, err)
+		assert.Len(t, results, n)
+		return *count
+	}
+
+	// More tenaga kerja in a chunk add no selects
+	oneChunk := selectsFor(10)
+	assert.Equal(t, oneChunk, selectsFor(candidateChunkSize))
+
+	// Each further chunk adds the same few selects, those loading its
+	// tenaga kerja and their nilai
+	twoChunks := selectsFor(candidateChunkSize + 1)
+	threeChunks := selectsFor(2*candidateChunkSize + 1)
+	perChunk := twoChunks - oneChunk
+	assert.Equal(t, 2, perChunk)
+	assert.Equal(t, perChunk, threeChunks-twoChunks)
+}
+
+func BenchmarkProfileMatchingService_Calculate(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("tenaga_kerja=%d", n), func(b *testing.B) {
+			db := setupServiceTestDB(b)
+			jabatan := seedCalculationWorkforce(b, db, n)
+			service := newTestProfileMatchingService(db)
+			count := countSelects(db)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := service.Calculate(CalculationRequest{JabatanID: jabatan.ID}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(*count)/float64(b.N), "selects/op")
+		})
+	}
+}
@@ -27,6 +27,13 @@ func NewTargetProfileService(
 	}
 }
 
+// WithActor returns the service recording the changes it makes for the user
+func (s *TargetProfileService) WithActor(userID *uint) *TargetProfileService {
+	scoped := *s
+	scoped.targetProfileRepo = s.targetProfileRepo.WithActor(userID)
+	return &scoped
+}
+
 func (s *TargetProfileService) GetAll() ([]models.TargetProfile, error) {
 	return s.targetProfileRepo.GetAll()
 }
@@ -68,7 +75,15 @@ func (s *TargetProfileService) Create(profile *models.TargetProfile) error {
 	return s.targetProfileRepo.Create(profile)
 }
 
-func (s *TargetProfileService) Update(id uint, profile *models.TargetProfile) error {
+// TargetProfileUpdateOptions are the changes to a target profile its zero
+// values cannot express
+type TargetProfileUpdateOptions struct {
+	// ResetNilaiMinimum removes the knockout minimum of the target profile
+	ResetNilaiMinimum bool
+}
+
+// Update validates and updates the target profile in one transaction
+func (s *TargetProfileService) Update(id uint, profile *models.TargetProfile, opts TargetProfileUpdateOptions) error {
 	// Check if profile exists
 	_, err := s.targetProfileRepo.GetByID(id)
 	if err != nil {
@@ -100,6 +115,9 @@ func (s *TargetProfileService) Update(id uint, profile *models.TargetProfile) er
 		}
 	}
 
+	if opts.ResetNilaiMinimum {
+		return s.targetProfileRepo.UpdateWithColumns(id, profile, map[string]interface{}{"nilai_minimum": nil})
+	}
 	return s.targetProfileRepo.Update(id, profile)
 }
 
@@ -115,4 +133,3 @@ func (s *TargetProfileService) Delete(id uint) error {
 
 	return s.targetProfileRepo.Delete(id)
 }
-
@@ -16,7 +16,7 @@ func TestTargetProfileService_Create(t *testing.T) {
 	kriteriaRepo := repositories.NewKriteriaRepository(db)
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 
-	jabatanService := NewJabatanService(jabatanRepo)
+	jabatanService := NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	aspekService := NewAspekService(aspekRepo)
 	kriteriaService := NewKriteriaService(kriteriaRepo, aspekRepo)
 	service := NewTargetProfileService(targetProfileRepo, jabatanRepo, kriteriaRepo)
@@ -31,8 +31,8 @@ func TestTargetProfileService_Create(t *testing.T) {
 	kriteriaService.Create(kriteria)
 
 	targetProfile := &models.TargetProfile{
-		JabatanID:  jabatan.ID,
-		KriteriaID: kriteria.ID,
+		JabatanID:   jabatan.ID,
+		KriteriaID:  kriteria.ID,
 		TargetNilai: 4.0,
 	}
 
@@ -59,8 +59,8 @@ func TestTargetProfileService_Create_InvalidJabatan(t *testing.T) {
 	kriteriaService.Create(kriteria)
 
 	targetProfile := &models.TargetProfile{
-		JabatanID:  999, // Non-existent jabatan
-		KriteriaID: kriteria.ID,
+		JabatanID:   999, // Non-existent jabatan
+		KriteriaID:  kriteria.ID,
 		TargetNilai: 4.0,
 	}
 
@@ -76,7 +76,7 @@ func TestTargetProfileService_GetByID(t *testing.T) {
 	kriteriaRepo := repositories.NewKriteriaRepository(db)
 	targetProfileRepo := repositories.NewTargetProfileRepository(db)
 
-	jabatanService := NewJabatanService(jabatanRepo)
+	jabatanService := NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	aspekService := NewAspekService(aspekRepo)
 	kriteriaService := NewKriteriaService(kriteriaRepo, aspekRepo)
 	service := NewTargetProfileService(targetProfileRepo, jabatanRepo, kriteriaRepo)
@@ -91,8 +91,8 @@ func TestTargetProfileService_GetByID(t *testing.T) {
 	kriteriaService.Create(kriteria)
 
 	targetProfile := &models.TargetProfile{
-		JabatanID:  jabatan.ID,
-		KriteriaID: kriteria.ID,
+		JabatanID:   jabatan.ID,
+		KriteriaID:  kriteria.ID,
 		TargetNilai: 4.0,
 	}
 	service.Create(targetProfile)
@@ -102,3 +102,31 @@ func TestTargetProfileService_GetByID(t *testing.T) {
 	assert.Equal(t, targetProfile.TargetNilai, found.TargetNilai)
 }
 
+func TestTargetProfileService_Update_ResetNilaiMinimum(t *testing.T) {
+	db := setupServiceTestDB(t)
+	jabatanRepo := repositories.NewJabatanRepository(db)
+	aspekRepo := repositories.NewAspekRepository(db)
+	kriteriaRepo := repositories.NewKriteriaRepository(db)
+	service := NewTargetProfileService(repositories.NewTargetProfileRepository(db), jabatanRepo, kriteriaRepo)
+
+	jabatan := &models.Jabatan{Nama: "Manager"}
+	assert.NoError(t, jabatanRepo.Create(jabatan))
+	aspek := &models.Aspek{Nama: "Kompetensi", Persentase: 50.0}
+	assert.NoError(t, aspekRepo.Create(aspek))
+	kriteria := &models.Kriteria{AspekID: aspek.ID, Kode: "K1", Nama: "Kriteria 1", IsCore: true, Bobot: 1.0}
+	assert.NoError(t, kriteriaRepo.Create(kriteria))
+	minimum := 3.0
+	profile := &models.TargetProfile{JabatanID: jabatan.ID, KriteriaID: kriteria.ID, TargetNilai: 4.0, NilaiMinimum: &minimum}
+	assert.NoError(t, service.Create(profile))
+
+	// A rejected update keeps the minimum
+	err := service.Update(profile.ID, &models.TargetProfile{KriteriaID: 999}, TargetProfileUpdateOptions{ResetNilaiMinimum: true})
+	assert.EqualError(t, err, "kriteria not found")
+	updated, _ := service.GetByID(profile.ID)
+	assert.Equal(t, &minimum, updated.NilaiMinimum)
+
+	assert.NoError(t, service.Update(profile.ID, &models.TargetProfile{TargetNilai: 5.0}, TargetProfileUpdateOptions{ResetNilaiMinimum: true}))
+	updated, _ = service.GetByID(profile.ID)
+	assert.Nil(t, updated.NilaiMinimum)
+	assert.Equal(t, 5.0, updated.TargetNilai)
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"unicode/utf8"
 
 	"backend/internal/models"
 	"backend/internal/repositories"
@@ -17,6 +19,13 @@ func NewTenagaKerjaService(tenagaKerjaRepo *repositories.TenagaKerjaRepository)
 	return &TenagaKerjaService{tenagaKerjaRepo: tenagaKerjaRepo}
 }
 
+// WithActor returns the service recording the changes it makes for the user
+func (s *TenagaKerjaService) WithActor(userID *uint) *TenagaKerjaService {
+	scoped := *s
+	scoped.tenagaKerjaRepo = s.tenagaKerjaRepo.WithActor(userID)
+	return &scoped
+}
+
 func (s *TenagaKerjaService) GetAll() ([]models.TenagaKerja, error) {
 	return s.tenagaKerjaRepo.GetAll()
 }
@@ -33,11 +42,8 @@ func (s *TenagaKerjaService) GetByID(id uint) (*models.TenagaKerja, error) {
 }
 
 func (s *TenagaKerjaService) Create(tenagaKerja *models.TenagaKerja) error {
-	if tenagaKerja.NIK == "" {
-		return errors.New("NIK tidak boleh kosong")
-	}
-	if tenagaKerja.Nama == "" {
-		return errors.New("nama tidak boleh kosong")
+	if errs := tenagaKerjaFieldErrors(tenagaKerja); len(errs) > 0 {
+		return errors.New(errs[0])
 	}
 
 	// Check if NIK already exists
@@ -52,6 +58,139 @@ func (s *TenagaKerjaService) Create(tenagaKerja *models.TenagaKerja) error {
 	return s.tenagaKerjaRepo.Create(tenagaKerja)
 }
 
+// tenagaKerjaFieldErrors checks the required fields and the column lengths
+// of a new tenaga kerja
+func tenagaKerjaFieldErrors(tenagaKerja *models.TenagaKerja) []string {
+	var errs []string
+	if tenagaKerja.NIK == "" {
+		errs = append(errs, "NIK tidak boleh kosong")
+	} else if utf8.RuneCountInString(tenagaKerja.NIK) > 20 {
+		errs = append(errs, "NIK maksimal 20 karakter")
+	}
+	if tenagaKerja.Nama == "" {
+		errs = append(errs, "nama tidak boleh kosong")
+	} else if utf8.RuneCountInString(tenagaKerja.Nama) > 100 {
+		errs = append(errs, "nama maksimal 100 karakter")
+	}
+	if utf8.RuneCountInString(tenagaKerja.Telepon) > 20 {
+		errs = append(errs, "telepon maksimal 20 karakter")
+	}
+	return errs
+}
+
+// MaxTenagaKerjaImportRows caps the rows of a single import
+const MaxTenagaKerjaImportRows = 5000
+
+// TenagaKerjaImportRow is one row of an import. Errors starts with the
+// problems found while reading the row and gains those found by Import.
+type TenagaKerjaImportRow struct {
+	Line        int
+	TenagaKerja models.TenagaKerja
+	Errors      []string
+}
+
+// Import checks the rows with the rules of Create, also rejecting a NIK
+// repeated within the import. Unless dryRun is set, the rows without errors
+// are inserted in one transaction and get their IDs; a row whose NIK is
+// registered meanwhile gets an error instead.
+func (s *TenagaKerjaService) Import(rows []TenagaKerjaImportRow, dryRun bool) ([]TenagaKerjaImportRow, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("import has no rows")
+	}
+	if len(rows) > MaxTenagaKerjaImportRows {
+		return nil, errors.New("too many import rows")
+	}
+
+	niks := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.TenagaKerja.NIK != "" {
+			niks = append(niks, row.TenagaKerja.NIK)
+		}
+	}
+	existing, err := s.existingNIKs(niks)
+	if err != nil {
+		return nil, err
+	}
+
+	firstLine := make(map[string]int)
+	var valid []int
+	for i := range rows {
+		row := &rows[i]
+		row.Errors = append(row.Errors, tenagaKerjaFieldErrors(&row.TenagaKerja)...)
+		if nik := row.TenagaKerja.NIK; nik != "" {
+			if existing[nik] {
+				row.Errors = append(row.Errors, "NIK sudah terdaftar")
+			} else if line, ok := firstLine[nik]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("NIK sama dengan baris %d", line))
+			} else {
+				firstLine[nik] = row.Line
+			}
+		}
+		if len(row.Errors) == 0 {
+			valid = append(valid, i)
+		}
+	}
+
+	if dryRun {
+		return rows, nil
+	}
+	for len(valid) > 0 {
+		list := make([]models.TenagaKerja, len(valid))
+		for j, i := range valid {
+			list[j] = rows[i].TenagaKerja
+		}
+		err := s.tenagaKerjaRepo.CreateBatch(list)
+		if err == nil {
+			for j, i := range valid {
+				rows[i].TenagaKerja = list[j]
+			}
+			break
+		}
+		if err != repositories.ErrDuplicateNIK {
+			return nil, err
+		}
+
+		// A NIK was registered since the check above: report it on its row
+		// and insert the others again
+		niks = niks[:0]
+		for _, i := range valid {
+			niks = append(niks, rows[i].TenagaKerja.NIK)
+		}
+		registered, err := s.existingNIKs(niks)
+		if err != nil {
+			return nil, err
+		}
+		remaining := make([]int, 0, len(valid))
+		for _, i := range valid {
+			if registered[rows[i].TenagaKerja.NIK] {
+				rows[i].Errors = append(rows[i].Errors, "NIK sudah terdaftar")
+			} else {
+				remaining = append(remaining, i)
+			}
+		}
+		if len(remaining) == len(valid) {
+			return nil, repositories.ErrDuplicateNIK
+		}
+		valid = remaining
+	}
+	return rows, nil
+}
+
+// existingNIKs returns which of the given NIK are already registered
+func (s *TenagaKerjaService) existingNIKs(niks []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for start := 0; start < len(niks); start += candidateChunkSize {
+		found, err := s.tenagaKerjaRepo.GetExistingNIKs(niks[start:min(start+candidateChunkSize, len(niks))])
+		if err != nil {
+			return nil, err
+		}
+		for nik := range found {
+			existing[nik] = true
+		}
+	}
+	return existing, nil
+}
+
 func (s *TenagaKerjaService) Update(id uint, tenagaKerja *models.TenagaKerja) error {
 	// Check if tenaga kerja exists
 	existing, err := s.tenagaKerjaRepo.GetByID(id)
@@ -88,4 +227,3 @@ func (s *TenagaKerjaService) Delete(id uint) error {
 
 	return s.tenagaKerjaRepo.Delete(id)
 }
-
@@ -113,3 +113,42 @@ func TestTenagaKerjaService_Delete(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestTenagaKerjaService_Import(t *testing.T) {
+	db := setupServiceTestDB(t)
+	repo := repositories.NewTenagaKerjaRepository(db)
+	service := NewTenagaKerjaService(repo)
+	service.Create(&models.TenagaKerja{NIK: "TK001", Nama: "John Doe"})
+
+	rows := func() []TenagaKerjaImportRow {
+		return []TenagaKerjaImportRow{
+			{Line: 2, TenagaKerja: models.TenagaKerja{NIK: "TK002", Nama: "Ani"}},
+			{Line: 3, TenagaKerja: models.TenagaKerja{NIK: "TK001", Nama: "Budi"}},
+			{Line: 4, TenagaKerja: models.TenagaKerja{NIK: "TK002", Nama: "Citra"}},
+			{Line: 5, TenagaKerja: models.TenagaKerja{Nama: "Dewi"}, Errors: []string{"alamat tidak boleh kosong"}},
+			{Line: 6, TenagaKerja: models.TenagaKerja{NIK: "TK003", Nama: "Eka"}},
+		}
+	}
+
+	// A dry run reports without saving
+	report, err := service.Import(rows(), true)
+	assert.NoError(t, err)
+	assert.Empty(t, report[0].Errors)
+	assert.Equal(t, []string{"NIK sudah terdaftar"}, report[1].Errors)
+	assert.Equal(t, []string{"NIK sama dengan baris 2"}, report[2].Errors)
+	assert.Equal(t, []string{"alamat tidak boleh kosong", "NIK tidak boleh kosong"}, report[3].Errors)
+	assert.Zero(t, report[0].TenagaKerja.ID)
+	all, _ := service.GetAll()
+	assert.Len(t, all, 1)
+
+	// Committing inserts only the valid rows
+	report, err = service.Import(rows(), false)
+	assert.NoError(t, err)
+	assert.NotZero(t, report[0].TenagaKerja.ID)
+	assert.NotZero(t, report[4].TenagaKerja.ID)
+	assert.Zero(t, report[2].TenagaKerja.ID)
+	all, _ = service.GetAll()
+	assert.Len(t, all, 3)
+
+	_, err = service.Import(nil, true)
+	assert.EqualError(t, err, "import has no rows")
+}
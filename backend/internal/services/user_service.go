@@ -18,6 +18,13 @@ func NewUserService(userRepo *repositories.UserRepository) *UserService {
 	return &UserService{userRepo: userRepo}
 }
 
+// WithActor returns the service recording the changes it makes for the user
+func (s *UserService) WithActor(userID *uint) *UserService {
+	scoped := *s
+	scoped.userRepo = s.userRepo.WithActor(userID)
+	return &scoped
+}
+
 func (s *UserService) GetAll() ([]models.User, error) {
 	users, err := s.userRepo.GetAll()
 	if err != nil {
@@ -108,4 +115,3 @@ func (s *UserService) Delete(id uint) error {
 func (s *UserService) Register(user *models.User) error {
 	return s.Create(user)
 }
-
@@ -12,7 +12,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func setupServiceTestDB(t *testing.T) *gorm.DB {
+func setupServiceTestDB(t testing.TB) *gorm.DB {
 	db, err := database.ConnectTestDB(t)
 	if err != nil {
 		t.Fatalf("Failed to connect to test database: %v", err)
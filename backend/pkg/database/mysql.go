@@ -37,13 +37,22 @@ func ConnectDB() (*gorm.DB, error) {
 	// Auto migrate
 	err = db.AutoMigrate(
 		&models.User{},
+		&models.GapWeightTable{},
+		&models.GapWeightEntry{},
 		&models.Jabatan{},
 		&models.Aspek{},
 		&models.Kriteria{},
 		&models.TargetProfile{},
 		&models.TenagaKerja{},
 		&models.NilaiTenagaKerja{},
+		&models.CalculationRun{},
+		&models.CalculationJob{},
 		&models.ProfileMatchResult{},
+		&models.ProfileMatchResultAspek{},
+		&models.ProfileMatchResultKriteria{},
+		&models.ResultStatusHistory{},
+		&models.IneligibleResult{},
+		&models.AuditLog{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %v", err)
@@ -13,7 +13,7 @@ import (
 
 // ConnectTestDB connects to the test database
 // It uses TEST_DB_NAME environment variable if available, otherwise uses DB_NAME_test
-func ConnectTestDB(t *testing.T) (*gorm.DB, error) {
+func ConnectTestDB(t testing.TB) (*gorm.DB, error) {
 	config := GetTestDBConfig()
 	dsn := config.BuildDSN()
 
@@ -28,13 +28,22 @@ func ConnectTestDB(t *testing.T) (*gorm.DB, error) {
 	// Auto migrate test database
 	err = db.AutoMigrate(
 		&models.User{},
+		&models.GapWeightTable{},
+		&models.GapWeightEntry{},
 		&models.Jabatan{},
 		&models.Aspek{},
 		&models.Kriteria{},
 		&models.TargetProfile{},
 		&models.TenagaKerja{},
 		&models.NilaiTenagaKerja{},
+		&models.CalculationRun{},
+		&models.CalculationJob{},
 		&models.ProfileMatchResult{},
+		&models.ProfileMatchResultAspek{},
+		&models.ProfileMatchResultKriteria{},
+		&models.ResultStatusHistory{},
+		&models.IneligibleResult{},
+		&models.AuditLog{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate test database: %v", err)
@@ -46,13 +55,22 @@ func ConnectTestDB(t *testing.T) (*gorm.DB, error) {
 // CleanTestDB clears all data from test database tables
 func CleanTestDB(db *gorm.DB) error {
 	tables := []string{
+		"audit_logs",
+		"ineligible_results",
+		"result_status_histories",
+		"profile_match_result_kriteria",
+		"profile_match_result_aspeks",
 		"profile_match_results",
+		"calculation_runs",
+		"calculation_jobs",
 		"nilai_tenaga_kerjas",
 		"target_profiles",
 		"tenaga_kerjas",
 		"kriterias",
 		"aspeks",
 		"jabatans",
+		"gap_weight_entries",
+		"gap_weight_tables",
 		"users",
 	}
 
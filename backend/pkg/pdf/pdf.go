@@ -0,0 +1,184 @@
+// Package pdf writes simple A4 documents of text and lines with the standard
+// library. Text uses the built-in Helvetica fonts, so no font files are
+// embedded; characters outside Windows-1252 are printed as "?".
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of a PDF document
+const ContentType = "application/pdf"
+
+// A4 page size in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font is one of the built-in fonts
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+// Document is a PDF under construction. Coordinates are in points from the
+// top-left corner of the page.
+type Document struct {
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+}
+
+// New returns an empty document; AddPage must be called before drawing
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage starts a new page and makes it current
+func (d *Document) AddPage() {
+	d.current = &bytes.Buffer{}
+	d.pages = append(d.pages, d.current)
+}
+
+// PageCount returns the number of pages added so far
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage makes the 0-based page i current, so it can be drawn on again
+func (d *Document) SetPage(i int) {
+	d.current = d.pages[i]
+}
+
+// Text draws s with its baseline starting at x, y
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(d.current, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		font+1, number(size), number(x), number(PageHeight-y), escape(encode(s)))
+}
+
+// Line draws a 0.5 point line from x1, y1 to x2, y2
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.current, "0.5 w %s %s m %s %s l S\n",
+		number(x1), number(PageHeight-y1), number(x2), number(PageHeight-y2))
+}
+
+// TextWidth returns the width of s in points
+func TextWidth(s string, font Font, size float64) float64 {
+	widths := &regularWidths
+	if font == Bold {
+		widths = &boldWidths
+	}
+	total := 0
+	for _, c := range encode(s) {
+		if c >= 32 && c <= 126 {
+			total += widths[c-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Truncate shortens s with "..." so it fits in width points
+func Truncate(s string, font Font, size, width float64) string {
+	if TextWidth(s, font, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if t := string(runes) + "..."; TextWidth(t, font, size) <= width {
+			return t
+		}
+	}
+	return ""
+}
+
+// WriteTo writes the document. Each page is an object followed by its
+// content stream; the fonts and page tree come first.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = strconv.Itoa(5+2*i) + " 0 R"
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			number(PageWidth), number(PageHeight), 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.WriteTo(w)
+}
+
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// encode converts s to Windows-1252, which matches Unicode for printable
+// ASCII and Latin-1
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if (r >= 32 && r <= 126) || (r >= 160 && r <= 255) {
+			out = append(out, byte(r))
+		} else {
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func escape(b []byte) string {
+	var s strings.Builder
+	for _, c := range b {
+		if c == '\\' || c == '(' || c == ')' {
+			s.WriteByte('\\')
+		}
+		s.WriteByte(c)
+	}
+	return s.String()
+}
+
+// Glyph widths of characters 32 to 126 in thousandths of the font size
+var regularWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var boldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
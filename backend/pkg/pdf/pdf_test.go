@@ -0,0 +1,59 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument(t *testing.T) {
+	doc := New()
+	doc.AddPage()
+	doc.Text(40, 50, Bold, 14, "Laporan (final)")
+	doc.Line(40, 60, 200, 60)
+	doc.AddPage()
+	doc.Text(40, 50, Regular, 10, "José ✓")
+	doc.SetPage(0)
+	doc.Text(40, 800, Regular, 8, "Halaman 1 dari 2")
+
+	var buf bytes.Buffer
+	_, err := doc.WriteTo(&buf)
+	assert.NoError(t, err)
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "%PDF-1.4"))
+	assert.Contains(t, out, "/Count 2")
+	assert.Contains(t, out, `(Laporan \(final\)) Tj`)
+	assert.Contains(t, out, "(Halaman 1 dari 2) Tj")
+	assert.Contains(t, out, "(Jos\xe9 ?) Tj")
+
+	// Every xref offset points at its object
+	xrefStart := strings.Index(out, "\nxref\n") + 1
+	xref := out[xrefStart:]
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(xref, -1)
+	assert.Len(t, offsets, 8)
+	for i, m := range offsets {
+		offset, _ := strconv.Atoi(m[1])
+		assert.True(t, strings.HasPrefix(out[offset:], strconv.Itoa(i+1)+" 0 obj"))
+	}
+	startxref := regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(out)
+	assert.Equal(t, strconv.Itoa(xrefStart), startxref[1])
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 5.56, TextWidth("0", Regular, 10), 1e-9)
+	assert.InDelta(t, 2.22, TextWidth("i", Regular, 10), 1e-9)
+	assert.InDelta(t, 2.78, TextWidth("i", Bold, 10), 1e-9)
+	assert.Greater(t, TextWidth("Wm", Regular, 10), TextWidth("il", Regular, 10))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "Budi", Truncate("Budi", Regular, 10, 100))
+	short := Truncate("Budi Santoso Wibowo", Regular, 10, 50)
+	assert.True(t, strings.HasSuffix(short, "..."))
+	assert.LessOrEqual(t, TextWidth(short, Regular, 10), 50.0)
+}
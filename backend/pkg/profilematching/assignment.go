@@ -0,0 +1,252 @@
+package profilematching
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// MaxAssignmentOpenings caps the total openings of a single assignment
+const MaxAssignmentOpenings = 500
+
+// Vacancy is a jabatan with a number of openings to fill
+type Vacancy struct {
+	JabatanID uint
+	Openings  int
+}
+
+// Pair is a tenaga kerja placed in, or kept out of, a jabatan
+type Pair struct {
+	TenagaKerjaID uint
+	JabatanID     uint
+}
+
+// AssignmentInput lists the vacancies and the score of every tenaga kerja
+// for each jabatan. A tenaga kerja without a score for a jabatan cannot
+// fill it. Pinned pairs are always assigned; excluded pairs never are.
+type AssignmentInput struct {
+	Vacancies []Vacancy
+	Scores    map[Pair]float64
+	Pinned    []Pair
+	Excluded  []Pair
+}
+
+// Alternative is the best jabatan a tenaga kerja could fill besides the
+// assigned one
+type Alternative struct {
+	JabatanID uint
+	Score     float64
+}
+
+// Assignment is a tenaga kerja placed in a jabatan with its score there
+type Assignment struct {
+	Pair
+	Score       float64
+	Pinned      bool
+	Alternative *Alternative
+}
+
+// AssignmentResult is the outcome of Assign
+type AssignmentResult struct {
+	// Assignments are ordered by vacancy, then by score
+	Assignments []Assignment
+	// Unfilled lists the openings left empty for lack of candidates
+	Unfilled   []Vacancy
+	TotalScore float64
+}
+
+// Assign fills the openings so that the total score is maximal and each
+// tenaga kerja fills at most one opening. Pinned pairs take their openings
+// first; the rest are assigned with the Hungarian algorithm.
+func Assign(input AssignmentInput) (AssignmentResult, error) {
+	if len(input.Vacancies) == 0 {
+		return AssignmentResult{}, errors.New("at least one vacancy is required")
+	}
+	openings := make(map[uint]int, len(input.Vacancies))
+	total := 0
+	for _, v := range input.Vacancies {
+		if v.Openings <= 0 {
+			return AssignmentResult{}, errors.New("openings must be positive")
+		}
+		if _, ok := openings[v.JabatanID]; ok {
+			return AssignmentResult{}, errors.New("duplicate vacancy jabatan")
+		}
+		openings[v.JabatanID] = v.Openings
+		total += v.Openings
+	}
+	if total > MaxAssignmentOpenings {
+		return AssignmentResult{}, errors.New("too many openings")
+	}
+
+	excluded := make(map[Pair]bool, len(input.Excluded))
+	for _, p := range input.Excluded {
+		excluded[p] = true
+	}
+	allowed := func(p Pair) bool {
+		_, scored := input.Scores[p]
+		_, vacant := openings[p.JabatanID]
+		return scored && vacant && !excluded[p]
+	}
+
+	var result AssignmentResult
+	assigned := make(map[uint]bool)
+	for _, p := range input.Pinned {
+		if _, ok := openings[p.JabatanID]; !ok {
+			return AssignmentResult{}, errors.New("pinned jabatan not in vacancies")
+		}
+		if excluded[p] {
+			return AssignmentResult{}, errors.New("pinned pair is excluded")
+		}
+		if _, ok := input.Scores[p]; !ok {
+			return AssignmentResult{}, errors.New("pinned tenaga kerja has no score for jabatan")
+		}
+		if assigned[p.TenagaKerjaID] {
+			return AssignmentResult{}, errors.New("tenaga kerja pinned more than once")
+		}
+		if openings[p.JabatanID] == 0 {
+			return AssignmentResult{}, errors.New("more pins than openings")
+		}
+		openings[p.JabatanID]--
+		assigned[p.TenagaKerjaID] = true
+		result.Assignments = append(result.Assignments, Assignment{Pair: p, Score: input.Scores[p], Pinned: true})
+	}
+
+	// Rows are the remaining openings, columns the unpinned candidates
+	// followed by one empty column per opening
+	var slots []uint
+	for _, v := range input.Vacancies {
+		for i := 0; i < openings[v.JabatanID]; i++ {
+			slots = append(slots, v.JabatanID)
+		}
+	}
+	candidateSet := make(map[uint]bool)
+	for p := range input.Scores {
+		if !assigned[p.TenagaKerjaID] && allowed(p) {
+			candidateSet[p.TenagaKerjaID] = true
+		}
+	}
+	candidates := make([]uint, 0, len(candidateSet))
+	for id := range candidateSet {
+		candidates = append(candidates, id)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+
+	if len(slots) > 0 {
+		// Leaving an opening empty costs 0 and a disallowed pair costs 1, so
+		// the optimum never uses a disallowed pair while an empty column is free
+		cost := make([][]float64, len(slots))
+		for i, jabatanID := range slots {
+			cost[i] = make([]float64, len(candidates)+len(slots))
+			for j, tenagaKerjaID := range candidates {
+				p := Pair{TenagaKerjaID: tenagaKerjaID, JabatanID: jabatanID}
+				if allowed(p) {
+					cost[i][j] = -input.Scores[p]
+				} else {
+					cost[i][j] = 1
+				}
+			}
+		}
+		for i, j := range hungarian(cost) {
+			if j < len(candidates) {
+				p := Pair{TenagaKerjaID: candidates[j], JabatanID: slots[i]}
+				if allowed(p) {
+					openings[p.JabatanID]--
+					result.Assignments = append(result.Assignments, Assignment{Pair: p, Score: input.Scores[p]})
+				}
+			}
+		}
+	}
+
+	order := make(map[uint]int, len(input.Vacancies))
+	for i, v := range input.Vacancies {
+		order[v.JabatanID] = i
+		if openings[v.JabatanID] > 0 {
+			result.Unfilled = append(result.Unfilled, Vacancy{JabatanID: v.JabatanID, Openings: openings[v.JabatanID]})
+		}
+	}
+	sort.SliceStable(result.Assignments, func(i, j int) bool {
+		a, b := result.Assignments[i], result.Assignments[j]
+		if a.JabatanID != b.JabatanID {
+			return order[a.JabatanID] < order[b.JabatanID]
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.TenagaKerjaID < b.TenagaKerjaID
+	})
+
+	for i := range result.Assignments {
+		a := &result.Assignments[i]
+		result.TotalScore += a.Score
+		for _, v := range input.Vacancies {
+			p := Pair{TenagaKerjaID: a.TenagaKerjaID, JabatanID: v.JabatanID}
+			if v.JabatanID == a.JabatanID || !allowed(p) {
+				continue
+			}
+			if a.Alternative == nil || input.Scores[p] > a.Alternative.Score {
+				a.Alternative = &Alternative{JabatanID: v.JabatanID, Score: input.Scores[p]}
+			}
+		}
+	}
+	return result, nil
+}
+
+// hungarian solves the assignment problem for a cost matrix with no more
+// rows than columns and returns the column assigned to each row
+func hungarian(cost [][]float64) []int {
+	n, m := len(cost), len(cost[0])
+	// Potentials and matching are 1-indexed; column 0 is a sentinel
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	p := make([]int, m+1)
+	way := make([]int, m+1)
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]float64, m+1)
+		used := make([]bool, m+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+		for {
+			used[j0] = true
+			i0, delta, j1 := p[j0], math.Inf(1), 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := cost[i0-1][j-1] - u[i0] - v[j]; cur < minv[j] {
+					minv[j], way[j] = cur, j0
+				}
+				if minv[j] < delta {
+					delta, j1 = minv[j], j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	rows := make([]int, n)
+	for j := 1; j <= m; j++ {
+		if p[j] != 0 {
+			rows[p[j]-1] = j - 1
+		}
+	}
+	return rows
+}
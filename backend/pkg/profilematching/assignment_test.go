@@ -0,0 +1,141 @@
+package profilematching
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssign_SpreadsTopCandidate(t *testing.T) {
+	// Tenaga kerja 1 tops both jabatan; giving them jabatan 10 alone would
+	// leave 2 in jabatan 20 for a total of 4 + 1
+	input := AssignmentInput{
+		Vacancies: []Vacancy{{JabatanID: 10, Openings: 1}, {JabatanID: 20, Openings: 1}},
+		Scores: map[Pair]float64{
+			{1, 10}: 4, {1, 20}: 3.5,
+			{2, 10}: 3.8, {2, 20}: 1,
+		},
+	}
+
+	result, err := Assign(input)
+	assert.NoError(t, err)
+	assert.Equal(t, []Assignment{
+		{Pair: Pair{2, 10}, Score: 3.8, Alternative: &Alternative{JabatanID: 20, Score: 1}},
+		{Pair: Pair{1, 20}, Score: 3.5, Alternative: &Alternative{JabatanID: 10, Score: 4}},
+	}, result.Assignments)
+	assert.InDelta(t, 7.3, result.TotalScore, Epsilon)
+	assert.Empty(t, result.Unfilled)
+}
+
+func TestAssign_PinnedAndExcluded(t *testing.T) {
+	input := AssignmentInput{
+		Vacancies: []Vacancy{{JabatanID: 10, Openings: 2}, {JabatanID: 20, Openings: 1}},
+		Scores: map[Pair]float64{
+			{1, 10}: 4, {1, 20}: 4,
+			{2, 10}: 3, {2, 20}: 3,
+			{3, 10}: 2,
+		},
+		Pinned:   []Pair{{3, 10}},
+		Excluded: []Pair{{1, 20}},
+	}
+
+	result, err := Assign(input)
+	assert.NoError(t, err)
+	assert.Len(t, result.Assignments, 3)
+	assert.Equal(t, Pair{1, 10}, result.Assignments[0].Pair)
+	assert.Nil(t, result.Assignments[0].Alternative) // Excluded from jabatan 20
+	assert.Equal(t, Pair{3, 10}, result.Assignments[1].Pair)
+	assert.True(t, result.Assignments[1].Pinned)
+	assert.Equal(t, Pair{2, 20}, result.Assignments[2].Pair)
+}
+
+func TestAssign_Unfilled(t *testing.T) {
+	result, err := Assign(AssignmentInput{
+		Vacancies: []Vacancy{{JabatanID: 10, Openings: 3}, {JabatanID: 20, Openings: 1}},
+		Scores:    map[Pair]float64{{1, 10}: 2, {2, 20}: 3},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result.Assignments, 2)
+	assert.Equal(t, []Vacancy{{JabatanID: 10, Openings: 2}}, result.Unfilled)
+}
+
+func TestAssign_Errors(t *testing.T) {
+	scores := map[Pair]float64{{1, 10}: 2, {2, 10}: 3}
+	tests := []struct {
+		name  string
+		input AssignmentInput
+		err   string
+	}{
+		{"no vacancies", AssignmentInput{}, "at least one vacancy is required"},
+		{"zero openings", AssignmentInput{Vacancies: []Vacancy{{10, 0}}}, "openings must be positive"},
+		{"duplicate", AssignmentInput{Vacancies: []Vacancy{{10, 1}, {10, 1}}}, "duplicate vacancy jabatan"},
+		{"too many", AssignmentInput{Vacancies: []Vacancy{{10, MaxAssignmentOpenings + 1}}}, "too many openings"},
+		{"pin outside vacancies", AssignmentInput{Vacancies: []Vacancy{{10, 1}}, Scores: scores, Pinned: []Pair{{1, 20}}}, "pinned jabatan not in vacancies"},
+		{"pin excluded", AssignmentInput{Vacancies: []Vacancy{{10, 1}}, Scores: scores, Pinned: []Pair{{1, 10}}, Excluded: []Pair{{1, 10}}}, "pinned pair is excluded"},
+		{"pin without score", AssignmentInput{Vacancies: []Vacancy{{10, 1}}, Scores: scores, Pinned: []Pair{{3, 10}}}, "pinned tenaga kerja has no score for jabatan"},
+		{"pinned twice", AssignmentInput{Vacancies: []Vacancy{{10, 2}}, Scores: scores, Pinned: []Pair{{1, 10}, {1, 10}}}, "tenaga kerja pinned more than once"},
+		{"too many pins", AssignmentInput{Vacancies: []Vacancy{{10, 1}}, Scores: scores, Pinned: []Pair{{1, 10}, {2, 10}}}, "more pins than openings"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Assign(tt.input)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestAssign_MatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		input := AssignmentInput{
+			Vacancies: []Vacancy{{JabatanID: 1, Openings: 1 + rng.Intn(2)}, {JabatanID: 2, Openings: 1}, {JabatanID: 3, Openings: 1}},
+			Scores:    make(map[Pair]float64),
+		}
+		for tk := uint(1); tk <= uint(2+rng.Intn(4)); tk++ {
+			for jabatan := uint(1); jabatan <= 3; jabatan++ {
+				if rng.Intn(4) > 0 {
+					input.Scores[Pair{tk, jabatan}] = math.Round(rng.Float64()*500) / 100
+				}
+			}
+		}
+
+		result, err := Assign(input)
+		assert.NoError(t, err)
+		seen := make(map[uint]bool)
+		for _, a := range result.Assignments {
+			assert.False(t, seen[a.TenagaKerjaID], "tenaga kerja assigned twice")
+			seen[a.TenagaKerjaID] = true
+		}
+		assert.InDelta(t, bruteForceAssignment(input), result.TotalScore, Epsilon)
+	}
+}
+
+// bruteForceAssignment tries every way of filling the openings in turn
+func bruteForceAssignment(input AssignmentInput) float64 {
+	var slots []uint
+	for _, v := range input.Vacancies {
+		for i := 0; i < v.Openings; i++ {
+			slots = append(slots, v.JabatanID)
+		}
+	}
+	used := make(map[uint]bool)
+	var best func(slot int) float64
+	best = func(slot int) float64 {
+		if slot == len(slots) {
+			return 0
+		}
+		total := best(slot + 1) // Leave the opening empty
+		for p, score := range input.Scores {
+			if p.JabatanID != slots[slot] || used[p.TenagaKerjaID] {
+				continue
+			}
+			used[p.TenagaKerjaID] = true
+			total = math.Max(total, score+best(slot+1))
+			used[p.TenagaKerjaID] = false
+		}
+		return total
+	}
+	return best(0)
+}
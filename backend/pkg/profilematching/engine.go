@@ -0,0 +1,425 @@
+// Package profilematching implements the profile matching scoring engine.
+// It works on plain inputs so rankings can be computed without a database.
+package profilematching
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Aspek groups kriteria; Persentase is its weight in the total score
+type Aspek struct {
+	ID         uint
+	Nama       string
+	Persentase float64
+	// Ratio overrides the engine's core/secondary ratio when set
+	Ratio *Ratio
+}
+
+// Kriteria is an assessed competency of an aspek, core or secondary
+type Kriteria struct {
+	ID      uint
+	AspekID uint
+	Kode    string
+	Nama    string
+	IsCore  bool
+	Bobot   float64
+}
+
+// Target is the nilai a jabatan requires for a kriteria. A candidate whose
+// nilai is below Minimum, or missing, is knocked out of the ranking.
+type Target struct {
+	KriteriaID uint
+	Nilai      float64
+	Minimum    *float64
+}
+
+// Profile is a jabatan's target profile with the kriteria and aspek it uses.
+// Candidates scoring below MinimumScore, on the ranking method's scale, are
+// knocked out of the ranking.
+type Profile struct {
+	Targets      []Target
+	Kriteria     map[uint]Kriteria
+	Aspek        map[uint]Aspek
+	MinimumScore *float64
+}
+
+// Ratio is the core/secondary factor split, in percent
+type Ratio struct {
+	Core      float64
+	Secondary float64
+}
+
+// DefaultRatio is the standard 60/40 core/secondary split
+var DefaultRatio = Ratio{Core: 60, Secondary: 40}
+
+// Validate requires a non-negative ratio summing to 100%
+func (r Ratio) Validate() error {
+	if r.Core < 0 || r.Secondary < 0 || math.Abs(r.Core+r.Secondary-100) > Epsilon {
+		return errors.New("invalid factor ratio: core and secondary must both be set and sum to 100")
+	}
+	return nil
+}
+
+// DefaultMinimumNilai is the nilai assumed for missing kriteria under the
+// "minimum" policy when none is given
+const DefaultMinimumNilai = 1
+
+// MissingPolicy selects how target kriteria without nilai are handled
+type MissingPolicy struct {
+	Policy       string
+	MinimumNilai float64
+}
+
+// NewMissingPolicy validates the policy: "skip" (default) leaves the
+// kriteria out, "minimum" assumes MinimumNilai, "exclude" drops the
+// candidate from rankings and "incomplete" ranks it after complete ones.
+func NewMissingPolicy(policy string, minimumNilai *float64) (MissingPolicy, error) {
+	switch policy {
+	case "", "skip", "exclude", "incomplete":
+		if policy == "" {
+			policy = "skip"
+		}
+		return MissingPolicy{Policy: policy}, nil
+	case "minimum":
+		p := MissingPolicy{Policy: policy, MinimumNilai: DefaultMinimumNilai}
+		if minimumNilai != nil {
+			p.MinimumNilai = *minimumNilai
+		}
+		return p, nil
+	default:
+		return MissingPolicy{}, errors.New("invalid missing policy")
+	}
+}
+
+// KriteriaResult is the GAP of a candidate's nilai to a target and its weight
+type KriteriaResult struct {
+	Kriteria Kriteria
+	Target   float64
+	Actual   float64
+	Gap      float64
+	// EffectiveGap is the gap after rounding; equal to Gap in other modes
+	EffectiveGap float64
+	Weight       float64
+	// Kontribusi is the Bobot-weighted share of Weight in the aspek's CF or SF
+	Kontribusi float64
+	// Imputed is set when Actual is the minimum nilai, not an assessed one
+	Imputed bool
+}
+
+// AspekResult is a candidate's CF, SF and score for an aspek
+type AspekResult struct {
+	Aspek    Aspek
+	Kriteria []KriteriaResult
+	Ratio    Ratio
+	CF       float64
+	SF       float64
+	Score    float64
+	// Kontribusi is the aspek's Persentase-weighted share of the total score
+	Kontribusi float64
+}
+
+// Result is the evaluation of a candidate against a profile
+type Result struct {
+	Aspek           []AspekResult
+	CoreFactor      float64
+	SecondaryFactor float64
+	TotalScore      float64
+	// KriteriaDinilai of KriteriaTotal target kriteria have a nilai; the
+	// codes of the others are listed in Missing
+	KriteriaDinilai int
+	KriteriaTotal   int
+	Missing         []string
+	// Incomplete is set under the "incomplete" policy when nilai are missing
+	Incomplete bool
+}
+
+// Engine scores candidates against a profile
+type Engine struct {
+	weighter GapWeighter
+	ratio    Ratio
+	missing  MissingPolicy
+	method   string
+	ranking  RankingRule
+}
+
+// New returns an engine using the GAP weighter, the jabatan's core/secondary
+// ratio (aspek may override it) and the missing-nilai policy.
+func New(weighter GapWeighter, ratio Ratio, missing MissingPolicy) *Engine {
+	return &Engine{weighter: weighter, ratio: ratio, missing: missing, method: MethodProfileMatching, ranking: DefaultRankingRule}
+}
+
+// WithMethod returns a copy of the engine ranking with the given method
+func (e *Engine) WithMethod(method string) *Engine {
+	c := *e
+	c.method = method
+	return &c
+}
+
+// WithRanking returns a copy of the engine numbering rankings with the rule
+func (e *Engine) WithRanking(rule RankingRule) *Engine {
+	c := *e
+	c.ranking = rule
+	return &c
+}
+
+// Evaluate computes CF, SF and score per aspek, then combines the aspek
+// scores into a total weighted by Aspek.Persentase. Nilai is keyed by
+// kriteria ID.
+func (e *Engine) Evaluate(profile Profile, nilai map[uint]float64) Result {
+	aspekIndex := make(map[uint]int)
+	var aspekList []AspekResult
+	var result Result
+
+	for _, target := range profile.Targets {
+		kriteria, exists := profile.Kriteria[target.KriteriaID]
+		if !exists {
+			continue
+		}
+		result.KriteriaTotal++
+
+		actual, exists := nilai[target.KriteriaID]
+		imputed := false
+		if exists {
+			result.KriteriaDinilai++
+		} else {
+			result.Missing = append(result.Missing, kriteria.Kode)
+			if e.missing.Policy != "minimum" {
+				continue // Skip if no nilai for this kriteria
+			}
+			actual, imputed = e.missing.MinimumNilai, true
+		}
+
+		idx, exists := aspekIndex[kriteria.AspekID]
+		if !exists {
+			aspek, ok := profile.Aspek[kriteria.AspekID]
+			if !ok {
+				aspek = Aspek{ID: kriteria.AspekID}
+			}
+			idx = len(aspekList)
+			aspekIndex[kriteria.AspekID] = idx
+			aspekList = append(aspekList, AspekResult{Aspek: aspek})
+		}
+
+		// Calculate GAP at the decimal(5,2) precision of the inputs and
+		// convert it to weight
+		gap := math.Round((actual-target.Nilai)*100) / 100
+		effectiveGap, weight := e.weighter.Weight(gap)
+		aspekList[idx].Kriteria = append(aspekList[idx].Kriteria, KriteriaResult{
+			Kriteria:     kriteria,
+			Target:       target.Nilai,
+			Actual:       actual,
+			Gap:          gap,
+			EffectiveGap: effectiveGap,
+			Weight:       weight,
+			Imputed:      imputed,
+		})
+	}
+
+	sort.Slice(aspekList, func(i, j int) bool { return aspekList[i].Aspek.ID < aspekList[j].Aspek.ID })
+
+	var totalPersentase float64
+	for _, a := range aspekList {
+		totalPersentase += a.Aspek.Persentase
+	}
+
+	for i := range aspekList {
+		a := &aspekList[i]
+
+		// CF and SF are averages of the weights, weighted by Kriteria.Bobot
+		var coreBobot, secondaryBobot float64
+		for _, k := range a.Kriteria {
+			if k.Kriteria.IsCore {
+				coreBobot += k.Kriteria.Bobot
+			} else {
+				secondaryBobot += k.Kriteria.Bobot
+			}
+		}
+		for j := range a.Kriteria {
+			k := &a.Kriteria[j]
+			if k.Kriteria.IsCore {
+				k.Kontribusi = k.Weight * bobotShare(k.Kriteria.Bobot, coreBobot, a.Kriteria, true)
+				a.CF += k.Kontribusi
+			} else {
+				k.Kontribusi = k.Weight * bobotShare(k.Kriteria.Bobot, secondaryBobot, a.Kriteria, false)
+				a.SF += k.Kontribusi
+			}
+		}
+
+		// Score for this aspek (core% CF + secondary% SF)
+		a.Ratio = e.ratio
+		if a.Aspek.Ratio != nil {
+			a.Ratio = *a.Aspek.Ratio
+		}
+		a.Score = (a.Ratio.Core/100)*a.CF + (a.Ratio.Secondary/100)*a.SF
+
+		// Share of this aspek in the total; aspek without Persentase weigh
+		// equally when none of the evaluated aspek has one
+		share := 1.0 / float64(len(aspekList))
+		if totalPersentase > 0 {
+			share = a.Aspek.Persentase / totalPersentase
+		}
+		a.Kontribusi = share * a.Score
+
+		result.CoreFactor += share * a.CF
+		result.SecondaryFactor += share * a.SF
+		result.TotalScore += a.Kontribusi
+	}
+	result.Aspek = aspekList
+	result.Incomplete = e.missing.Policy == "incomplete" && len(result.Missing) > 0
+
+	return result
+}
+
+// bobotShare returns the share of a kriteria inside its core or secondary
+// group. Groups whose Bobot sum to zero fall back to a plain average.
+func bobotShare(bobot, groupBobot float64, group []KriteriaResult, isCore bool) float64 {
+	if groupBobot > 0 {
+		return bobot / groupBobot
+	}
+	var count int
+	for _, k := range group {
+		if k.Kriteria.IsCore == isCore {
+			count++
+		}
+	}
+	return 1 / float64(count)
+}
+
+// Candidate is a tenaga kerja to rank, with nilai keyed by kriteria ID. NIK
+// is only used by the NIK tie-breaker.
+type Candidate struct {
+	ID    uint
+	NIK   string
+	Nilai map[uint]float64
+}
+
+// RankedCandidate is a candidate with its rank and evaluation
+type RankedCandidate struct {
+	ID   uint
+	NIK  string
+	Rank int
+	// Score is the ranking method's score; for profile matching it equals
+	// Result.TotalScore, the other methods score on a 0-100 scale
+	Score  float64
+	Result Result
+}
+
+// KnockoutFailure is a target minimum a candidate falls short of; Nilai is
+// nil when the candidate has no nilai for the kriteria
+type KnockoutFailure struct {
+	KriteriaID uint
+	Minimum    float64
+	Nilai      *float64
+}
+
+// IneligibleCandidate is a candidate knocked out of the ranking, either by
+// target minimums or, when they all pass, by the profile's minimum score
+type IneligibleCandidate struct {
+	ID       uint
+	NIK      string
+	Failures []KnockoutFailure
+	// BelowMinimumScore is set with the Score that fell short
+	BelowMinimumScore bool
+	Score             float64
+	Result            Result
+}
+
+// Knockouts returns the target minimums the nilai fall short of
+func (p Profile) Knockouts(nilai map[uint]float64) []KnockoutFailure {
+	var failures []KnockoutFailure
+	for _, t := range p.Targets {
+		if t.Minimum == nil {
+			continue
+		}
+		v, ok := nilai[t.KriteriaID]
+		if !ok {
+			failures = append(failures, KnockoutFailure{KriteriaID: t.KriteriaID, Minimum: *t.Minimum})
+		} else if v < *t.Minimum-Epsilon {
+			failures = append(failures, KnockoutFailure{KriteriaID: t.KriteriaID, Minimum: *t.Minimum, Nilai: &v})
+		}
+	}
+	return failures
+}
+
+// Rank ranks the eligible candidates; see RankEligible
+func (e *Engine) Rank(profile Profile, candidates []Candidate) []RankedCandidate {
+	ranked, _ := e.RankEligible(profile, candidates)
+	return ranked
+}
+
+// RankEligible knocks out the candidates failing a target minimum, ranks
+// the others and then knocks out those below the minimum score, numbering
+// the remaining ranks again. Scores of methods relative to the field are
+// computed among the candidates passing the target minimums. Ineligible
+// candidates are returned by ID.
+func (e *Engine) RankEligible(profile Profile, candidates []Candidate) ([]RankedCandidate, []IneligibleCandidate) {
+	var eligible []Candidate
+	var ineligible []IneligibleCandidate
+	for _, c := range candidates {
+		if failures := profile.Knockouts(c.Nilai); len(failures) > 0 {
+			ineligible = append(ineligible, IneligibleCandidate{
+				ID:       c.ID,
+				NIK:      c.NIK,
+				Failures: failures,
+				Result:   e.Evaluate(profile, c.Nilai),
+			})
+			continue
+		}
+		eligible = append(eligible, c)
+	}
+
+	ranked := e.rank(profile, eligible)
+	if profile.MinimumScore != nil {
+		kept := ranked[:0]
+		for _, r := range ranked {
+			if r.Score < *profile.MinimumScore-Epsilon {
+				ineligible = append(ineligible, IneligibleCandidate{
+					ID:                r.ID,
+					NIK:               r.NIK,
+					BelowMinimumScore: true,
+					Score:             r.Score,
+					Result:            r.Result,
+				})
+				continue
+			}
+			kept = append(kept, r)
+		}
+		ranked = kept
+		e.ranking.assignRanks(ranked)
+	}
+
+	sort.Slice(ineligible, func(i, j int) bool { return ineligible[i].ID < ineligible[j].ID })
+	return ranked, ineligible
+}
+
+// rank evaluates the candidates and orders them by the ranking method's
+// score, with incomplete results last, then by the ranking rule's
+// tie-breakers and candidate ID. Under the "exclude" policy candidates
+// missing a nilai are left out.
+func (e *Engine) rank(profile Profile, candidates []Candidate) []RankedCandidate {
+	ranked := make([]RankedCandidate, 0, len(candidates))
+	var results []Result
+	for _, c := range candidates {
+		result := e.Evaluate(profile, c.Nilai)
+		if e.missing.Policy == "exclude" && len(result.Missing) > 0 {
+			continue
+		}
+		ranked = append(ranked, RankedCandidate{ID: c.ID, NIK: c.NIK, Result: result})
+		results = append(results, result)
+	}
+
+	for i, score := range methodScores(e.method, profile, results) {
+		ranked[i].Score = score
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if c := e.ranking.compare(ranked[i], ranked[j]); c != 0 {
+			return c < 0
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	e.ranking.assignRanks(ranked)
+	return ranked
+}
@@ -0,0 +1,162 @@
+package profilematching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEngine(ratio Ratio, missing MissingPolicy) *Engine {
+	return New(NewGapWeighter(DefaultGapTable(), GapMode{Mode: "exact"}), ratio, missing)
+}
+
+// singleAspekProfile has one aspek with two core kriteria K1 and K2, both
+// targeting nilai 3
+func singleAspekProfile(aspek Aspek, bobot1, bobot2 float64) Profile {
+	return Profile{
+		Targets: []Target{{KriteriaID: 1, Nilai: 3}, {KriteriaID: 2, Nilai: 3}},
+		Kriteria: map[uint]Kriteria{
+			1: {ID: 1, AspekID: aspek.ID, Kode: "K1", IsCore: true, Bobot: bobot1},
+			2: {ID: 2, AspekID: aspek.ID, Kode: "K2", IsCore: true, Bobot: bobot2},
+		},
+		Aspek: map[uint]Aspek{aspek.ID: aspek},
+	}
+}
+
+func TestEvaluate_WeightsAspekByPersentase(t *testing.T) {
+	profile := Profile{
+		Targets: []Target{
+			{KriteriaID: 1, Nilai: 4},
+			{KriteriaID: 2, Nilai: 3},
+			{KriteriaID: 3, Nilai: 3},
+		},
+		Kriteria: map[uint]Kriteria{
+			1: {ID: 1, AspekID: 1, Kode: "K1", IsCore: true},
+			2: {ID: 2, AspekID: 1, Kode: "K2", IsCore: false},
+			3: {ID: 3, AspekID: 2, Kode: "S1", IsCore: true},
+		},
+		Aspek: map[uint]Aspek{
+			1: {ID: 1, Nama: "Teknis", Persentase: 60},
+			2: {ID: 2, Nama: "Sikap", Persentase: 40},
+		},
+	}
+	// K1 gap 0 -> 5, K2 gap -1 -> 4, S1 gap -2 -> 3
+	nilai := map[uint]float64{1: 4, 2: 2, 3: 1}
+
+	result := newTestEngine(DefaultRatio, MissingPolicy{Policy: "skip"}).Evaluate(profile, nilai)
+
+	assert.Len(t, result.Aspek, 2)
+	teknisScore := 0.6*5 + 0.4*4
+	sikapScore := 0.6 * 3
+	assert.InDelta(t, teknisScore, result.Aspek[0].Score, 1e-9)
+	assert.InDelta(t, sikapScore, result.Aspek[1].Score, 1e-9)
+	assert.InDelta(t, 0.6*teknisScore+0.4*sikapScore, result.TotalScore, 1e-9)
+	assert.InDelta(t, result.TotalScore, result.Aspek[0].Kontribusi+result.Aspek[1].Kontribusi, 1e-9)
+}
+
+func TestEvaluate_AspekRatioOverride(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Nama: "Teknis", Persentase: 100, Ratio: &Ratio{Core: 80, Secondary: 20}}, 0, 0)
+	profile.Kriteria[2] = Kriteria{ID: 2, AspekID: 1, Kode: "K2", IsCore: false}
+	nilai := map[uint]float64{1: 3, 2: 1}
+
+	result := newTestEngine(Ratio{Core: 50, Secondary: 50}, MissingPolicy{Policy: "skip"}).Evaluate(profile, nilai)
+
+	assert.Equal(t, 80.0, result.Aspek[0].Ratio.Core)
+	assert.InDelta(t, 0.8*5+0.2*3, result.TotalScore, 1e-9)
+}
+
+func TestEvaluate_WeightsKriteriaByBobot(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Nama: "Teknis", Persentase: 100}, 3, 1)
+	// K1 gap 0 -> 5, K2 gap -2 -> 3
+	nilai := map[uint]float64{1: 3, 2: 1}
+
+	result := newTestEngine(DefaultRatio, MissingPolicy{Policy: "skip"}).Evaluate(profile, nilai)
+
+	assert.InDelta(t, (3*5.0+1*3.0)/4, result.Aspek[0].CF, 1e-9)
+	assert.InDelta(t, 3*5.0/4, result.Aspek[0].Kriteria[0].Kontribusi, 1e-9)
+	assert.InDelta(t, 1*3.0/4, result.Aspek[0].Kriteria[1].Kontribusi, 1e-9)
+}
+
+func TestEvaluate_MissingPolicy(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Nama: "Teknis", Persentase: 100}, 1, 1)
+	nilai := map[uint]float64{1: 3}
+
+	skip, err := NewMissingPolicy("", nil)
+	assert.NoError(t, err)
+	result := newTestEngine(DefaultRatio, skip).Evaluate(profile, nilai)
+	assert.Equal(t, 1, result.KriteriaDinilai)
+	assert.Equal(t, 2, result.KriteriaTotal)
+	assert.Equal(t, []string{"K2"}, result.Missing)
+	assert.InDelta(t, 5.0, result.Aspek[0].CF, 1e-9)
+	assert.False(t, result.Incomplete)
+
+	// K2 is assumed at nilai 1: gap -2 -> 3
+	minimum, err := NewMissingPolicy("minimum", nil)
+	assert.NoError(t, err)
+	result = newTestEngine(DefaultRatio, minimum).Evaluate(profile, nilai)
+	assert.Equal(t, 1, result.KriteriaDinilai)
+	assert.InDelta(t, (5.0+3.0)/2, result.Aspek[0].CF, 1e-9)
+	assert.True(t, result.Aspek[0].Kriteria[1].Imputed)
+
+	_, err = NewMissingPolicy("ignore", nil)
+	assert.Error(t, err)
+}
+
+func TestRank(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Nama: "Teknis", Persentase: 100}, 1, 1)
+	candidates := []Candidate{
+		{ID: 1, Nilai: map[uint]float64{1: 2, 2: 2}},
+		{ID: 2, Nilai: map[uint]float64{1: 3}},
+		{ID: 3, Nilai: map[uint]float64{1: 3, 2: 3}},
+	}
+
+	incomplete, _ := NewMissingPolicy("incomplete", nil)
+	ranked := newTestEngine(DefaultRatio, incomplete).Rank(profile, candidates)
+	assert.Len(t, ranked, 3)
+	assert.Equal(t, []uint{3, 1, 2}, []uint{ranked[0].ID, ranked[1].ID, ranked[2].ID})
+	assert.Equal(t, 3, ranked[2].Rank)
+	assert.True(t, ranked[2].Result.Incomplete)
+
+	exclude, _ := NewMissingPolicy("exclude", nil)
+	ranked = newTestEngine(DefaultRatio, exclude).Rank(profile, candidates)
+	assert.Len(t, ranked, 2)
+}
+
+func TestRankEligible(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Nama: "Teknis", Persentase: 100}, 1, 1)
+	minimum := 3.0
+	profile.Targets[0].Minimum = &minimum
+	candidates := []Candidate{
+		{ID: 1, Nilai: map[uint]float64{1: 2, 2: 3}},
+		{ID: 2, Nilai: map[uint]float64{2: 3}},
+		{ID: 3, Nilai: map[uint]float64{1: 3, 2: 3}},
+		{ID: 4, Nilai: map[uint]float64{1: 3, 2: 1}},
+		{ID: 5, Nilai: map[uint]float64{1: 4, 2: 3}},
+	}
+	engine := newTestEngine(DefaultRatio, MissingPolicy{Policy: "skip"})
+
+	// Candidates 1 and 2 fail the K1 minimum however they score on K2
+	ranked, ineligible := engine.RankEligible(profile, candidates)
+	assert.Equal(t, []uint{3, 5, 4}, []uint{ranked[0].ID, ranked[1].ID, ranked[2].ID})
+	assert.Len(t, ineligible, 2)
+	nilai := 2.0
+	assert.Equal(t, []KnockoutFailure{{KriteriaID: 1, Minimum: 3, Nilai: &nilai}}, ineligible[0].Failures)
+	assert.Equal(t, []KnockoutFailure{{KriteriaID: 1, Minimum: 3}}, ineligible[1].Failures)
+
+	// A minimum score between the second and third knocks out the third
+	minimumScore := (ranked[1].Score + ranked[2].Score) / 2
+	profile.MinimumScore = &minimumScore
+	ranked, ineligible = engine.RankEligible(profile, candidates)
+	assert.Len(t, ranked, 2)
+	assert.Equal(t, 2, ranked[1].Rank)
+	assert.Len(t, ineligible, 3)
+	assert.Equal(t, uint(4), ineligible[2].ID)
+	assert.True(t, ineligible[2].BelowMinimumScore)
+	assert.Empty(t, ineligible[2].Failures)
+}
+
+func TestRatio_Validate(t *testing.T) {
+	assert.NoError(t, Ratio{Core: 70, Secondary: 30}.Validate())
+	assert.Error(t, Ratio{Core: 70, Secondary: 40}.Validate())
+	assert.Error(t, Ratio{Core: 110, Secondary: -10}.Validate())
+}
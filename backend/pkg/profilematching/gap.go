@@ -0,0 +1,150 @@
+package profilematching
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Epsilon is the tolerance used when comparing gaps and percentages, since
+// nilai and target are stored as decimal(5,2).
+const Epsilon = 1e-6
+
+// GapEntry maps a GAP (nilai - target) to a bobot nilai
+type GapEntry struct {
+	Gap   float64
+	Bobot float64
+}
+
+// GapTable is a GAP weight table. Gaps outside the range covered by the
+// entries are handled per side by BelowRange and AboveRange: "zero" gives a
+// weight of 0, "clamp" reuses the nearest entry.
+type GapTable struct {
+	Entries    []GapEntry
+	BelowRange string
+	AboveRange string
+}
+
+// DefaultGapTable returns the standard profile matching conversion
+func DefaultGapTable() GapTable {
+	return GapTable{
+		Entries: []GapEntry{
+			{Gap: -4, Bobot: 1},  // Competency lack 4 levels
+			{Gap: -3, Bobot: 2},  // Competency lack 3 levels
+			{Gap: -2, Bobot: 3},  // Competency lack 2 levels
+			{Gap: -1, Bobot: 4},  // Competency lack 1 level
+			{Gap: 0, Bobot: 5},   // No difference
+			{Gap: 1, Bobot: 4.5}, // Competency excess 1 level
+			{Gap: 2, Bobot: 3.5}, // Competency excess 2 levels
+			{Gap: 3, Bobot: 2.5}, // Competency excess 3 levels
+			{Gap: 4, Bobot: 1.5}, // Competency excess 4 levels
+		},
+		BelowRange: "zero",
+		AboveRange: "zero",
+	}
+}
+
+// GapMode selects how a gap is converted to a weight
+type GapMode struct {
+	Mode         string
+	RoundingRule string
+}
+
+// NewGapMode validates the mode ("exact" by default, "interpolate" or
+// "round") and, for the round mode, the rounding rule ("nearest" by default,
+// "half_up", "half_down", "floor" or "ceil").
+func NewGapMode(mode, roundingRule string) (GapMode, error) {
+	switch mode {
+	case "", "exact", "interpolate":
+		if mode == "" {
+			mode = "exact"
+		}
+		return GapMode{Mode: mode}, nil
+	case "round":
+		switch roundingRule {
+		case "":
+			roundingRule = "nearest"
+		case "nearest", "half_up", "half_down", "floor", "ceil":
+		default:
+			return GapMode{}, errors.New("invalid rounding rule")
+		}
+		return GapMode{Mode: mode, RoundingRule: roundingRule}, nil
+	default:
+		return GapMode{}, errors.New("invalid gap mode")
+	}
+}
+
+// round applies the rounding rule to the gap
+func (m GapMode) round(gap float64) float64 {
+	switch m.RoundingRule {
+	case "half_up":
+		return math.Floor(gap + 0.5)
+	case "half_down":
+		return math.Ceil(gap - 0.5)
+	case "floor":
+		return math.Floor(gap)
+	case "ceil":
+		return math.Ceil(gap)
+	default:
+		return math.Round(gap)
+	}
+}
+
+// GapWeighter converts GAP to weight using a GAP weight table
+type GapWeighter struct {
+	entries    []GapEntry // sorted by gap ascending
+	belowRange string
+	aboveRange string
+	mode       GapMode
+}
+
+// NewGapWeighter returns a weighter for the table, falling back to the
+// standard conversion when the table has no entries.
+func NewGapWeighter(table GapTable, mode GapMode) GapWeighter {
+	if len(table.Entries) == 0 {
+		table = DefaultGapTable()
+	}
+
+	entries := make([]GapEntry, len(table.Entries))
+	copy(entries, table.Entries)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Gap < entries[j].Gap })
+
+	return GapWeighter{entries: entries, belowRange: table.BelowRange, aboveRange: table.AboveRange, mode: mode}
+}
+
+// Weight returns the effective gap and its bobot nilai. In exact mode gaps
+// inside the table's range without an exact entry weigh 0; interpolate mode
+// weighs them linearly between the surrounding entries and round mode rounds
+// the gap first.
+func (w GapWeighter) Weight(gap float64) (float64, float64) {
+	if w.mode.Mode == "round" {
+		gap = w.mode.round(gap)
+	}
+
+	first := w.entries[0]
+	last := w.entries[len(w.entries)-1]
+
+	if gap < first.Gap-Epsilon {
+		if w.belowRange == "clamp" {
+			return gap, first.Bobot
+		}
+		return gap, 0
+	}
+	if gap > last.Gap+Epsilon {
+		if w.aboveRange == "clamp" {
+			return gap, last.Bobot
+		}
+		return gap, 0
+	}
+
+	for i, e := range w.entries {
+		if math.Abs(e.Gap-gap) < Epsilon {
+			return gap, e.Bobot
+		}
+		if w.mode.Mode == "interpolate" && i > 0 && gap < e.Gap {
+			prev := w.entries[i-1]
+			return gap, prev.Bobot + (gap-prev.Gap)/(e.Gap-prev.Gap)*(e.Bobot-prev.Bobot)
+		}
+	}
+	return gap, 0
+}
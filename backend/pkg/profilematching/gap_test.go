@@ -0,0 +1,76 @@
+package profilematching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func weightOf(weighter GapWeighter, gap float64) float64 {
+	_, weight := weighter.Weight(gap)
+	return weight
+}
+
+func TestGapWeighter_DefaultTable(t *testing.T) {
+	weighter := NewGapWeighter(GapTable{}, GapMode{Mode: "exact"})
+
+	assert.Equal(t, 5.0, weightOf(weighter, 0))
+	assert.Equal(t, 4.5, weightOf(weighter, 1))
+	assert.Equal(t, 4.0, weightOf(weighter, -1))
+	assert.Equal(t, 1.0, weightOf(weighter, -4))
+	assert.Equal(t, 0.0, weightOf(weighter, -5))
+	assert.Equal(t, 0.0, weightOf(weighter, 5))
+}
+
+func TestGapWeighter_AsymmetricClamp(t *testing.T) {
+	weighter := NewGapWeighter(GapTable{
+		BelowRange: "zero",
+		AboveRange: "clamp",
+		Entries: []GapEntry{
+			{Gap: 2, Bobot: 4},
+			{Gap: 0, Bobot: 5},
+			{Gap: -1, Bobot: 2},
+		},
+	}, GapMode{Mode: "exact"})
+
+	assert.Equal(t, 2.0, weightOf(weighter, -1))
+	assert.Equal(t, 0.0, weightOf(weighter, -2))
+	assert.Equal(t, 4.0, weightOf(weighter, 3))
+	assert.Equal(t, 0.0, weightOf(weighter, 1))
+}
+
+func TestGapWeighter_Interpolate(t *testing.T) {
+	mode, err := NewGapMode("interpolate", "")
+	assert.NoError(t, err)
+	weighter := NewGapWeighter(DefaultGapTable(), mode)
+
+	assert.InDelta(t, 4.75, weightOf(weighter, 0.5), 1e-9)
+	assert.InDelta(t, 4.5, weightOf(weighter, -0.5), 1e-9)
+	assert.Equal(t, 5.0, weightOf(weighter, 0))
+	assert.Equal(t, 0.0, weightOf(weighter, -4.5))
+}
+
+func TestGapWeighter_Round(t *testing.T) {
+	tests := []struct {
+		rule         string
+		gap          float64
+		effectiveGap float64
+	}{
+		{"nearest", 0.5, 1},
+		{"nearest", -0.5, -1},
+		{"half_up", -0.5, 0},
+		{"half_down", 0.5, 0},
+		{"floor", 0.75, 0},
+		{"ceil", -0.75, 0},
+	}
+
+	for _, tt := range tests {
+		mode, err := NewGapMode("round", tt.rule)
+		assert.NoError(t, err)
+		effectiveGap, _ := NewGapWeighter(DefaultGapTable(), mode).Weight(tt.gap)
+		assert.Equal(t, tt.effectiveGap, effectiveGap, "%s(%v)", tt.rule, tt.gap)
+	}
+
+	_, err := NewGapMode("round", "banker")
+	assert.Error(t, err)
+}
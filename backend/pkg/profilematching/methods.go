@@ -0,0 +1,215 @@
+package profilematching
+
+import (
+	"errors"
+	"math"
+)
+
+// Ranking methods. The alternatives to profile matching score the same GAP
+// weights as a decision matrix with one column per target kriteria.
+const (
+	MethodProfileMatching = "profile_matching"
+	MethodSAW             = "saw"
+	MethodWP              = "wp"
+	MethodTOPSIS          = "topsis"
+)
+
+// ValidateMethod normalizes the method name, defaulting to profile matching
+func ValidateMethod(method string) (string, error) {
+	switch method {
+	case "":
+		return MethodProfileMatching, nil
+	case MethodProfileMatching, MethodSAW, MethodWP, MethodTOPSIS:
+		return method, nil
+	default:
+		return "", errors.New("invalid method")
+	}
+}
+
+// CriteriaWeights returns the weight of each target kriteria in the decision
+// matrix: the aspek's share of Persentase times the kriteria's share of
+// Bobot inside its aspek. Shares fall back to equal splits when the
+// Persentase or Bobot sum to zero. The weights sum to 1.
+func CriteriaWeights(profile Profile) map[uint]float64 {
+	aspekKriteria := make(map[uint][]Kriteria)
+	var aspekOrder []uint
+	for _, target := range profile.Targets {
+		kriteria, exists := profile.Kriteria[target.KriteriaID]
+		if !exists {
+			continue
+		}
+		if _, seen := aspekKriteria[kriteria.AspekID]; !seen {
+			aspekOrder = append(aspekOrder, kriteria.AspekID)
+		}
+		aspekKriteria[kriteria.AspekID] = append(aspekKriteria[kriteria.AspekID], kriteria)
+	}
+
+	var totalPersentase float64
+	for _, id := range aspekOrder {
+		totalPersentase += profile.Aspek[id].Persentase
+	}
+
+	weights := make(map[uint]float64)
+	for _, id := range aspekOrder {
+		aspekShare := 1.0 / float64(len(aspekOrder))
+		if totalPersentase > 0 {
+			aspekShare = profile.Aspek[id].Persentase / totalPersentase
+		}
+
+		var totalBobot float64
+		for _, k := range aspekKriteria[id] {
+			totalBobot += k.Bobot
+		}
+		for _, k := range aspekKriteria[id] {
+			bobotShare := 1.0 / float64(len(aspekKriteria[id]))
+			if totalBobot > 0 {
+				bobotShare = k.Bobot / totalBobot
+			}
+			weights[k.ID] += aspekShare * bobotShare
+		}
+	}
+	return weights
+}
+
+// decisionMatrix holds the GAP weight of each candidate (rows) for each
+// target kriteria (columns). Kriteria left out of an evaluation count as 0.
+type decisionMatrix struct {
+	weights []float64
+	rows    [][]float64
+}
+
+func newDecisionMatrix(profile Profile, results []Result) decisionMatrix {
+	criteriaWeights := CriteriaWeights(profile)
+
+	var columns []uint
+	column := make(map[uint]int)
+	for _, target := range profile.Targets {
+		if _, exists := profile.Kriteria[target.KriteriaID]; !exists {
+			continue
+		}
+		if _, seen := column[target.KriteriaID]; seen {
+			continue
+		}
+		column[target.KriteriaID] = len(columns)
+		columns = append(columns, target.KriteriaID)
+	}
+
+	m := decisionMatrix{weights: make([]float64, len(columns)), rows: make([][]float64, len(results))}
+	for j, id := range columns {
+		m.weights[j] = criteriaWeights[id]
+	}
+	for i, result := range results {
+		row := make([]float64, len(columns))
+		for _, a := range result.Aspek {
+			for _, k := range a.Kriteria {
+				row[column[k.Kriteria.ID]] = k.Weight
+			}
+		}
+		m.rows[i] = row
+	}
+	return m
+}
+
+// methodScores scores each result with the method, on a 0-100 scale
+func methodScores(method string, profile Profile, results []Result) []float64 {
+	scores := make([]float64, len(results))
+	if method == MethodProfileMatching {
+		for i, r := range results {
+			scores[i] = r.TotalScore
+		}
+		return scores
+	}
+
+	m := newDecisionMatrix(profile, results)
+	switch method {
+	case MethodSAW:
+		m.saw(scores)
+	case MethodWP:
+		m.wp(scores)
+	case MethodTOPSIS:
+		m.topsis(scores)
+	}
+	for i := range scores {
+		scores[i] *= 100
+	}
+	return scores
+}
+
+// saw sums the weighted values normalized by each column's maximum
+func (m decisionMatrix) saw(scores []float64) {
+	for j, w := range m.weights {
+		var max float64
+		for _, row := range m.rows {
+			max = math.Max(max, row[j])
+		}
+		if max == 0 {
+			continue
+		}
+		for i, row := range m.rows {
+			scores[i] += w * row[j] / max
+		}
+	}
+}
+
+// wp multiplies the values raised to their weights and divides each product
+// by the sum of all products
+func (m decisionMatrix) wp(scores []float64) {
+	var total float64
+	for i, row := range m.rows {
+		s := 1.0
+		for j, w := range m.weights {
+			s *= math.Pow(row[j], w)
+		}
+		scores[i] = s
+		total += s
+	}
+	if total == 0 {
+		return
+	}
+	for i := range scores {
+		scores[i] /= total
+	}
+}
+
+// topsis scores the relative closeness to the ideal solution of the weighted,
+// vector-normalized matrix
+func (m decisionMatrix) topsis(scores []float64) {
+	weighted := make([][]float64, len(m.rows))
+	for i := range m.rows {
+		weighted[i] = make([]float64, len(m.weights))
+	}
+	ideal := make([]float64, len(m.weights))
+	antiIdeal := make([]float64, len(m.weights))
+	for j, w := range m.weights {
+		var sumSquares float64
+		for _, row := range m.rows {
+			sumSquares += row[j] * row[j]
+		}
+		norm := math.Sqrt(sumSquares)
+		for i, row := range m.rows {
+			if norm > 0 {
+				weighted[i][j] = w * row[j] / norm
+			}
+			if i == 0 || weighted[i][j] > ideal[j] {
+				ideal[j] = weighted[i][j]
+			}
+			if i == 0 || weighted[i][j] < antiIdeal[j] {
+				antiIdeal[j] = weighted[i][j]
+			}
+		}
+	}
+
+	for i, row := range weighted {
+		var toIdeal, toAntiIdeal float64
+		for j, v := range row {
+			toIdeal += (v - ideal[j]) * (v - ideal[j])
+			toAntiIdeal += (v - antiIdeal[j]) * (v - antiIdeal[j])
+		}
+		toIdeal, toAntiIdeal = math.Sqrt(toIdeal), math.Sqrt(toAntiIdeal)
+		if toIdeal+toAntiIdeal == 0 {
+			scores[i] = 1 // every candidate equals the ideal solution
+			continue
+		}
+		scores[i] = toAntiIdeal / (toIdeal + toAntiIdeal)
+	}
+}
@@ -0,0 +1,89 @@
+package profilematching
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A has GAP weights (5, 3) and B has (4, 4) on two equally weighted kriteria
+func methodTestCandidates() []Candidate {
+	return []Candidate{
+		{ID: 1, Nilai: map[uint]float64{1: 3, 2: 1}},
+		{ID: 2, Nilai: map[uint]float64{1: 2, 2: 2}},
+	}
+}
+
+func scoreByID(ranked []RankedCandidate) map[uint]float64 {
+	scores := make(map[uint]float64)
+	for _, r := range ranked {
+		scores[r.ID] = r.Score
+	}
+	return scores
+}
+
+func TestValidateMethod(t *testing.T) {
+	method, err := ValidateMethod("")
+	assert.NoError(t, err)
+	assert.Equal(t, MethodProfileMatching, method)
+
+	_, err = ValidateMethod("ahp")
+	assert.Error(t, err)
+}
+
+func TestCriteriaWeights(t *testing.T) {
+	profile := Profile{
+		Targets: []Target{{KriteriaID: 1, Nilai: 3}, {KriteriaID: 2, Nilai: 3}, {KriteriaID: 3, Nilai: 3}},
+		Kriteria: map[uint]Kriteria{
+			1: {ID: 1, AspekID: 1, Bobot: 3},
+			2: {ID: 2, AspekID: 1, Bobot: 1},
+			3: {ID: 3, AspekID: 2},
+		},
+		Aspek: map[uint]Aspek{1: {ID: 1, Persentase: 60}, 2: {ID: 2, Persentase: 40}},
+	}
+
+	weights := CriteriaWeights(profile)
+	assert.InDelta(t, 0.45, weights[1], 1e-9)
+	assert.InDelta(t, 0.15, weights[2], 1e-9)
+	assert.InDelta(t, 0.4, weights[3], 1e-9)
+}
+
+func TestRank_SAW(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Persentase: 100}, 1, 1)
+	engine := newTestEngine(DefaultRatio, MissingPolicy{Policy: "skip"}).WithMethod(MethodSAW)
+
+	ranked := engine.Rank(profile, methodTestCandidates())
+	scores := scoreByID(ranked)
+
+	assert.InDelta(t, 100*(0.5*5/5+0.5*3/4), scores[1], 1e-9)
+	assert.InDelta(t, 100*(0.5*4/5+0.5*4/4), scores[2], 1e-9)
+	assert.Equal(t, uint(2), ranked[0].ID)
+}
+
+func TestRank_WP(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Persentase: 100}, 1, 1)
+	engine := newTestEngine(DefaultRatio, MissingPolicy{Policy: "skip"}).WithMethod(MethodWP)
+
+	scores := scoreByID(engine.Rank(profile, methodTestCandidates()))
+
+	a, b := math.Sqrt(5*3), math.Sqrt(4*4)
+	assert.InDelta(t, 100*a/(a+b), scores[1], 1e-9)
+	assert.InDelta(t, 100*b/(a+b), scores[2], 1e-9)
+}
+
+func TestRank_TOPSIS(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Persentase: 100}, 1, 1)
+	engine := newTestEngine(DefaultRatio, MissingPolicy{Policy: "skip"}).WithMethod(MethodTOPSIS)
+	candidates := append(methodTestCandidates(), Candidate{ID: 3, Nilai: map[uint]float64{1: 3, 2: 3}})
+
+	ranked := engine.Rank(profile, candidates)
+	scores := scoreByID(ranked)
+
+	// Candidate 3 scores 5 on both kriteria and is the ideal solution
+	assert.Equal(t, uint(3), ranked[0].ID)
+	assert.InDelta(t, 100, scores[3], 1e-9)
+	for _, s := range scores {
+		assert.True(t, s >= 0 && s <= 100)
+	}
+}
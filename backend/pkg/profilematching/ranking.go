@@ -0,0 +1,143 @@
+package profilematching
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// Ranking styles, shown for scores 90, 80, 80, 70
+const (
+	RankOrdinal     = "ordinal"     // 1, 2, 3, 4
+	RankCompetition = "competition" // 1, 2, 2, 4
+	RankDense       = "dense"       // 1, 2, 2, 3
+)
+
+// Tie-breakers ordering candidates with equal scores. Factors and kriteria
+// weights prefer higher values, NIK sorts ascending.
+const (
+	TieBreakCoreFactor      = "core_factor"
+	TieBreakSecondaryFactor = "secondary_factor"
+	TieBreakNIK             = "nik"
+	// TieBreakKriteriaPrefix is followed by a kriteria code, e.g. "kriteria:K1",
+	// and compares the GAP weight of that kriteria
+	TieBreakKriteriaPrefix = "kriteria:"
+)
+
+// RankingRule numbers a ranking. Candidates equal on score and on every
+// tie-breaker are tied; ties share a rank except in the ordinal style, where
+// they are ordered by candidate ID.
+type RankingRule struct {
+	Style       string
+	TieBreakers []string
+}
+
+// DefaultRankingRule numbers candidates 1..n without tie-breakers
+var DefaultRankingRule = RankingRule{Style: RankOrdinal}
+
+// NewRankingRule validates the style (ordinal by default) and tie-breakers
+func NewRankingRule(style string, tieBreakers []string) (RankingRule, error) {
+	switch style {
+	case "":
+		style = RankOrdinal
+	case RankOrdinal, RankCompetition, RankDense:
+	default:
+		return RankingRule{}, errors.New("invalid ranking style")
+	}
+	for _, tb := range tieBreakers {
+		switch {
+		case tb == TieBreakCoreFactor, tb == TieBreakSecondaryFactor, tb == TieBreakNIK:
+		case strings.HasPrefix(tb, TieBreakKriteriaPrefix) && len(tb) > len(TieBreakKriteriaPrefix):
+		default:
+			return RankingRule{}, errors.New("invalid tie breaker")
+		}
+	}
+	return RankingRule{Style: style, TieBreakers: tieBreakers}, nil
+}
+
+// KriteriaCodes returns the kriteria codes used as tie-breakers
+func (r RankingRule) KriteriaCodes() []string {
+	var codes []string
+	for _, tb := range r.TieBreakers {
+		if strings.HasPrefix(tb, TieBreakKriteriaPrefix) {
+			codes = append(codes, strings.TrimPrefix(tb, TieBreakKriteriaPrefix))
+		}
+	}
+	return codes
+}
+
+// compare orders two ranked candidates: complete before incomplete results,
+// then higher scores, then the tie-breakers. It returns 0 for a tie.
+func (r RankingRule) compare(a, b RankedCandidate) int {
+	if a.Result.Incomplete != b.Result.Incomplete {
+		if a.Result.Incomplete {
+			return 1
+		}
+		return -1
+	}
+	if c := compareDesc(a.Score, b.Score); c != 0 {
+		return c
+	}
+	for _, tb := range r.TieBreakers {
+		var c int
+		switch {
+		case tb == TieBreakCoreFactor:
+			c = compareDesc(a.Result.CoreFactor, b.Result.CoreFactor)
+		case tb == TieBreakSecondaryFactor:
+			c = compareDesc(a.Result.SecondaryFactor, b.Result.SecondaryFactor)
+		case tb == TieBreakNIK:
+			c = strings.Compare(a.NIK, b.NIK)
+		default:
+			code := strings.TrimPrefix(tb, TieBreakKriteriaPrefix)
+			c = compareDesc(kriteriaWeight(a.Result, code), kriteriaWeight(b.Result, code))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// assignRanks numbers candidates already sorted by compare
+func (r RankingRule) assignRanks(ranked []RankedCandidate) {
+	for i := range ranked {
+		switch {
+		case i == 0:
+			ranked[i].Rank = 1
+		case r.Style == RankOrdinal || r.compare(ranked[i-1], ranked[i]) != 0:
+			if r.Style == RankDense {
+				ranked[i].Rank = ranked[i-1].Rank + 1
+			} else {
+				ranked[i].Rank = i + 1
+			}
+		default:
+			ranked[i].Rank = ranked[i-1].Rank
+		}
+	}
+}
+
+// compareDesc orders higher values first, treating values within Epsilon as
+// equal
+func compareDesc(a, b float64) int {
+	switch {
+	case math.Abs(a-b) <= Epsilon:
+		return 0
+	case a > b:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// kriteriaWeight returns the GAP weight of the kriteria with the code, or -1
+// when it was not evaluated so it ranks below any weight
+func kriteriaWeight(result Result, code string) float64 {
+	for _, a := range result.Aspek {
+		for _, k := range a.Kriteria {
+			if k.Kriteria.Kode == code {
+				return k.Weight
+			}
+		}
+	}
+	return -1
+}
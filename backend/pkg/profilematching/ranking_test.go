@@ -0,0 +1,65 @@
+package profilematching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRankingRule(t *testing.T) {
+	rule, err := NewRankingRule("", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, RankOrdinal, rule.Style)
+
+	rule, err = NewRankingRule(RankDense, []string{TieBreakCoreFactor, "kriteria:K1", TieBreakNIK})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"K1"}, rule.KriteriaCodes())
+
+	_, err = NewRankingRule("olympic", nil)
+	assert.EqualError(t, err, "invalid ranking style")
+	_, err = NewRankingRule(RankOrdinal, []string{"kriteria:"})
+	assert.EqualError(t, err, "invalid tie breaker")
+	_, err = NewRankingRule(RankOrdinal, []string{"age"})
+	assert.EqualError(t, err, "invalid tie breaker")
+}
+
+func TestRank_Styles(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Nama: "Teknis", Persentase: 100}, 1, 1)
+	// Candidates 1 and 2 tie on score: 1 is better on K1, 2 on K2
+	candidates := []Candidate{
+		{ID: 2, NIK: "100", Nilai: map[uint]float64{1: 2, 2: 3}},
+		{ID: 1, NIK: "200", Nilai: map[uint]float64{1: 3, 2: 2}},
+		{ID: 3, NIK: "300", Nilai: map[uint]float64{1: 3, 2: 3}},
+		{ID: 4, NIK: "400", Nilai: map[uint]float64{1: 1, 2: 1}},
+	}
+
+	rank := func(style string, tieBreakers ...string) ([]uint, []int) {
+		rule, err := NewRankingRule(style, tieBreakers)
+		assert.NoError(t, err)
+		var ids []uint
+		var ranks []int
+		for _, r := range newTestEngine(DefaultRatio, MissingPolicy{Policy: "skip"}).WithRanking(rule).Rank(profile, candidates) {
+			ids = append(ids, r.ID)
+			ranks = append(ranks, r.Rank)
+		}
+		return ids, ranks
+	}
+
+	// Ties are ordered by candidate ID
+	ids, ranks := rank(RankOrdinal)
+	assert.Equal(t, []uint{3, 1, 2, 4}, ids)
+	assert.Equal(t, []int{1, 2, 3, 4}, ranks)
+
+	_, ranks = rank(RankCompetition)
+	assert.Equal(t, []int{1, 2, 2, 4}, ranks)
+
+	_, ranks = rank(RankDense)
+	assert.Equal(t, []int{1, 2, 2, 3}, ranks)
+
+	ids, ranks = rank(RankCompetition, "kriteria:K2")
+	assert.Equal(t, []uint{3, 2, 1, 4}, ids)
+	assert.Equal(t, []int{1, 2, 3, 4}, ranks)
+
+	ids, _ = rank(RankCompetition, TieBreakCoreFactor, TieBreakNIK)
+	assert.Equal(t, []uint{3, 2, 1, 4}, ids)
+}
@@ -0,0 +1,222 @@
+package profilematching
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// MaxSensitivityScenarios caps the number of perturbed rankings computed by
+// a single sensitivity analysis
+const MaxSensitivityScenarios = 1000
+
+// Range is an inclusive range of values stepped from Min to Max. A zero Step
+// is only valid when Min equals Max.
+type Range struct {
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+// Values returns the values of the range, rounded to the decimal(5,2)
+// precision of the stored inputs
+func (r Range) Values() ([]float64, error) {
+	if r.Min > r.Max || r.Step < 0 || (r.Step == 0 && r.Min != r.Max) {
+		return nil, errors.New("invalid perturbation range")
+	}
+	if r.Step == 0 {
+		return []float64{r.Min}, nil
+	}
+	count := int(math.Floor((r.Max-r.Min)/r.Step+Epsilon)) + 1
+	if count > MaxSensitivityScenarios {
+		return nil, errors.New("too many sensitivity scenarios")
+	}
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = math.Round((r.Min+float64(i)*r.Step)*100) / 100
+	}
+	return values, nil
+}
+
+// Sensitivity lists the inputs to perturb. Every combination of the values
+// is ranked once. A CoreFactorPersen range replaces the jabatan's ratio and
+// any aspek overrides, with the secondary factor taking the remainder.
+type Sensitivity struct {
+	AspekPersentase  map[uint]Range
+	CoreFactorPersen *Range
+	TargetNilai      map[uint]Range
+	TopN             int
+}
+
+// CandidateSensitivity summarizes a candidate's rank over all scenarios
+type CandidateSensitivity struct {
+	ID       uint
+	BaseRank int
+	MinRank  int
+	MaxRank  int
+	// TopNCount is the number of scenarios ranking the candidate in the top N
+	TopNCount     int
+	TopNFrequency float64
+}
+
+type SensitivityReport struct {
+	Scenarios  int
+	TopN       int
+	Candidates []CandidateSensitivity
+}
+
+// sensitivityDimension is one perturbed input with the values it takes
+type sensitivityDimension struct {
+	values []float64
+	apply  func(profile *Profile, engine *Engine, value float64)
+}
+
+// Sensitivity ranks the candidates once per combination of perturbed inputs
+// and reports, per candidate, the range of ranks and how often it stays in
+// the top N. The base rank comes from the unperturbed profile.
+func (e *Engine) Sensitivity(profile Profile, candidates []Candidate, s Sensitivity) (SensitivityReport, error) {
+	if s.TopN <= 0 {
+		return SensitivityReport{}, errors.New("top n must be positive")
+	}
+
+	dimensions, err := sensitivityDimensions(s)
+	if err != nil {
+		return SensitivityReport{}, err
+	}
+
+	scenarios := 1
+	for _, d := range dimensions {
+		scenarios *= len(d.values)
+		if scenarios > MaxSensitivityScenarios {
+			return SensitivityReport{}, errors.New("too many sensitivity scenarios")
+		}
+	}
+
+	report := SensitivityReport{Scenarios: scenarios, TopN: s.TopN}
+	index := make(map[uint]int)
+	for _, r := range e.Rank(profile, candidates) {
+		index[r.ID] = len(report.Candidates)
+		report.Candidates = append(report.Candidates, CandidateSensitivity{ID: r.ID, BaseRank: r.Rank, MinRank: r.Rank, MaxRank: r.Rank})
+	}
+
+	// Walk every combination of dimension values like an odometer
+	positions := make([]int, len(dimensions))
+	for n := 0; n < scenarios; n++ {
+		scenarioProfile := profile.clone()
+		scenarioEngine := *e
+		for i, d := range dimensions {
+			d.apply(&scenarioProfile, &scenarioEngine, d.values[positions[i]])
+		}
+
+		for _, r := range scenarioEngine.Rank(scenarioProfile, candidates) {
+			idx, exists := index[r.ID]
+			if !exists {
+				continue
+			}
+			c := &report.Candidates[idx]
+			if r.Rank < c.MinRank {
+				c.MinRank = r.Rank
+			}
+			if r.Rank > c.MaxRank {
+				c.MaxRank = r.Rank
+			}
+			if r.Rank <= s.TopN {
+				c.TopNCount++
+			}
+		}
+
+		for i := range positions {
+			positions[i]++
+			if positions[i] < len(dimensions[i].values) {
+				break
+			}
+			positions[i] = 0
+		}
+	}
+
+	for i := range report.Candidates {
+		report.Candidates[i].TopNFrequency = float64(report.Candidates[i].TopNCount) / float64(scenarios)
+	}
+	return report, nil
+}
+
+func sensitivityDimensions(s Sensitivity) ([]sensitivityDimension, error) {
+	var dimensions []sensitivityDimension
+
+	for _, aspekID := range sortedKeys(s.AspekPersentase) {
+		values, err := s.AspekPersentase[aspekID].Values()
+		if err != nil {
+			return nil, err
+		}
+		id := aspekID
+		dimensions = append(dimensions, sensitivityDimension{values: values, apply: func(p *Profile, _ *Engine, v float64) {
+			aspek := p.Aspek[id]
+			aspek.Persentase = v
+			p.Aspek[id] = aspek
+		}})
+	}
+
+	if s.CoreFactorPersen != nil {
+		values, err := s.CoreFactorPersen.Values()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			if err := (Ratio{Core: v, Secondary: 100 - v}).Validate(); err != nil {
+				return nil, err
+			}
+		}
+		dimensions = append(dimensions, sensitivityDimension{values: values, apply: func(p *Profile, e *Engine, v float64) {
+			e.ratio = Ratio{Core: v, Secondary: 100 - v}
+			for id, aspek := range p.Aspek {
+				aspek.Ratio = nil
+				p.Aspek[id] = aspek
+			}
+		}})
+	}
+
+	for _, kriteriaID := range sortedKeys(s.TargetNilai) {
+		values, err := s.TargetNilai[kriteriaID].Values()
+		if err != nil {
+			return nil, err
+		}
+		id := kriteriaID
+		dimensions = append(dimensions, sensitivityDimension{values: values, apply: func(p *Profile, _ *Engine, v float64) {
+			for i := range p.Targets {
+				if p.Targets[i].KriteriaID == id {
+					p.Targets[i].Nilai = v
+				}
+			}
+		}})
+	}
+
+	return dimensions, nil
+}
+
+// clone copies the profile so a scenario can change it without affecting
+// the others
+func (p Profile) clone() Profile {
+	c := Profile{
+		Targets:      make([]Target, len(p.Targets)),
+		Kriteria:     make(map[uint]Kriteria, len(p.Kriteria)),
+		Aspek:        make(map[uint]Aspek, len(p.Aspek)),
+		MinimumScore: p.MinimumScore,
+	}
+	copy(c.Targets, p.Targets)
+	for id, k := range p.Kriteria {
+		c.Kriteria[id] = k
+	}
+	for id, a := range p.Aspek {
+		c.Aspek[id] = a
+	}
+	return c
+}
+
+func sortedKeys(m map[uint]Range) []uint {
+	keys := make([]uint, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,82 @@
+package profilematching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange_Values(t *testing.T) {
+	values, err := Range{Min: 2, Max: 3, Step: 0.5}.Values()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{2, 2.5, 3}, values)
+
+	values, err = Range{Min: 0.1, Max: 0.3, Step: 0.1}.Values()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.1, 0.2, 0.3}, values)
+
+	_, err = Range{Min: 3, Max: 2, Step: 1}.Values()
+	assert.Error(t, err)
+	_, err = Range{Min: 2, Max: 3}.Values()
+	assert.Error(t, err)
+}
+
+func TestSensitivity(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Persentase: 100}, 1, 1)
+	profile.Kriteria[2] = Kriteria{ID: 2, AspekID: 1, Kode: "K2", IsCore: false, Bobot: 1}
+	// Candidate 1 is strong on the core kriteria, candidate 2 on the secondary
+	candidates := []Candidate{
+		{ID: 1, Nilai: map[uint]float64{1: 3, 2: 1}},
+		{ID: 2, Nilai: map[uint]float64{1: 2, 2: 3}},
+	}
+	engine := newTestEngine(DefaultRatio, MissingPolicy{Policy: "skip"})
+
+	report, err := engine.Sensitivity(profile, candidates, Sensitivity{
+		CoreFactorPersen: &Range{Min: 20, Max: 80, Step: 30},
+		TopN:             1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Scenarios)
+
+	// 60/40 base: 1 scores 0.6*5+0.4*3=4.2, 2 scores 0.6*4+0.4*5=4.4
+	first := report.Candidates[0]
+	assert.Equal(t, uint(2), first.ID)
+	assert.Equal(t, 1, first.BaseRank)
+	assert.Equal(t, 1, first.MinRank)
+	assert.Equal(t, 2, first.MaxRank)
+	assert.Equal(t, 2, first.TopNCount)
+	assert.InDelta(t, 2.0/3, first.TopNFrequency, 1e-9)
+
+	_, err = engine.Sensitivity(profile, candidates, Sensitivity{
+		AspekPersentase: map[uint]Range{1: {Min: 0, Max: 100, Step: 0.01}},
+		TargetNilai:     map[uint]Range{1: {Min: 1, Max: 5, Step: 0.01}},
+		TopN:            1,
+	})
+	assert.Error(t, err)
+}
+
+func TestSensitivity_RankingRule(t *testing.T) {
+	profile := singleAspekProfile(Aspek{ID: 1, Persentase: 100}, 1, 1)
+	// Candidates 1 and 2 tie on score
+	candidates := []Candidate{
+		{ID: 1, Nilai: map[uint]float64{1: 3, 2: 2}},
+		{ID: 2, Nilai: map[uint]float64{1: 3, 2: 2}},
+		{ID: 3, Nilai: map[uint]float64{1: 1, 2: 1}},
+	}
+	rule, err := NewRankingRule(RankCompetition, nil)
+	assert.NoError(t, err)
+	engine := newTestEngine(DefaultRatio, MissingPolicy{Policy: "skip"}).WithRanking(rule)
+
+	report, err := engine.Sensitivity(profile, candidates, Sensitivity{
+		TargetNilai: map[uint]Range{2: {Min: 2, Max: 3, Step: 1}},
+		TopN:        1,
+	})
+	assert.NoError(t, err)
+	// Both tied candidates share rank 1 in every scenario, as in the stored ranking
+	for _, c := range report.Candidates[:2] {
+		assert.Equal(t, 1, c.BaseRank)
+		assert.Equal(t, 1, c.MaxRank)
+		assert.Equal(t, 2, c.TopNCount)
+	}
+	assert.Equal(t, 3, report.Candidates[2].BaseRank)
+}
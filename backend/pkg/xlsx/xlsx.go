@@ -0,0 +1,180 @@
+// Package xlsx writes single-sheet Office Open XML workbooks with the
+// standard library. Rows are streamed to the sheet as they are written, so
+// large tables are not held in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of an XLSX workbook
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Cell is a string or a number. Numbers are stored and displayed rounded to
+// Decimals decimal places (0 to 4).
+type Cell struct {
+	String   string
+	Number   float64
+	IsNumber bool
+	Decimals int
+}
+
+// String returns a text cell
+func String(s string) Cell {
+	return Cell{String: s}
+}
+
+// Number returns a numeric cell displayed with the given decimal places
+func Number(v float64, decimals int) Cell {
+	return Cell{Number: v, IsNumber: true, Decimals: decimals}
+}
+
+// Writer writes a workbook with one sheet. Close must be called to finish
+// the workbook.
+type Writer struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	rows  int
+	err   error
+}
+
+// NewWriter starts a workbook whose sheet is named sheetName; the name must
+// be a valid sheet name of at most 31 characters without []:*?/\
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", strings.Replace(workbook, "{{sheet}}", escape(sheetName), 1)},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, xml.Header+part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	// The sheet is the last part, so it can be streamed until Close
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, xml.Header+sheetStart); err != nil {
+		return nil, err
+	}
+	return &Writer{zw: zw, sheet: sheet}, nil
+}
+
+// WriteRow appends a row to the sheet
+func (w *Writer) WriteRow(cells []Cell) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.rows++
+	var b strings.Builder
+	b.WriteString(`<row r="` + strconv.Itoa(w.rows) + `">`)
+	for i, c := range cells {
+		ref := columnName(i) + strconv.Itoa(w.rows)
+		if c.IsNumber {
+			if c.Decimals < 0 || c.Decimals > 4 {
+				w.err = errors.New("xlsx: decimals must be between 0 and 4")
+				return w.err
+			}
+			// Styles 1-5 display 0 to 4 decimals
+			b.WriteString(`<c r="` + ref + `" s="` + strconv.Itoa(c.Decimals+1) + `"><v>`)
+			b.WriteString(strconv.FormatFloat(c.Number, 'f', c.Decimals, 64))
+			b.WriteString(`</v></c>`)
+		} else {
+			b.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+			b.WriteString(escape(c.String))
+			b.WriteString(`</t></is></c>`)
+		}
+	}
+	b.WriteString(`</row>`)
+	_, w.err = io.WriteString(w.sheet, b.String())
+	return w.err
+}
+
+// Close finishes the sheet and the workbook; it does not close the
+// underlying writer
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if _, err := io.WriteString(w.sheet, sheetEnd); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// columnName returns the column letters of a 0-based index: A..Z, AA..
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const contentTypes = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const rootRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="{{sheet}}" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const workbookRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// styles defines cell style 0 as general and styles 1-5 as numbers with 0
+// to 4 decimals
+const styles = `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="3">` +
+	`<numFmt numFmtId="164" formatCode="0.0"/>` +
+	`<numFmt numFmtId="165" formatCode="0.000"/>` +
+	`<numFmt numFmtId="166" formatCode="0.0000"/>` +
+	`</numFmts>` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="6">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="1" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="166" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
+
+const sheetStart = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetEnd = `</sheetData></worksheet>`
@@ -0,0 +1,66 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Ranking")
+	assert.NoError(t, err)
+	assert.NoError(t, w.WriteRow([]Cell{String("nama"), String("total_score")}))
+	assert.NoError(t, w.WriteRow([]Cell{String("Ani & <Budi>"), Number(4.456, 2)}))
+	assert.NoError(t, w.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(body)
+
+		// Every part is well-formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(body))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err, f.Name)
+			if err != nil {
+				break
+			}
+		}
+	}
+
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts, "xl/styles.xml")
+	assert.Contains(t, parts["xl/workbook.xml"], `name="Ranking"`)
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A2" t="inlineStr"><is><t xml:space="preserve">Ani &amp; &lt;Budi&gt;</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="B2" s="3"><v>4.46</v></c>`)
+}
+
+func TestWriter_InvalidDecimals(t *testing.T) {
+	w, err := NewWriter(io.Discard, "Sheet1")
+	assert.NoError(t, err)
+	assert.Error(t, w.WriteRow([]Cell{Number(1, 5)}))
+	assert.Error(t, w.Close())
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
@@ -22,7 +22,7 @@ func TestCreateJabatan(t *testing.T) {
 
 	// Setup controller
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	jabatanSvc := services.NewJabatanService(jabatanRepo)
+	jabatanSvc := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	jabatanCtrl := controllers.NewJabatanController(jabatanSvc)
 
 	gin.SetMode(gin.TestMode)
@@ -84,7 +84,7 @@ func TestGetJabatan(t *testing.T) {
 
 	// Setup controller
 	jabatanRepo := repositories.NewJabatanRepository(db)
-	jabatanSvc := services.NewJabatanService(jabatanRepo)
+	jabatanSvc := services.NewJabatanService(jabatanRepo, repositories.NewGapWeightTableRepository(db))
 	jabatanCtrl := controllers.NewJabatanController(jabatanSvc)
 
 	gin.SetMode(gin.TestMode)
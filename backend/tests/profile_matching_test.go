@@ -35,6 +35,9 @@ func TestProfileMatching(t *testing.T) {
 		tenagaKerjaRepo,
 		profileMatchResultRepo,
 		jabatanRepo,
+		repositories.NewGapWeightTableRepository(db),
+		repositories.NewCalculationRunRepository(db),
+		repositories.NewResultStatusHistoryRepository(db),
 	)
 
 	// Setup controller